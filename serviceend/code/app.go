@@ -5,12 +5,15 @@ import (
 	"dataPanel/serviceend/code/internal"
 	"dataPanel/serviceend/global"
 	"dataPanel/serviceend/model/configModel"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/service"
 	"dataPanel/serviceend/utils"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/energye/systray"
@@ -39,8 +42,42 @@ func (a *App) load() {
 	//1.加载读取配置文件内容
 	global.GavVp = Viper() // 初始化Viper 读取yaml配置文件
 	InitZap()
+	//数据加密密钥与令牌签名密钥
+	InitSecretKey()
 	//参数初始化校验翻译器
 	InitTrans("zh")
+	//数据库连接
+	global.GvaDb = InitGorm()
+	//数据库迁移 需在路由注册前完成
+	InitMigration()
+	//加载已注销的令牌
+	if err := service.ServiceGroupApp.JwtService.LoadBlacklist(); err != nil {
+		global.GvaLog.Error("令牌黑名单加载失败", zap.Error(err))
+	}
+	//加载登录会话
+	if err := service.ServiceGroupApp.SessionService.LoadSessions(); err != nil {
+		global.GvaLog.Error("登录会话加载失败", zap.Error(err))
+	}
+	//重建定时公告
+	if err := service.ServiceGroupApp.BulletinService.LoadSchedules(); err != nil {
+		global.GvaLog.Error("公告定时任务加载失败", zap.Error(err))
+	}
+	//清理过期的分片上传
+	if err := service.ServiceGroupApp.FileService.CleanChunks(); err != nil {
+		global.GvaLog.Error("分片上传清理失败", zap.Error(err))
+	}
+	//清理过期验证码
+	if err := service.ServiceGroupApp.VerificationService.CleanExpired(); err != nil {
+		global.GvaLog.Error("验证码清理失败", zap.Error(err))
+	}
+	//启动定时任务调度
+	if err := service.ServiceGroupApp.JobService.Start(); err != nil {
+		global.GvaLog.Error("定时任务调度启动失败", zap.Error(err))
+	}
+	//加载告警规则 需在定时任务调度启动之后
+	if err := service.ServiceGroupApp.AlertService.LoadRules(); err != nil {
+		global.GvaLog.Error("告警规则加载失败", zap.Error(err))
+	}
 	//路由配置
 	engine := CreateGinServer()
 	address := fmt.Sprintf(":%d", global.GvaConfig.System.Addr)
@@ -56,6 +93,9 @@ func InitZap() {
 	if ok, _ := utils.PathExists(global.GvaConfig.Zap.Director); !ok { // 判断是否有Director文件夹
 		_ = os.Mkdir(global.GvaConfig.Zap.Director, os.ModePerm)
 	}
+	if global.GvaLog == nil {
+		global.GvaLogLevel.SetLevel(global.GvaConfig.Zap.TransportLevel())
+	}
 
 	cores := internal.Zap.GetZapCores()
 	logged := zap.New(zapcore.NewTee(cores...))
@@ -67,6 +107,23 @@ func InitZap() {
 	global.GvaLog = logged
 }
 
+// ReloadZap 配置文件变化后应用日志配置
+// 级别变化时直接修改并取消自动恢复, 输出格式、控制台输出等变化时重建日志
+func ReloadZap(old *configModel.Zap) {
+	cur := global.GvaConfig.Zap
+	if old == nil || cur == nil {
+		return
+	}
+	if !strings.EqualFold(old.Level, cur.Level) {
+		service.ServiceGroupApp.LogService.ResetLevel()
+	}
+	if old.Format != cur.Format || old.LogInConsole != cur.LogInConsole || old.EncodeLevel != cur.EncodeLevel ||
+		old.Prefix != cur.Prefix || old.StacktraceKey != cur.StacktraceKey || old.ShowLine != cur.ShowLine || old.Director != cur.Director {
+		InitZap()
+		global.GvaLog.Info("日志配置已重新加载", zap.String("format", cur.Format), zap.Bool("logInConsole", cur.LogInConsole))
+	}
+}
+
 func (a *App) System() *configModel.System {
 	return global.GvaConfig.System
 }
@@ -83,15 +140,42 @@ func (a *App) SetCtx(ctx context.Context) *App {
 // Startup wails 生命周期
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
+	//注册前端事件推送
+	service.ServiceGroupApp.EventService.SetEmitter(func(eventName string, optionalData ...interface{}) {
+		runtime.EventsEmit(ctx, eventName, optionalData...)
+	})
+	//启动组件自动刷新 结果通过事件推送 需在注册事件推送之后
+	if err := service.ServiceGroupApp.WidgetRefreshService.Start(); err != nil {
+		global.GvaLog.Error("组件自动刷新启动失败", zap.Error(err))
+	}
 	//设置状态栏菜单
 	InitSystray(func() {
 		mainMenuItem := systray.AddMenuItem("主页面", "显示主页面")
 		mainMenuItem.Click(func() {
 			runtime.WindowShow(ctx)
+			service.ServiceGroupApp.WidgetRefreshService.Resume()
+		})
+		alertMenuItem := systray.AddMenuItem("告警", "查看未确认的告警")
+		alertMenuItem.Click(func() {
+			runtime.WindowShow(ctx)
+			service.ServiceGroupApp.WidgetRefreshService.Resume()
+			service.ServiceGroupApp.EventService.Emit(service.EventAlertOpen)
 		})
+		//告警触发时弹出系统通知 状态栏显示未确认数量
+		service.ServiceGroupApp.AlertService.SetNotifier(func(event *dbModel.SysAlertEvent, unacked int64) {
+			a.alertTitle(alertMenuItem, unacked)
+			if event != nil {
+				a.toast(event.RuleName, event.Message)
+			}
+		})
+		if unacked, err := service.ServiceGroupApp.AlertService.Unacked(); err == nil {
+			a.alertTitle(alertMenuItem, unacked)
+		}
 		hide := systray.AddMenuItem("隐藏", "隐藏应用程序")
 		hide.Click(func() {
 			runtime.WindowHide(a.ctx)
+			//窗口隐藏期间暂停组件自动刷新
+			service.ServiceGroupApp.WidgetRefreshService.Pause()
 		})
 		systray.AddSeparator()
 		quitMenuItem := systray.AddMenuItem("退出", "退出程序")
@@ -132,6 +216,13 @@ func (a *App) Shutdown(ctx context.Context) {
 	if err := a.srv.Shutdown(ctx2); err != nil {
 		global.GvaLog.Error("后台服务关闭异常", zap.Error(err))
 	}
+	service.ServiceGroupApp.JobService.Stop()
+	service.ServiceGroupApp.NotifyService.Close()
+	service.ServiceGroupApp.WidgetRefreshService.Stop()
+	service.ServiceGroupApp.LogService.StopTail()
+	service.ServiceGroupApp.QueryService.CancelAll()
+	service.ServiceGroupApp.DataSourceService.CloseAll()
+	CloseGorm()
 }
 
 // DomReady is called after the front-end dom has been loaded
@@ -148,10 +239,16 @@ func (a *App) BeforeClose(ctx context.Context) bool {
 
 // OnSecondInstanceLaunch 应用重复启动
 func (a *App) OnSecondInstanceLaunch(secondInstanceData options.SecondInstanceData) {
+	a.toast(global.GvaConfig.System.ApplicationName, "程序已经在运行了")
+	time.Sleep(time.Second * 3)
+}
+
+// toast 弹出系统通知
+func (a *App) toast(title, message string) {
 	notification := toast.Notification{
 		AppID:    global.GvaConfig.System.ApplicationName,
-		Title:    global.GvaConfig.System.ApplicationName,
-		Message:  "程序已经在运行了",
+		Title:    title,
+		Message:  message,
 		Icon:     "",
 		Duration: "short",
 		Audio:    toast.Default,
@@ -160,7 +257,15 @@ func (a *App) OnSecondInstanceLaunch(secondInstanceData options.SecondInstanceDa
 	if err != nil {
 		global.GvaLog.Error("服务异常", zap.Error(err))
 	}
-	time.Sleep(time.Second * 3)
+}
+
+// alertTitle 状态栏告警菜单显示未确认数量
+func (a *App) alertTitle(item *systray.MenuItem, unacked int64) {
+	if unacked > 0 {
+		item.SetTitle(fmt.Sprintf("告警 (%d)", unacked))
+		return
+	}
+	item.SetTitle("告警")
 }
 
 // InitSystray 状态栏图标设置
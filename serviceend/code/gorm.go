@@ -0,0 +1,131 @@
+package code
+
+import (
+	"dataPanel/serviceend/code/internal"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"dataPanel/serviceend/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	DbTypeMysql  = "mysql"
+	DbTypePgsql  = "postgresql"
+	DbTypeSqlite = "sqlite"
+)
+
+// InitGorm 根据 system.db-type 初始化数据库连接
+func InitGorm() *gorm.DB {
+	dbType := strings.ToLower(global.GvaConfig.System.DbType)
+	if global.GvaConfig.Db == nil {
+		panic(fmt.Errorf("数据库初始化失败: 缺少 db 配置"))
+	}
+	var (
+		db  *gorm.DB
+		err error
+	)
+	switch dbType {
+	case DbTypeMysql, "":
+		db, err = GormMysql(global.GvaConfig.Db.Mysql)
+	case DbTypePgsql, "postgres", "pgsql":
+		db, err = GormPgsql(global.GvaConfig.Db.Pgsql)
+	case DbTypeSqlite:
+		db, err = GormSqlite(global.GvaConfig.Db.Sqlite)
+	default:
+		err = fmt.Errorf("暂不支持的数据库类型: %s", dbType)
+	}
+	if err != nil {
+		global.GvaLog.Error("数据库初始化失败", zap.String("db-type", dbType), zap.Error(err))
+		panic(fmt.Errorf("数据库初始化失败: %w", err))
+	}
+	global.GvaLog.Info("数据库连接成功", zap.String("db-type", dbType))
+	return db
+}
+
+// GormMysql 初始化 mysql 数据库
+func GormMysql(m configModel.Mysql) (*gorm.DB, error) {
+	if m.DbName == "" {
+		return nil, errors.New("mysql 未配置 db-name")
+	}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       m.Dsn(),
+		DefaultStringSize:         191,   // string 类型字段的默认长度
+		SkipInitializeWithVersion: false, // 根据版本自动配置
+	}), internal.Gorm.Config(m.LogMode))
+	if err != nil {
+		return nil, err
+	}
+	return db, setupPool(db, m.GeneralDB)
+}
+
+// GormPgsql 初始化 postgresql 数据库
+func GormPgsql(p configModel.Pgsql) (*gorm.DB, error) {
+	if p.DbName == "" {
+		return nil, errors.New("postgresql 未配置 db-name")
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  p.Dsn(),
+		PreferSimpleProtocol: false,
+	}), internal.Gorm.Config(p.LogMode))
+	if err != nil {
+		return nil, err
+	}
+	return db, setupPool(db, p.GeneralDB)
+}
+
+// GormSqlite 初始化 sqlite 数据库 纯go实现,桌面端无需额外安装数据库服务
+func GormSqlite(s configModel.Sqlite) (*gorm.DB, error) {
+	if s.DbName == "" {
+		s.DbName = global.GvaConfig.System.ApplicationName
+	}
+	if s.Path != "" {
+		if err := utils.CreateDir(s.Path); err != nil {
+			return nil, err
+		}
+	}
+	db, err := gorm.Open(sqlite.Open(s.Dsn()), internal.Gorm.Config(s.LogMode))
+	if err != nil {
+		return nil, err
+	}
+	return db, setupPool(db, s.GeneralDB)
+}
+
+// setupPool 设置连接池参数并检测连接是否可用
+func setupPool(db *gorm.DB, general configModel.GeneralDB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxIdleConns(general.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(general.MaxOpenConns)
+	if general.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(time.Duration(general.ConnMaxLifetime) * time.Second)
+	}
+	return sqlDB.Ping()
+}
+
+// CloseGorm 关闭数据库连接
+func CloseGorm() {
+	if global.GvaDb == nil {
+		return
+	}
+	sqlDB, err := global.GvaDb.DB()
+	if err != nil {
+		global.GvaLog.Error("获取数据库连接异常", zap.Error(err))
+		return
+	}
+	if err = sqlDB.Close(); err != nil {
+		global.GvaLog.Error("数据库连接关闭异常", zap.Error(err))
+		return
+	}
+	global.GvaLog.Info("数据库连接已关闭")
+}
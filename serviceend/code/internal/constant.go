@@ -6,4 +6,10 @@ const (
 	ConfigTestFile    = "config.test.yaml"
 	ConfigDebugFile   = "config.debug.yaml"
 	ConfigReleaseFile = "config.release.yaml"
+
+	SecretKeyFile    = "secret.key"                 // system.secret-key 为空时自动生成的密钥文件
+	SecretKeyDefault = "5d0c8e7a-data-panel-secret" // 早期版本配置文件中的默认密钥 不允许使用
+
+	SigningKeyFile    = "signing.key"                        // jwt.signing-key 为空时自动生成的签名密钥文件
+	SigningKeyDefault = "a3f1c2d9-6b7e-4c5a-9e8f-data-panel" // 早期版本配置文件中的默认签名密钥 不允许使用
 )
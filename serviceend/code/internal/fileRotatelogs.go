@@ -1,29 +0,0 @@
-package internal
-
-import (
-	"dataPanel/serviceend/global"
-	"os"
-	"path"
-	"time"
-
-	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
-	"go.uber.org/zap/zapcore"
-)
-
-var FileRotatelogs = new(fileRotatelogs)
-
-type fileRotatelogs struct{}
-
-// GetWriteSyncer 获取 zapcore.WriteSyncer 分割文件
-func (r *fileRotatelogs) GetWriteSyncer(level string) (zapcore.WriteSyncer, error) {
-	fileWriter, err := rotatelogs.New(
-		path.Join(global.GvaConfig.Zap.Director, "%Y-%m-%d", level+".log"),
-		rotatelogs.WithClock(rotatelogs.Local),
-		rotatelogs.WithMaxAge(time.Duration(global.GvaConfig.Zap.MaxAge)*24*time.Hour), // 日志留存时间
-		rotatelogs.WithRotationTime(time.Hour*24),
-	)
-	if global.GvaConfig.Zap.LogInConsole {
-		return zapcore.NewMultiWriteSyncer(zapcore.AddSync(os.Stdout), zapcore.AddSync(fileWriter)), err
-	}
-	return zapcore.AddSync(fileWriter), err
-}
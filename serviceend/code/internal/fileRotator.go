@@ -0,0 +1,338 @@
+package internal
+
+import (
+	"compress/gzip"
+	"dataPanel/serviceend/global"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// rotateDateLayout 日志按日期分目录 如 log/2024-01-02/info.log
+const rotateDateLayout = "2006-01-02"
+
+var FileRotator = new(fileRotator)
+
+type fileRotator struct{}
+
+// rotateState 各级别日志文件共用的状态 清理在后台串行执行
+var rotateState = struct {
+	sync.Mutex
+	writers map[string]*rotateWriter // 各级别的日志文件 重建日志时复用
+	active  map[string]struct{}      // 正在写入的文件 清理时跳过
+	clean   chan struct{}
+}{writers: map[string]*rotateWriter{}, active: map[string]struct{}{}}
+
+// GetWriteSyncer 获取 zapcore.WriteSyncer 按天与大小分割文件 首次写入时创建文件
+// 分割出的文件按配置压缩为 .gz, 超过留存天数或总大小上限时删除最旧的文件
+func (r *fileRotator) GetWriteSyncer(level string) (zapcore.WriteSyncer, error) {
+	dir := global.GvaConfig.Zap.Director
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	rotateState.Lock()
+	writer, ok := rotateState.writers[level]
+	var old *rotateWriter
+	if !ok || writer.dir != dir {
+		old, writer = writer, &rotateWriter{dir: dir, level: level}
+		rotateState.writers[level] = writer
+	}
+	rotateState.Unlock()
+	// 日志目录变化 关闭原目录中的文件
+	if old != nil {
+		old.mu.Lock()
+		old.release()
+		old.mu.Unlock()
+	}
+	r.startCleaner()
+	return writer, nil
+}
+
+// rotateWriter 单个级别的日志文件
+type rotateWriter struct {
+	mu    sync.Mutex
+	dir   string
+	level string
+	date  string
+	path  string
+	file  *os.File
+	size  int64
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	switch {
+	case w.file == nil || now.Format(rotateDateLayout) != w.date:
+		if err := w.open(now); err != nil {
+			return 0, err
+		}
+	case w.maxSize() > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize():
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotateWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// open 打开当天的日志文件 跨天时关闭前一天的文件并交给后台压缩
+func (w *rotateWriter) open(now time.Time) error {
+	w.close()
+	date := now.Format(rotateDateLayout)
+	path := filepath.Join(w.dir, date, w.level+".log")
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	rotateState.Lock()
+	delete(rotateState.active, w.path)
+	rotateState.active[path] = struct{}{}
+	rotateState.Unlock()
+	w.file, w.path, w.date, w.size = file, path, date, info.Size()
+	FileRotator.notify()
+	return nil
+}
+
+// rotate 当前文件达到大小上限 重命名为 级别.时分秒.log 后重新打开
+// 重命名完成前文件仍标记为写入中 避免被后台压缩
+func (w *rotateWriter) rotate(now time.Time) error {
+	w.close()
+	base := strings.TrimSuffix(w.path, ".log") + "." + now.Format("150405")
+	target := base + ".log"
+	for n := 1; FileRotator.exists(target) || FileRotator.exists(target+".gz"); n++ {
+		target = base + "." + strconv.Itoa(n) + ".log"
+	}
+	if err := os.Rename(w.path, target); err != nil {
+		return err
+	}
+	return w.open(now)
+}
+
+// close 关闭当前文件 写入中的标记由 open 更新
+func (w *rotateWriter) close() {
+	if w.file == nil {
+		return
+	}
+	_ = w.file.Close()
+	w.file = nil
+}
+
+// release 关闭文件并取消写入中的标记 之后的写入重新打开当天的文件
+func (w *rotateWriter) release() {
+	w.close()
+	rotateState.Lock()
+	delete(rotateState.active, w.path)
+	rotateState.Unlock()
+}
+
+func (w *rotateWriter) maxSize() int64 {
+	return int64(global.GvaConfig.Zap.MaxSize) << 20
+}
+
+// startCleaner 启动后台清理 首次启动时立即清理一次
+func (r *fileRotator) startCleaner() {
+	rotateState.Lock()
+	defer rotateState.Unlock()
+	if rotateState.clean != nil {
+		return
+	}
+	rotateState.clean = make(chan struct{}, 1)
+	rotateState.clean <- struct{}{}
+	go func() {
+		for range rotateState.clean {
+			r.cleanup()
+		}
+	}()
+}
+
+// notify 请求清理 已有待执行的清理时合并
+func (r *fileRotator) notify() {
+	rotateState.Lock()
+	defer rotateState.Unlock()
+	if rotateState.clean == nil {
+		return
+	}
+	select {
+	case rotateState.clean <- struct{}{}:
+	default:
+	}
+}
+
+// rotatedFile 已分割的日志文件
+type rotatedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// cleanup 释放跨天后没有再写入的文件, 压缩已分割的文件, 删除超过留存天数的日期目录,
+// 总大小超过上限时从最旧的文件开始删除, 最后删除空的日期目录
+func (r *fileRotator) cleanup() {
+	cfg := global.GvaConfig.Zap
+	today := time.Now().Format(rotateDateLayout)
+	r.releaseStale(today)
+	dates, err := os.ReadDir(cfg.Director)
+	if err != nil {
+		return
+	}
+	var (
+		files []rotatedFile
+		total int64
+	)
+	for _, d := range dates {
+		day, err := time.ParseInLocation(rotateDateLayout, d.Name(), time.Local)
+		if !d.IsDir() || err != nil {
+			continue
+		}
+		dir := filepath.Join(cfg.Director, d.Name())
+		if cfg.MaxAge > 0 && d.Name() != today && time.Since(day) > time.Duration(cfg.MaxAge)*24*time.Hour {
+			_ = os.RemoveAll(dir)
+			continue
+		}
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			// 压缩中断留下的临时文件
+			if strings.HasSuffix(path, ".gz.tmp") {
+				_ = os.Remove(path)
+				continue
+			}
+			if e.IsDir() || r.isActive(path) {
+				if info, err := e.Info(); err == nil && !e.IsDir() {
+					total += info.Size()
+				}
+				continue
+			}
+			if cfg.Compress && strings.HasSuffix(path, ".log") {
+				if compressed, err := r.compress(path); err == nil {
+					path = compressed
+				}
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			files = append(files, rotatedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+			total += info.Size()
+		}
+	}
+	if limit := int64(cfg.MaxTotalSize) << 20; limit > 0 && total > limit {
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+		for _, f := range files {
+			if total <= limit {
+				break
+			}
+			if os.Remove(f.path) == nil {
+				total -= f.size
+			}
+		}
+	}
+	for _, d := range dates {
+		if !d.IsDir() || d.Name() == today {
+			continue
+		}
+		if _, err := time.Parse(rotateDateLayout, d.Name()); err != nil {
+			continue
+		}
+		dir := filepath.Join(cfg.Director, d.Name())
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+			_ = os.Remove(dir)
+		}
+	}
+}
+
+// releaseStale 关闭不是当天的文件 跨天后没有再写入的级别不再占用前一天的文件
+func (r *fileRotator) releaseStale(today string) {
+	rotateState.Lock()
+	writers := make([]*rotateWriter, 0, len(rotateState.writers))
+	for _, w := range rotateState.writers {
+		writers = append(writers, w)
+	}
+	rotateState.Unlock()
+	for _, w := range writers {
+		w.mu.Lock()
+		if w.file != nil && w.date != today {
+			w.release()
+		}
+		w.mu.Unlock()
+	}
+}
+
+// compress 压缩为 .gz 并删除原文件 返回压缩后的路径
+// 压缩文件沿用原文件的修改时间 总大小超限时仍按原文件的新旧删除
+func (r *fileRotator) compress(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+	target := path + ".gz"
+	dst, err := os.Create(target + ".tmp")
+	if err != nil {
+		return "", err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chtimes(target+".tmp", info.ModTime(), info.ModTime())
+	}
+	if err == nil {
+		err = os.Rename(target+".tmp", target)
+	}
+	if err != nil {
+		_ = os.Remove(target + ".tmp")
+		return "", err
+	}
+	_ = src.Close()
+	_ = os.Remove(path)
+	return target, nil
+}
+
+func (r *fileRotator) exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (r *fileRotator) isActive(path string) bool {
+	rotateState.Lock()
+	defer rotateState.Unlock()
+	_, ok := rotateState.active[path]
+	return ok
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// setupRotate 使用临时日志目录并停用后台清理 由测试直接调用 cleanup
+func setupRotate(t *testing.T, cfg configModel.Zap) string {
+	t.Helper()
+	cfg.Director = t.TempDir()
+	global.GvaConfig.Zap = &cfg
+	rotateState.Lock()
+	rotateState.writers, rotateState.active, rotateState.clean = map[string]*rotateWriter{}, map[string]struct{}{}, nil
+	rotateState.Unlock()
+	return cfg.Director
+}
+
+// writeLog 写入不可压缩的内容并设置修改时间
+func writeLog(t *testing.T, path string, size int, modTime time.Time) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	rand.New(rand.NewSource(int64(size))).Read(data)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRotateSize(t *testing.T) {
+	dir := setupRotate(t, configModel.Zap{MaxSize: 1})
+	w := &rotateWriter{dir: dir, level: "info"}
+	defer w.close()
+	today := filepath.Join(dir, time.Now().Format(rotateDateLayout))
+
+	chunk := bytes.Repeat([]byte("a"), 600<<10)
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rotated, _ := filepath.Glob(filepath.Join(today, "info.*.log"))
+	if len(rotated) != 1 || !regexp.MustCompile(`^info\.\d{6}\.log$`).MatchString(filepath.Base(rotated[0])) {
+		t.Fatalf("rotated files = %v", rotated)
+	}
+	if info, err := os.Stat(filepath.Join(today, "info.log")); err != nil || info.Size() != int64(len(chunk)) {
+		t.Fatalf("info.log = %v, %v", info, err)
+	}
+	if !FileRotator.isActive(filepath.Join(today, "info.log")) || FileRotator.isActive(rotated[0]) {
+		t.Error("only the current file should be active")
+	}
+
+	// 同一秒内多次分割 已存在的文件(含压缩后的)追加序号
+	now := time.Now()
+	at := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.Local)
+	writeLog(t, filepath.Join(today, "info.120000.log.gz"), 1, now)
+	for _, want := range []string{"info.120000.1.log", "info.120000.2.log"} {
+		if err := w.rotate(at); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(today, want)); err != nil {
+			t.Errorf("%s: %v", want, err)
+		}
+	}
+}
+
+func TestRotateCleanup(t *testing.T) {
+	dir := setupRotate(t, configModel.Zap{Compress: true, MaxTotalSize: 1})
+	now := time.Now()
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format(rotateDateLayout) }
+	const size = 300 << 10
+
+	writeLog(t, filepath.Join(dir, day(-3), "info.log"), size, now.Add(-72*time.Hour))
+	writeLog(t, filepath.Join(dir, day(-2), "info.log"), size+1, now.Add(-48*time.Hour))
+	active := filepath.Join(dir, day(0), "info.log")
+	writeLog(t, active, size+2, now)
+	hourAgo := now.Add(-time.Hour).Truncate(time.Second)
+	rotated := writeLog(t, filepath.Join(dir, day(0), "info.100000.log"), size+3, hourAgo)
+	writeLog(t, filepath.Join(dir, day(0), "error.log.gz.tmp"), 10, now)
+	for _, d := range []string{day(-5), "other"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rotateState.Lock()
+	rotateState.active[active] = struct{}{}
+	rotateState.Unlock()
+
+	FileRotator.cleanup()
+
+	// 总大小超过 1M 只删除最旧的一个文件, 随后删除空的日期目录
+	for path, exists := range map[string]bool{
+		filepath.Join(dir, day(-3)):                false,
+		filepath.Join(dir, day(-5)):                false,
+		filepath.Join(dir, "other"):                true,
+		filepath.Join(dir, day(-2), "info.log"):    false,
+		filepath.Join(dir, day(-2), "info.log.gz"): true,
+		active:         true,
+		active + ".gz": false,
+		filepath.Join(dir, day(0), "info.100000.log"):    false,
+		filepath.Join(dir, day(0), "info.100000.log.gz"): true,
+		filepath.Join(dir, day(0), "error.log.gz.tmp"):   false,
+	} {
+		if _, err := os.Stat(path); (err == nil) != exists {
+			t.Errorf("%s exists = %v, want %v", path, err == nil, exists)
+		}
+	}
+
+	// 压缩内容完整 并沿用原文件的修改时间
+	gzPath := filepath.Join(dir, day(0), "info.100000.log.gz")
+	f, err := os.Open(gzPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(gz); err != nil || !bytes.Equal(data, rotated) {
+		t.Errorf("decompressed %d bytes, err = %v", len(data), err)
+	}
+	if info, err := os.Stat(gzPath); err != nil {
+		t.Error(err)
+	} else if !info.ModTime().Equal(hourAgo) {
+		t.Errorf("gz mod time = %v, want %v", info.ModTime(), hourAgo)
+	}
+}
+
+// TestRotateReleaseStale 跨天后没有再写入的级别 清理时关闭前一天的文件并压缩
+func TestRotateReleaseStale(t *testing.T) {
+	dir := setupRotate(t, configModel.Zap{Compress: true})
+	w := &rotateWriter{dir: dir, level: "error"}
+	defer w.close()
+	if err := w.open(time.Now().AddDate(0, 0, -1)); err != nil {
+		t.Fatal(err)
+	}
+	stale := w.path
+	if _, err := w.file.WriteString("error\n"); err != nil {
+		t.Fatal(err)
+	}
+	rotateState.Lock()
+	rotateState.writers["error"] = w
+	rotateState.Unlock()
+
+	FileRotator.cleanup()
+
+	if w.file != nil || FileRotator.isActive(stale) {
+		t.Fatal("stale file still open")
+	}
+	if _, err := os.Stat(stale + ".gz"); err != nil {
+		t.Errorf("stale file not compressed: %v", err)
+	}
+	if _, err := w.Write([]byte("error\n")); err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, time.Now().Format(rotateDateLayout), "error.log"); w.path != want || !FileRotator.isActive(want) {
+		t.Errorf("path = %s, want %s", w.path, want)
+	}
+}
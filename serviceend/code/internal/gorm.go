@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"dataPanel/serviceend/global"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var Gorm = new(_gorm)
+
+type _gorm struct{}
+
+// Config 获取 gorm.Config sql日志统一输出到 zap
+func (g *_gorm) Config(logMode string) *gorm.Config {
+	return &gorm.Config{
+		Logger: logger.New(new(gormWriter), logger.Config{
+			SlowThreshold:             200 * time.Millisecond, // 慢sql阈值
+			LogLevel:                  g.LogLevel(logMode),
+			IgnoreRecordNotFoundError: true,
+			Colorful:                  false,
+		}),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	}
+}
+
+// LogLevel 根据字符串转化为 gorm 日志级别
+func (g *_gorm) LogLevel(logMode string) logger.LogLevel {
+	switch strings.ToLower(logMode) {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "warn":
+		return logger.Warn
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
+}
+
+// gormWriter 实现 logger.Writer 将 gorm 日志写入 zap
+type gormWriter struct{}
+
+func (w *gormWriter) Printf(message string, data ...interface{}) {
+	if global.GvaLog == nil {
+		fmt.Printf(message+"\n", data...)
+		return
+	}
+	global.GvaLog.Info(fmt.Sprintf(message, data...))
+}
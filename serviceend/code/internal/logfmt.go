@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder 输出 logfmt 格式 如 time="..." level=info message="..." key=value
+// With 添加的字段暂存于内嵌的 MapObjectEncoder, 按键名排序输出
+type logfmtEncoder struct {
+	*zapcore.MapObjectEncoder
+	cfg zapcore.EncoderConfig
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), cfg: cfg}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+	return &logfmtEncoder{MapObjectEncoder: clone, cfg: e.cfg}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf := logfmtPool.Get()
+	if e.cfg.TimeKey != "" && e.cfg.EncodeTime != nil {
+		e.appendEncoded(buf, e.cfg.TimeKey, func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeTime(ent.Time, enc) })
+	}
+	if e.cfg.LevelKey != "" && e.cfg.EncodeLevel != nil {
+		e.appendEncoded(buf, e.cfg.LevelKey, func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeLevel(ent.Level, enc) })
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		e.appendField(buf, e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && ent.Caller.Defined && e.cfg.EncodeCaller != nil {
+		e.appendEncoded(buf, e.cfg.CallerKey, func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeCaller(ent.Caller, enc) })
+	}
+	if e.cfg.MessageKey != "" {
+		e.appendField(buf, e.cfg.MessageKey, ent.Message)
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.appendField(buf, k, e.Fields[k])
+	}
+	// 逐个字段编码 保持调用时的顺序
+	for _, f := range fields {
+		m := zapcore.NewMapObjectEncoder()
+		f.AddTo(m)
+		for k, v := range m.Fields {
+			e.appendField(buf, k, v)
+		}
+	}
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		e.appendField(buf, e.cfg.StacktraceKey, ent.Stack)
+	}
+	if e.cfg.LineEnding != "" {
+		buf.AppendString(e.cfg.LineEnding)
+	} else {
+		buf.AppendString(zapcore.DefaultLineEnding)
+	}
+	return buf, nil
+}
+
+// appendEncoded 使用 EncoderConfig 中的编码函数生成值
+func (e *logfmtEncoder) appendEncoded(buf *buffer.Buffer, key string, encode func(zapcore.PrimitiveArrayEncoder)) {
+	if value, ok := e.encode(encode); ok {
+		e.appendField(buf, key, value)
+	}
+}
+
+// encode 收集编码函数输出的值 输出多个值时返回数组
+func (e *logfmtEncoder) encode(encode func(zapcore.PrimitiveArrayEncoder)) (interface{}, bool) {
+	m := zapcore.NewMapObjectEncoder()
+	_ = m.AddArray("v", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		encode(enc)
+		return nil
+	}))
+	values, _ := m.Fields["v"].([]interface{})
+	switch len(values) {
+	case 0:
+		return nil, false
+	case 1:
+		return values[0], true
+	default:
+		return values, true
+	}
+}
+
+func (e *logfmtEncoder) appendField(buf *buffer.Buffer, key string, value interface{}) {
+	if buf.Len() > 0 {
+		buf.AppendByte(' ')
+	}
+	buf.AppendString(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key))
+	buf.AppendByte('=')
+	buf.AppendString(e.format(value))
+}
+
+// format 值转为 logfmt 文本 含空格、引号、等号或不可见字符时加引号
+func (e *logfmtEncoder) format(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case nil:
+		return "null"
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		return fmt.Sprint(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case time.Duration:
+		if encoded, ok := e.encode(func(enc zapcore.PrimitiveArrayEncoder) { e.durationEncoder()(v, enc) }); ok {
+			return e.format(encoded)
+		}
+		s = v.String()
+	case time.Time:
+		if encoded, ok := e.encode(func(enc zapcore.PrimitiveArrayEncoder) { e.timeEncoder()(v, enc) }); ok {
+			return e.format(encoded)
+		}
+		s = v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		s = v.String()
+	case error:
+		s = v.Error()
+	default:
+		// 数组、对象等嵌套结构 以 JSON 输出
+		data, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(data)
+		}
+	}
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return r == ' ' || r == '=' || r == '"' || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func (e *logfmtEncoder) durationEncoder() zapcore.DurationEncoder {
+	if e.cfg.EncodeDuration != nil {
+		return e.cfg.EncodeDuration
+	}
+	return zapcore.StringDurationEncoder
+}
+
+func (e *logfmtEncoder) timeEncoder() zapcore.TimeEncoder {
+	if e.cfg.EncodeTime != nil {
+		return e.cfg.EncodeTime
+	}
+	return zapcore.RFC3339NanoTimeEncoder
+}
@@ -3,57 +3,82 @@ package internal
 import (
 	"dataPanel/serviceend/global"
 	"fmt"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 )
 
+// PrefixKey json/logfmt 格式中日志前缀的字段名 console 格式的前缀写在消息前
+const PrefixKey = "prefix"
+
 var Zap = new(_zap)
 
 type _zap struct{}
 
-// GetZapCores 根据配置文件的Level获取 []zapcore.Core
+// GetZapCores 为每个级别创建 zapcore.Core 是否输出由 global.GvaLogLevel 决定, 运行时修改级别无需重建
+// 开启 log-in-console 时另加一个输出到控制台的 core, 只有控制台输出使用带颜色的级别编码器
 func (z *_zap) GetZapCores() []zapcore.Core {
-	cores := make([]zapcore.Core, 0, 7)
-	for level := global.GvaConfig.Zap.TransportLevel(); level <= zapcore.FatalLevel; level++ {
-		cores = append(cores, z.GetEncoderCore(level, z.GetLevelPriority(level)))
+	cores := make([]zapcore.Core, 0, 8)
+	for level := zapcore.DebugLevel; level <= zapcore.FatalLevel; level++ {
+		priority := z.GetLevelPriority(level)
+		cores = append(cores, z.GetEncoderCore(level, func(l zapcore.Level) bool {
+			return priority(l) && global.GvaLogLevel.Enabled(l)
+		}))
+	}
+	if global.GvaConfig.Zap.LogInConsole {
+		cores = append(cores, zapcore.NewCore(z.GetEncoder(true), zapcore.Lock(os.Stdout), global.GvaLogLevel))
 	}
 	return cores
 }
 
 // GetEncoderCore 获取Encoder的 zapcore.Core
 func (z *_zap) GetEncoderCore(l zapcore.Level, level zap.LevelEnablerFunc) zapcore.Core {
-	writer, err := FileRotatelogs.GetWriteSyncer(l.String()) // 使用file-rotatelogs进行日志分割
+	writer, err := FileRotator.GetWriteSyncer(l.String()) // 按天与大小分割日志文件
 	if err != nil {
 		fmt.Printf("Get Write Syncer Failed err:%v", err.Error())
 		return nil
 	}
 
-	return zapcore.NewCore(z.GetEncoder(), writer, level)
+	return zapcore.NewCore(z.GetEncoder(false), writer, level)
 }
 
-// GetEncoder 获取 zapcore.Encoder
-func (z *_zap) GetEncoder() zapcore.Encoder {
-	encoderConfig := z.GetEncoderConfig()
-	if global.GvaConfig.Zap.Format == "json" {
-		return zapcore.NewJSONEncoder(encoderConfig)
+// GetEncoder 获取 zapcore.Encoder color 为是否允许带颜色的级别, 仅 console 格式生效
+// 配置的 prefix 在 json/logfmt 格式中为单独的字段, console 格式中写在消息前
+func (z *_zap) GetEncoder(color bool) zapcore.Encoder {
+	encoderConfig := z.GetEncoderConfig(color)
+	prefix := global.GvaConfig.Zap.Prefix
+	var encoder zapcore.Encoder
+	switch global.GvaConfig.Zap.Format {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "logfmt":
+		encoder = newLogfmtEncoder(encoderConfig)
+	default:
+		if prefix == "" {
+			return zapcore.NewConsoleEncoder(encoderConfig)
+		}
+		return prefixEncoder{Encoder: zapcore.NewConsoleEncoder(encoderConfig), prefix: prefix}
 	}
-	return zapcore.NewConsoleEncoder(encoderConfig)
+	if prefix != "" {
+		encoder.AddString(PrefixKey, prefix)
+	}
+	return encoder
 }
 
 // GetEncoderConfig 获取zapcore.EncoderConfig
-func (z *_zap) GetEncoderConfig() zapcore.EncoderConfig {
+func (z *_zap) GetEncoderConfig(color bool) zapcore.EncoderConfig {
 	return zapcore.EncoderConfig{
-		MessageKey:    "message",
-		LevelKey:      "level",
-		TimeKey:       "time",
-		NameKey:       "logger",
-		CallerKey:     "caller",
-		StacktraceKey: global.GvaConfig.Zap.StacktraceKey,
-		LineEnding:    zapcore.DefaultLineEnding, //默认换行符 \n
-		//EncodeLevel:    zapcore.LowercaseLevelEncoder,  // 日志等级序列为小写字符串，如:InfoLevel被序列化为 "info"
-		EncodeLevel:    zapcore.CapitalLevelEncoder,    // 日志等级序列为大写字符串
+		MessageKey:     "message",
+		LevelKey:       "level",
+		TimeKey:        "time",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  global.GvaConfig.Zap.StacktraceKey,
+		LineEnding:     zapcore.DefaultLineEnding,      //默认换行符 \n
+		EncodeLevel:    z.GetLevelEncoder(color),       // 日志等级按 encode-level 配置序列化
 		EncodeTime:     z.CustomTimeEncoder,            // 日志时间格式显示
 		EncodeDuration: zapcore.SecondsDurationEncoder, // 时间序列化，Duration为经过的浮点秒数
 		EncodeCaller:   zapcore.FullCallerEncoder,      // 日志行号显示
@@ -61,6 +86,20 @@ func (z *_zap) GetEncoderConfig() zapcore.EncoderConfig {
 	}
 }
 
+// GetLevelEncoder 根据 encode-level 获取级别编码器
+// 日志文件与 json/logfmt 格式不使用颜色, 避免转义码写入文件或破坏结构化输出
+func (z *_zap) GetLevelEncoder(color bool) zapcore.LevelEncoder {
+	if !color || global.GvaConfig.Zap.Format == "json" || global.GvaConfig.Zap.Format == "logfmt" {
+		switch global.GvaConfig.Zap.EncodeLevel {
+		case "LowercaseColorLevelEncoder":
+			return zapcore.LowercaseLevelEncoder
+		case "CapitalColorLevelEncoder":
+			return zapcore.CapitalLevelEncoder
+		}
+	}
+	return global.GvaConfig.Zap.ZapEncodeLevel()
+}
+
 // GetLevelPriority 根据 zapcore.Level 获取 zap.LevelEnablerFunc
 func (z *_zap) GetLevelPriority(level zapcore.Level) zap.LevelEnablerFunc {
 	switch level {
@@ -103,3 +142,18 @@ func (z *_zap) GetLevelPriority(level zapcore.Level) zap.LevelEnablerFunc {
 func (z *_zap) CustomTimeEncoder(t time.Time, encoder zapcore.PrimitiveArrayEncoder) {
 	encoder.AppendString(t.Format("2006/01/02 - 15:04:05.000"))
 }
+
+// prefixEncoder 在消息前加上前缀 用于 console 格式
+type prefixEncoder struct {
+	zapcore.Encoder
+	prefix string
+}
+
+func (e prefixEncoder) Clone() zapcore.Encoder {
+	return prefixEncoder{Encoder: e.Encoder.Clone(), prefix: e.prefix}
+}
+
+func (e prefixEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = e.prefix + ent.Message
+	return e.Encoder.EncodeEntry(ent, fields)
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"bytes"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"dataPanel/serviceend/utils/logview"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const ansiEscape = "\x1b["
+
+// encodeLine 按当前配置编码一条带字段的 warn 日志
+func encodeLine(t *testing.T, color bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	core := zapcore.NewCore(Zap.GetEncoder(color), zapcore.AddSync(&buf), zapcore.DebugLevel)
+	zap.New(core).Warn("磁盘空间不足", zap.String("disk", "C:"), zap.Int("used", 95))
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func TestZapEncoder(t *testing.T) {
+	formats := []string{"console", "json", "logfmt"}
+	levels := []struct {
+		encoder string
+		level   string
+		color   bool
+	}{
+		{"", "warn", false},
+		{"LowercaseLevelEncoder", "warn", false},
+		{"LowercaseColorLevelEncoder", "warn", true},
+		{"CapitalLevelEncoder", "WARN", false},
+		{"CapitalColorLevelEncoder", "WARN", true},
+	}
+	for _, format := range formats {
+		for _, lv := range levels {
+			for _, prefix := range []string{"", "LOG_"} {
+				for _, console := range []bool{false, true} {
+					name := strings.Join([]string{format, lv.encoder, prefix, map[bool]string{false: "file", true: "stdout"}[console]}, "/")
+					t.Run(name, func(t *testing.T) {
+						global.GvaConfig.Zap = &configModel.Zap{Format: format, EncodeLevel: lv.encoder, Prefix: prefix, StacktraceKey: "stacktrace"}
+						line := encodeLine(t, console)
+
+						// 只有控制台的 console 格式使用颜色
+						wantColor := lv.color && console && format == "console"
+						if strings.Contains(line, ansiEscape) != wantColor {
+							t.Fatalf("color = %v, want %v: %q", !wantColor, wantColor, line)
+						}
+						plain := strings.NewReplacer(ansiEscape+"33m", "", ansiEscape+"0m", "").Replace(line)
+
+						var timeValue, levelValue, message, prefixValue string
+						switch format {
+						case "json":
+							var fields map[string]interface{}
+							if err := json.Unmarshal([]byte(line), &fields); err != nil {
+								t.Fatalf("invalid json %q: %v", line, err)
+							}
+							timeValue, _ = fields["time"].(string)
+							levelValue, _ = fields["level"].(string)
+							message, _ = fields["message"].(string)
+							prefixValue, _ = fields[PrefixKey].(string)
+							if fields["disk"] != "C:" || fields["used"] != float64(95) {
+								t.Errorf("fields = %v", fields)
+							}
+						case "logfmt":
+							for _, want := range []string{`disk=C:`, `used=95`} {
+								if !strings.Contains(line, want) {
+									t.Errorf("missing %s in %q", want, line)
+								}
+							}
+							timeValue, levelValue = logfmtValue(line, "time"), logfmtValue(line, "level")
+							message, prefixValue = logfmtValue(line, "message"), logfmtValue(line, PrefixKey)
+						default:
+							parts := strings.Split(plain, "\t")
+							if len(parts) != 4 {
+								t.Fatalf("console columns = %q", parts)
+							}
+							timeValue, levelValue, message = parts[0], parts[1], parts[2]
+							if strings.HasPrefix(message, prefix) {
+								prefixValue, message = prefix, strings.TrimPrefix(message, prefix)
+							}
+							if parts[3] != `{"disk": "C:", "used": 95}` {
+								t.Errorf("console fields = %s", parts[3])
+							}
+						}
+						// 前缀不能混入时间
+						if _, err := time.ParseInLocation("2006/01/02 - 15:04:05.000", timeValue, time.Local); err != nil {
+							t.Errorf("time = %q: %v", timeValue, err)
+						}
+						if levelValue != lv.level {
+							t.Errorf("level = %q, want %q", levelValue, lv.level)
+						}
+						if message != "磁盘空间不足" || prefixValue != prefix {
+							t.Errorf("message = %q prefix = %q, want prefix %q", message, prefixValue, prefix)
+						}
+
+						// 日志查看可以解析所有格式
+						entry, _, ok := logview.Parse(line, "stacktrace")
+						if !ok || entry.Level != "warn" || !strings.HasSuffix(entry.Message, "磁盘空间不足") || entry.Time != timeValue {
+							t.Errorf("logview.Parse = %+v, %v", entry, ok)
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+// logfmtValue 取出 logfmt 行中的值 测试内容中没有转义字符
+func logfmtValue(line, key string) string {
+	i := strings.Index(" "+line, " "+key+"=")
+	if i < 0 {
+		return ""
+	}
+	value := line[i+len(key)+1:]
+	if strings.HasPrefix(value, `"`) {
+		return value[1 : 1+strings.Index(value[1:], `"`)]
+	}
+	if j := strings.Index(value, " "); j >= 0 {
+		return value[:j]
+	}
+	return value
+}
+
+// TestZapCores 日志文件不带颜色 按级别写入各自的文件, 级别过滤运行时生效
+func TestZapCores(t *testing.T) {
+	dir := t.TempDir()
+	global.GvaConfig.Zap = &configModel.Zap{Format: "console", EncodeLevel: "CapitalColorLevelEncoder", Prefix: "LOG_", Director: dir}
+	global.GvaLogLevel.SetLevel(zapcore.InfoLevel)
+	logger := zap.New(zapcore.NewTee(Zap.GetZapCores()...))
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Error("error")
+	_ = logger.Sync()
+
+	date := time.Now().Format(rotateDateLayout)
+	for level, want := range map[string]string{"info": "LOG_info", "error": "LOG_error", "debug": ""} {
+		data, err := os.ReadFile(filepath.Join(dir, date, level+".log"))
+		if want == "" {
+			if err == nil && len(data) > 0 {
+				t.Errorf("%s.log = %q, want empty", level, data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), ansiEscape) {
+			t.Errorf("%s.log contains color codes: %q", level, data)
+		}
+		if !strings.Contains(string(data), "\t"+strings.ToUpper(level)+"\t"+want) {
+			t.Errorf("%s.log = %q", level, data)
+		}
+	}
+}
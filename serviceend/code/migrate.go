@@ -0,0 +1,80 @@
+package code
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/migration"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 命令行迁移参数 在 Viper 中与 -c 一同解析
+var (
+	migrateCmd    string
+	migrateDryRun bool
+	migrateSteps  int
+)
+
+// InitMigration 执行数据库迁移 需在注册路由前调用
+// 指定 -migrate 时执行对应命令后直接退出,否则自动升级到最新版本
+func InitMigration() {
+	migrator := migration.NewMigrator(global.GvaDb, migrateDryRun)
+	if migrateCmd != "" {
+		if err := runMigrateCommand(migrator, strings.ToLower(migrateCmd)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	done, err := migrator.Up()
+	if err != nil {
+		global.GvaLog.Error("数据库迁移失败", zap.Error(err))
+		panic(err)
+	}
+	global.GvaLog.Info("数据库迁移完成", zap.Int("applied", len(done)))
+}
+
+func runMigrateCommand(migrator *migration.Migrator, cmd string) error {
+	switch cmd {
+	case "up":
+		done, err := migrator.Up()
+		printMigrations("up", done)
+		return err
+	case "down":
+		done, err := migrator.Down(migrateSteps)
+		printMigrations("down", done)
+		return err
+	case "status":
+		list, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-10s %-40s %-5s %-8s %s\n", "VERSION", "NAME", "KIND", "APPLIED", "APPLIED AT")
+		for _, s := range list {
+			appliedAt := "-"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-10d %-40s %-5s %-8t %s\n", s.Version, s.Name, s.Kind, s.Applied, appliedAt)
+		}
+		return migrator.Check()
+	default:
+		return fmt.Errorf("未知的迁移命令: %s, 可选 up|down|status", cmd)
+	}
+}
+
+func printMigrations(direction string, done []*migration.Migration) {
+	prefix := ""
+	if migrateDryRun {
+		prefix = "[dry-run] "
+	}
+	if len(done) == 0 {
+		fmt.Printf("%s%s: 无可执行的迁移\n", prefix, direction)
+		return
+	}
+	for _, m := range done {
+		fmt.Printf("%s%s: %d_%s\n", prefix, direction, m.Version, m.Name)
+	}
+}
@@ -4,16 +4,28 @@ import (
 	"dataPanel/serviceend/common"
 	"dataPanel/serviceend/global"
 	"dataPanel/serviceend/router"
+	"dataPanel/serviceend/service"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 func CreateGinServer() (engine *gin.Engine) {
 	//创建gin 实例
 	engine = gin.New()
+	engine.Use(common.AccessLog())  //访问日志 在最外层记录异常处理后的状态码
 	engine.Use(common.CatchError()) //全局异常处理
+	engine.Use(common.Cors())       //跨域处理
 	g := engine.RouterGroup.Group(global.GvaConfig.System.ApplicationName)
 	router.SetupRouter(g)
+	//同步接口权限并加载权限策略
+	permissionService := service.ServiceGroupApp.PermissionService
+	if err := permissionService.SyncRoutes(engine.Routes(), g.BasePath()); err != nil {
+		global.GvaLog.Error("接口权限同步失败", zap.Error(err))
+	}
+	if err := permissionService.ReloadPolicy(); err != nil {
+		global.GvaLog.Error("权限策略加载失败", zap.Error(err))
+	}
 	global.GvaLog.Info("路由加载  GinServer register success")
 	return engine
 }
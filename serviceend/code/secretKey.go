@@ -0,0 +1,90 @@
+package code
+
+import (
+	"crypto/rand"
+	"dataPanel/serviceend/code/internal"
+	"dataPanel/serviceend/global"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// InitSecretKey 确定数据加密密钥与令牌签名密钥 需在使用 system.secret-key 与 jwt.signing-key 前调用
+// 配置为空时读取密钥文件, 文件不存在时生成随机密钥并保存
+// 数据加密密钥为早期默认值时拒绝启动; 签名密钥为早期默认值时忽略并按未配置处理, 已签发的令牌随之失效
+func InitSecretKey() {
+	key, err := loadSecretKey()
+	if err != nil {
+		global.GvaLog.Error("数据加密密钥初始化失败", zap.Error(err))
+		panic(err)
+	}
+	global.GvaConfig.System.SecretKey = key
+	if key, err = loadSigningKey(); err != nil {
+		global.GvaLog.Error("令牌签名密钥初始化失败", zap.Error(err))
+		panic(err)
+	}
+	global.GvaConfig.Jwt.SigningKey = key
+}
+
+func loadSecretKey() (string, error) {
+	key := strings.TrimSpace(global.GvaConfig.System.SecretKey)
+	if key == internal.SecretKeyDefault {
+		return "", errors.New("system.secret-key 为早期版本的公开默认值, 请修改为随机值或留空自动生成, 修改后已保存的数据源密码需重新填写")
+	}
+	if key != "" {
+		return key, nil
+	}
+	return loadKeyFile(internal.SecretKeyFile, "已生成数据加密密钥, 请妥善备份, 丢失后已保存的数据源密码无法解密")
+}
+
+func loadSigningKey() (string, error) {
+	key := strings.TrimSpace(global.GvaConfig.Jwt.SigningKey)
+	if key == internal.SigningKeyDefault {
+		global.GvaLog.Error("jwt.signing-key 为早期版本的公开默认值, 任何人都可以伪造令牌, 已忽略并改用密钥文件, 请删除该配置",
+			zap.String("file", internal.SigningKeyFile))
+		key = ""
+	}
+	if key != "" {
+		return key, nil
+	}
+	return loadKeyFile(internal.SigningKeyFile, "已生成令牌签名密钥")
+}
+
+// loadKeyFile 读取密钥文件 不存在时生成 32 字节随机密钥并保存, 生成后以 warn 级别记录 created
+func loadKeyFile(name, created string) (string, error) {
+	content, err := os.ReadFile(name)
+	if err == nil {
+		key := strings.TrimSpace(string(content))
+		if key == "" {
+			return "", fmt.Errorf("密钥文件 %s 为空", name)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	key := hex.EncodeToString(buf)
+	// O_EXCL 避免覆盖并发生成的密钥
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if _, err = file.WriteString(key); err != nil {
+		_ = file.Close()
+		_ = os.Remove(name)
+		return "", err
+	}
+	if err = file.Close(); err != nil {
+		return "", err
+	}
+	global.GvaLog.Warn(created, zap.String("file", name))
+	return key, nil
+}
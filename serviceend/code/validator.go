@@ -52,13 +52,8 @@ func InitTrans(locale string) {
 
 		// register all sql.Null* types to use the ValidateValuer CustomTypeFunc
 		v.RegisterCustomTypeFunc(validator.ValidateValuer, sql.NullString{}, sql.NullInt64{}, sql.NullInt32{}, sql.NullBool{}, sql.NullFloat64{})
-		// 注意！因为这里会使用到trans实例
-		// 所以这一步注册要放到trans初始化的后面
-		// 添加额外翻译
-		validator.AddTranslation(&v, trans)
-		//自定义验证方法
-		validator.AddValidationMethod(&v)
 		// 注册翻译器
+		// 默认翻译遇到已注册的 tag 会中止 需先于自定义翻译注册
 		switch locale {
 		case "en":
 			enTranslations.RegisterDefaultTranslations(v, trans)
@@ -67,6 +62,12 @@ func InitTrans(locale string) {
 		default:
 			zhTranslations.RegisterDefaultTranslations(v, trans)
 		}
+		// 注意！因为这里会使用到trans实例
+		// 所以这一步注册要放到trans初始化的后面
+		// 添加额外翻译 覆盖同名的默认翻译
+		validator.AddTranslation(&v, trans)
+		//自定义验证方法
+		validator.AddValidationMethod(&v)
 		global.GvaTrans = &trans
 		return
 	}
@@ -2,7 +2,9 @@ package code
 
 import (
 	"dataPanel/serviceend/code/internal"
+	"dataPanel/serviceend/common"
 	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
 	"flag"
 	"fmt"
 	"os"
@@ -19,7 +21,14 @@ func Viper(path ...string) *viper.Viper {
 
 	if len(path) == 0 {
 		flag.StringVar(&config, "c", "", "choose config file.")
+		flag.StringVar(&migrateCmd, "migrate", "", "run schema migration and exit: up|down|status.")
+		flag.BoolVar(&migrateDryRun, "dry-run", false, "print pending migrations without executing, used with -migrate.")
+		flag.IntVar(&migrateSteps, "steps", 1, "number of migrations to roll back, used with -migrate down.")
 		flag.Parse()
+		if migrateDryRun && migrateCmd == "" {
+			fmt.Println("-dry-run 需与 -migrate 一同使用")
+			os.Exit(2)
+		}
 		if config == "" { // 判断命令行参数是否为空
 			if configEnv := os.Getenv(internal.ConfigEnv); configEnv == "" { // 判断 internal.ConfigEnv 常量存储的环境变量是否为空
 				switch gin.Mode() {
@@ -60,9 +69,24 @@ func Viper(path ...string) *viper.Viper {
 	// 配置文件发生变更之后会调用的回调函数
 	v.OnConfigChange(func(e fsnotify.Event) {
 		//// 注意！！！配置文件发生变化后要同步到全局变量Conf
-		if err = v.Unmarshal(&global.GvaConfig); err != nil {
+		// 解析到新的实例再整体替换,避免切片类配置(如跨域白名单)删减后残留旧元素
+		var conf configModel.ServerConfig
+		if err := v.Unmarshal(&conf); err != nil {
 			fmt.Println(err)
+			return
+		}
+		old := global.GvaConfig
+		global.GvaConfig = conf
+		// 数据加密密钥与令牌签名密钥沿用启动时确定的值 修改后需重启
+		if conf.System != nil && old.System != nil {
+			conf.System.SecretKey = old.System.SecretKey
+		}
+		if conf.Jwt != nil && old.Jwt != nil {
+			conf.Jwt.SigningKey = old.Jwt.SigningKey
 		}
+		common.LoadCorsPolicy()      // 跨域白名单热加载
+		common.LoadAccessLogPolicy() // 访问日志策略热加载
+		ReloadZap(old.Zap)           // 日志级别与输出格式热加载
 	})
 	//将读取的配置信息保存至全局变量Conf
 	if err = v.Unmarshal(&global.GvaConfig); err != nil {
@@ -15,6 +15,17 @@ func ApiReturn(code int, msg string) ApiReturnCode {
 	}
 }
 
+// Error 实现 error 接口 便于 service 层直接返回业务错误码
+func (a ApiReturnCode) Error() string {
+	return a.Msg
+}
+
+// WithData 携带数据返回 不修改预定义错误码
+func (a ApiReturnCode) WithData(data interface{}) ApiReturnCode {
+	a.Data = data
+	return a
+}
+
 var (
 	OK                      = ApiReturn(200, "ok")   // 通用成功
 	Err                     = ApiReturn(500, "服务异常") // 通用错误
@@ -64,4 +75,51 @@ var (
 	FileDisabled   = ApiReturn(10453, "文件已禁用/已删除")
 	DownloadFailed = ApiReturn(10454, "文件下载失败,请重试")
 	NoFaile        = ApiReturn(10455, "文件不存在")
+
+	//数据源
+	NoDataSource          = ApiReturn(10500, "数据源不存在")
+	DataSourceNameExisted = ApiReturn(10501, "数据源名称已存在")
+	DataSourceConnFailed  = ApiReturn(10502, "数据源连接失败")
+
+	//查询
+	QueryReadOnly         = ApiReturn(10510, "数据源为只读,仅允许执行查询语句")
+	QueryNotFound         = ApiReturn(10511, "查询不存在或已结束")
+	QueryFailed           = ApiReturn(10512, "查询执行失败")
+	QueryTimeout          = ApiReturn(10513, "查询超时")
+	NoSavedQuery          = ApiReturn(10520, "保存的查询不存在")
+	SavedQueryNameExisted = ApiReturn(10521, "查询名称已存在")
+
+	//仪表盘
+	NoDashboard              = ApiReturn(10530, "仪表盘不存在")
+	DashboardNameExisted     = ApiReturn(10531, "仪表盘名称已存在")
+	DashboardVersionConflict = ApiReturn(10532, "仪表盘已被修改,请刷新后重试")
+	NoDashboardVersion       = ApiReturn(10533, "仪表盘版本不存在")
+	NoWidget                 = ApiReturn(10534, "组件不存在")
+
+	//定时任务
+	NoJob      = ApiReturn(10540, "任务不存在")
+	JobRunning = ApiReturn(10541, "任务正在执行,本次触发已跳过")
+
+	//告警
+	NoAlertRule          = ApiReturn(10550, "告警规则不存在")
+	AlertRuleNameExisted = ApiReturn(10551, "告警规则名称已存在")
+
+	//通知
+	NoNotifyChannel = ApiReturn(10560, "通知渠道不存在或未启用")
+	NotifyFailed    = ApiReturn(10561, "通知发送失败")
+
+	//导出
+	ExportSourceInvalid = ApiReturn(10570, "请指定一个导出来源: 组件、保存的查询或数据源与SQL")
+	ExportNotQuery      = ApiReturn(10571, "只能导出查询语句的结果")
+	ExportFailed        = ApiReturn(10572, "导出失败")
+
+	//导入
+	ImportFileInvalid    = ApiReturn(10580, "导入文件无法解析")
+	ImportTableInvalid   = ApiReturn(10581, "表名不合法或为系统表")
+	ImportTableExisted   = ApiReturn(10582, "目标表已存在")
+	NoImportTable        = ApiReturn(10583, "目标表不存在")
+	ImportValidateFailed = ApiReturn(10584, "数据校验未通过,未导入任何数据")
+
+	//日志
+	LogPatternInvalid = ApiReturn(10590, "正则表达式格式错误")
 )
@@ -0,0 +1,230 @@
+package common
+
+import (
+	"bytes"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/utils"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	RequestIdHeader = "X-Request-Id" // 请求ID 请求头中已有时沿用, 同时写入响应头
+	RequestIdKey    = "requestId"    // 请求ID 在 gin.Context 中的键
+
+	accessLogBodyMaxSize = 2048  // 默认记录请求体的最大字节数
+	accessLogRedacted    = "***" // 脱敏后的值
+)
+
+// accessLogPolicy 访问日志策略快照 配置变更时整体替换,请求处理过程中只读
+type accessLogPolicy struct {
+	enable      bool
+	skip        []string
+	samples     []accessLogSample
+	slow        time.Duration
+	body        bool
+	bodyMaxSize int
+	redact      map[string]struct{}
+}
+
+type accessLogSample struct {
+	path string
+	rate float64
+}
+
+var accessLogPolicyHolder atomic.Pointer[accessLogPolicy]
+
+// LoadAccessLogPolicy 根据 global.GvaConfig.AccessLog 重建访问日志策略,在配置文件热加载时调用
+func LoadAccessLogPolicy() {
+	policy := &accessLogPolicy{bodyMaxSize: accessLogBodyMaxSize, redact: map[string]struct{}{}}
+	if c := global.GvaConfig.AccessLog; c != nil {
+		policy.enable, policy.skip, policy.body = c.Enable, c.SkipPaths, c.Body
+		for _, s := range c.Samples {
+			policy.samples = append(policy.samples, accessLogSample{path: s.Path, rate: s.Rate})
+		}
+		if c.SlowThreshold != "" {
+			slow, err := utils.ParseDuration(c.SlowThreshold)
+			if err != nil && global.GvaLog != nil {
+				global.GvaLog.Error("访问日志慢请求耗时格式错误", zap.String("slow-threshold", c.SlowThreshold), zap.Error(err))
+			}
+			policy.slow = slow
+		}
+		if c.BodyMaxSize > 0 {
+			policy.bodyMaxSize = c.BodyMaxSize
+		}
+		for _, field := range c.RedactFields {
+			policy.redact[strings.ToLower(field)] = struct{}{}
+		}
+	}
+	accessLogPolicyHolder.Store(policy)
+}
+
+// AccessLog 访问日志中间件 记录方法、路径、状态码、耗时、客户端IP、请求ID与响应大小
+// 跳过与采样的路径在出错(5xx 或业务码不是成功)或慢请求时仍会记录, 业务码不是成功时以 warn 级别记录
+// SSE 等长连接的耗时为连接时长, 不按慢请求处理
+func AccessLog() gin.HandlerFunc {
+	if accessLogPolicyHolder.Load() == nil {
+		LoadAccessLogPolicy()
+	}
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if requestId == "" || len(requestId) > 64 {
+			requestId = uuid.NewString()
+		}
+		c.Set(RequestIdKey, requestId)
+		c.Header(RequestIdHeader, requestId)
+		policy := accessLogPolicyHolder.Load()
+		if !policy.enable {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		var body []byte
+		if policy.body {
+			body = policy.readBody(c.Request)
+		}
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		stream := strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream")
+		slow := policy.slow > 0 && latency >= policy.slow && !stream
+		// CatchError 将 panic 等错误以 HTTP 200 与错误业务码返回
+		code, hasCode := c.Get(response.CodeKey)
+		failed := status >= http.StatusInternalServerError || (hasCode && code != response.SUCCESS)
+		path := strings.TrimPrefix(c.Request.URL.Path, "/"+global.GvaConfig.System.ApplicationName)
+		if !failed && !slow && !policy.sampled(path) {
+			return
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", latency),
+			zap.String("ip", c.ClientIP()),
+			zap.String("requestId", requestId),
+			zap.Int("size", max(c.Writer.Size(), 0)),
+		}
+		if hasCode {
+			fields = append(fields, zap.Any("code", code))
+		}
+		if c.Request.URL.RawQuery != "" {
+			fields = append(fields, zap.String("query", policy.redactForm(c.Request.URL.RawQuery)))
+		}
+		if userId := utils.GetUserID(c); userId != 0 {
+			fields = append(fields, zap.Uint("userId", userId))
+		}
+		if len(body) > 0 {
+			fields = append(fields, zap.String("body", policy.redactBody(c.ContentType(), body)))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+		switch {
+		case status >= http.StatusInternalServerError:
+			global.GvaLog.Error("访问日志", fields...)
+		case status >= http.StatusBadRequest || slow || (hasCode && code != response.SUCCESS):
+			global.GvaLog.Warn("访问日志", fields...)
+		default:
+			global.GvaLog.Info("访问日志", fields...)
+		}
+	}
+}
+
+// sampled 路径是否需要记录 跳过列表优先, 其次按第一个匹配的采样比例
+func (p *accessLogPolicy) sampled(path string) bool {
+	for _, skip := range p.skip {
+		if matchAccessLogPath(skip, path) {
+			return false
+		}
+	}
+	for _, s := range p.samples {
+		if matchAccessLogPath(s.path, path) {
+			return rand.Float64() < s.rate
+		}
+	}
+	return true
+}
+
+// readBody 读取 json 与表单请求体 超出大小上限时不记录, 读取的内容放回请求供后续处理
+func (p *accessLogPolicy) readBody(r *http.Request) []byte {
+	contentType := r.Header.Get("Content-Type")
+	if r.Body == nil || !(strings.HasPrefix(contentType, gin.MIMEJSON) || strings.HasPrefix(contentType, gin.MIMEPOSTForm)) ||
+		r.ContentLength > int64(p.bodyMaxSize) {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, int64(p.bodyMaxSize)+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > p.bodyMaxSize {
+		return nil
+	}
+	return body
+}
+
+// redactBody 替换请求体中的脱敏字段 json 按键名递归替换, 无法解析时不记录内容
+func (p *accessLogPolicy) redactBody(contentType string, body []byte) string {
+	if contentType == gin.MIMEPOSTForm {
+		return p.redactForm(string(body))
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "[invalid json]"
+	}
+	b, _ := json.Marshal(p.redactJson(v))
+	return string(b)
+}
+
+func (p *accessLogPolicy) redactJson(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			if _, ok := p.redact[strings.ToLower(k)]; ok {
+				value[k] = accessLogRedacted
+				continue
+			}
+			value[k] = p.redactJson(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = p.redactJson(item)
+		}
+	}
+	return v
+}
+
+// redactForm 替换查询参数或表单中的脱敏字段 其余参数保持原样
+func (p *accessLogPolicy) redactForm(raw string) string {
+	pairs := strings.Split(raw, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if _, ok := p.redact[strings.ToLower(key)]; ok {
+			pairs[i] = key + "=" + accessLogRedacted
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// matchAccessLogPath 路径相同或以 * 结尾时按前缀匹配
+func matchAccessLogPath(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return pattern == path
+}
@@ -0,0 +1,197 @@
+package common
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newAccessLogEngine(t *testing.T) (*gin.Engine, *observer.ObservedLogs) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.DebugLevel)
+	global.GvaLog = zap.New(core)
+	global.GvaConfig.System = &configModel.System{ApplicationName: "dataPanel"}
+	global.GvaConfig.AccessLog = &configModel.AccessLog{
+		Enable:        true,
+		SkipPaths:     []string{"/hello", "/log/tail"},
+		SlowThreshold: "50ms",
+	}
+	LoadAccessLogPolicy()
+	t.Cleanup(func() { global.GvaConfig.AccessLog = nil; LoadAccessLogPolicy() })
+	engine := gin.New()
+	engine.Use(AccessLog(), CatchError())
+	g := engine.Group("dataPanel")
+	g.GET("/ok", func(c *gin.Context) { response.Ok(c) })
+	g.GET("/hello", func(c *gin.Context) {
+		if c.Query("panic") != "" {
+			panic("boom")
+		}
+		if c.Query("slow") != "" {
+			time.Sleep(60 * time.Millisecond)
+		}
+		response.Ok(c)
+	})
+	stream := func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		time.Sleep(60 * time.Millisecond)
+		c.SSEvent("log:tail", "line")
+	}
+	g.GET("/log/tail", stream)
+	g.GET("/stream", stream)
+	return engine, logs
+}
+
+func TestAccessLogBypass(t *testing.T) {
+	cases := []struct {
+		name   string
+		path   string
+		level  zapcore.Level
+		logged bool
+	}{
+		{"normal", "/dataPanel/ok", zapcore.InfoLevel, true},
+		{"skipped", "/dataPanel/hello", 0, false},
+		{"skipped panic", "/dataPanel/hello?panic=1", zapcore.WarnLevel, true},
+		{"skipped slow", "/dataPanel/hello?slow=1", zapcore.WarnLevel, true},
+		{"tail stream", "/dataPanel/log/tail", 0, false},
+		{"stream not slow", "/dataPanel/stream", zapcore.InfoLevel, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine, logs := newAccessLogEngine(t)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Header().Get(RequestIdHeader) == "" {
+				t.Error("missing request id header")
+			}
+			entries := logs.FilterMessage("访问日志").All()
+			if (len(entries) == 1) != tc.logged {
+				t.Fatalf("access log entries = %d, want logged %v", len(entries), tc.logged)
+			}
+			if tc.logged && entries[0].Level != tc.level {
+				t.Errorf("level = %s, want %s", entries[0].Level, tc.level)
+			}
+		})
+	}
+}
+
+func TestAccessLogRedact(t *testing.T) {
+	global.GvaConfig.AccessLog = &configModel.AccessLog{RedactFields: []string{"password", "accessToken", "code"}}
+	LoadAccessLogPolicy()
+	t.Cleanup(func() { global.GvaConfig.AccessLog = nil; LoadAccessLogPolicy() })
+	policy := accessLogPolicyHolder.Load()
+
+	bodies := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"flat", "application/json", `{"username":"admin","password":"123456"}`, `{"password":"***","username":"admin"}`},
+		{"nested", "application/json", `{"user":{"Password":"x","name":"a"},"list":[{"ACCESSTOKEN":"t"},1],"code":"9527"}`,
+			`{"code":"***","list":[{"ACCESSTOKEN":"***"},1],"user":{"Password":"***","name":"a"}}`},
+		{"redacted object", "application/json", `{"password":{"old":"a","new":"b"}}`, `{"password":"***"}`},
+		{"invalid json", "application/json", `{"password":`, "[invalid json]"},
+		{"form", "application/x-www-form-urlencoded", "Password=x&name=a", "Password=***&name=a"},
+	}
+	for _, tc := range bodies {
+		t.Run("body/"+tc.name, func(t *testing.T) {
+			if got := policy.redactBody(tc.contentType, []byte(tc.body)); got != tc.want {
+				t.Errorf("redactBody = %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	forms := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"plain", "name=a&password=x", "name=a&password=***"},
+		{"case insensitive", "AccessToken=t&page=1", "AccessToken=***&page=1"},
+		{"escaped key", "pass%77ord=x&code%3D=1", "password=***&code%3D=1"},
+		{"repeated", "code=1&code=2", "code=***&code=***"},
+		{"no value", "password&name=a", "password=***&name=a"},
+		{"escaped value kept", "name=a%26b", "name=a%26b"},
+		{"empty", "", ""},
+	}
+	for _, tc := range forms {
+		t.Run("form/"+tc.name, func(t *testing.T) {
+			if got := policy.redactForm(tc.raw); got != tc.want {
+				t.Errorf("redactForm = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestAccessLogBody 请求体按上限记录并脱敏, 无论是否记录后续处理都能读到完整请求体
+func TestAccessLogBody(t *testing.T) {
+	engine, logs := newAccessLogEngine(t)
+	global.GvaConfig.AccessLog.Body = true
+	global.GvaConfig.AccessLog.BodyMaxSize = 32
+	global.GvaConfig.AccessLog.RedactFields = []string{"password"}
+	LoadAccessLogPolicy()
+	engine.POST("/dataPanel/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		c.Data(http.StatusOK, "text/plain", body)
+	})
+
+	large := `{"password":"x","name":"` + strings.Repeat("a", 32) + `"}`
+	cases := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		chunked     bool
+		wantBody    string // 为空时不记录请求体
+		wantQuery   string
+	}{
+		{"json", "/dataPanel/echo", "application/json; charset=utf-8", `{"password":"x"}`, false, `{"password":"***"}`, ""},
+		{"json too large", "/dataPanel/echo", "application/json", large, false, "", ""},
+		{"chunked", "/dataPanel/echo", "application/json", `{"password":"x"}`, true, `{"password":"***"}`, ""},
+		{"chunked too large", "/dataPanel/echo", "application/json", large, true, "", ""},
+		{"form with query", "/dataPanel/echo?Password=q&page=1", "application/x-www-form-urlencoded", "password=x&name=a", false,
+			"password=***&name=a", "Password=***&page=1"},
+		{"other content type", "/dataPanel/echo", "text/plain", "password=x", false, "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_ = logs.TakeAll()
+			req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			if tc.chunked {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(struct{ io.Reader }{strings.NewReader(tc.body)})
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			if w.Body.String() != tc.body {
+				t.Errorf("handler read %q, want %q", w.Body.String(), tc.body)
+			}
+			entries := logs.FilterMessage("访问日志").All()
+			if len(entries) != 1 {
+				t.Fatalf("access log entries = %d", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			if body, _ := fields["body"].(string); body != tc.wantBody {
+				t.Errorf("logged body = %q, want %q", body, tc.wantBody)
+			}
+			if query, _ := fields["query"].(string); query != tc.wantQuery {
+				t.Errorf("logged query = %q, want %q", query, tc.wantQuery)
+			}
+		})
+	}
+}
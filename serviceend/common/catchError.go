@@ -26,7 +26,12 @@ func CatchError() gin.HandlerFunc {
 				// 没有定义 错误
 				global.GvaLog.Error("未知错误类型", zap.Any("url", url), zap.Any("method", method), zap.Any("Error", err))
 				unknownErr := ApiReturn.UnknownErr
-				unknownErr.Msg = err.(string)
+				switch v := err.(type) {
+				case string:
+					unknownErr.Msg = v
+				case error:
+					unknownErr.Msg = v.Error()
+				}
 				response.WithApiReturn(unknownErr, c)
 				c.Abort()
 			}
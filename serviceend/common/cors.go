@@ -0,0 +1,122 @@
+package common
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	CorsModeAllowAll        = "allow-all"        // 放行全部
+	CorsModeWhitelist       = "whitelist"        // 白名单模式, 白名单内的来源添加 cors 头
+	CorsModeStrictWhitelist = "strict-whitelist" // 严格白名单模式, 白名单外的请求一律拒绝
+
+	corsDefaultAllowHeaders  = "Content-Type, AccessToken, X-CSRF-Token, Authorization, Token, X-Token, X-User-Id, X-Request-Id"
+	corsDefaultAllowMethods  = "POST, GET, OPTIONS, DELETE, PUT, PATCH"
+	corsDefaultExposeHeaders = "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Content-Type, X-Request-Id"
+)
+
+// corsPolicy 跨域策略快照 配置变更时整体替换,请求处理过程中只读
+type corsPolicy struct {
+	mode      string
+	whitelist map[string]*configModel.CorsWhitelist
+}
+
+var corsPolicyHolder atomic.Pointer[corsPolicy]
+
+// LoadCorsPolicy 根据 global.GvaConfig.Cors 重建跨域策略,在配置文件热加载时调用
+func LoadCorsPolicy() {
+	policy := &corsPolicy{whitelist: map[string]*configModel.CorsWhitelist{}}
+	if cors := global.GvaConfig.Cors; cors != nil {
+		policy.mode = strings.ToLower(strings.TrimSpace(cors.Mode))
+		for i := range cors.Whitelist {
+			item := cors.Whitelist[i]
+			key := normalizeOrigin(item.AllowOrigin)
+			if key == "" {
+				continue
+			}
+			policy.whitelist[key] = &item
+		}
+	}
+	corsPolicyHolder.Store(policy)
+	if global.GvaLog != nil {
+		global.GvaLog.Info("跨域策略加载完成", zap.String("mode", policy.mode), zap.Int("whitelist", len(policy.whitelist)))
+	}
+}
+
+// Cors 跨域处理中间件 按 cors.mode 放行全部/白名单/严格白名单
+func Cors() gin.HandlerFunc {
+	if corsPolicyHolder.Load() == nil {
+		LoadCorsPolicy()
+	}
+	return func(c *gin.Context) {
+		policy := corsPolicyHolder.Load()
+		origin := c.GetHeader("Origin")
+		// 非跨域请求(无 Origin 头)或未配置跨域策略直接放行
+		if origin == "" || policy == nil || policy.mode == "" {
+			c.Next()
+			return
+		}
+		switch policy.mode {
+		case CorsModeAllowAll:
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Headers", corsDefaultAllowHeaders)
+			c.Header("Access-Control-Allow-Methods", corsDefaultAllowMethods)
+			c.Header("Access-Control-Expose-Headers", corsDefaultExposeHeaders)
+			c.Header("Access-Control-Allow-Credentials", "true")
+			c.Header("Vary", "Origin")
+		case CorsModeWhitelist, CorsModeStrictWhitelist:
+			item := policy.match(origin)
+			if item == nil {
+				if policy.mode == CorsModeStrictWhitelist {
+					global.GvaLog.Warn("跨域请求来源不在白名单内,已拒绝", zap.String("origin", origin), zap.String("path", c.Request.URL.Path))
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				// 白名单模式下非白名单来源不添加 cors 头,由浏览器拦截
+				break
+			}
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Headers", item.AllowHeaders)
+			c.Header("Access-Control-Allow-Methods", item.AllowMethods)
+			c.Header("Access-Control-Expose-Headers", item.ExposeHeaders)
+			if item.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+			c.Header("Vary", "Origin")
+		default:
+			c.Next()
+			return
+		}
+		// 预检请求直接返回
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// match 先按完整来源匹配,再按去掉协议的主机名匹配,配置中可写 example.com 或 https://example.com
+func (p *corsPolicy) match(origin string) *configModel.CorsWhitelist {
+	key := normalizeOrigin(origin)
+	if item, ok := p.whitelist[key]; ok {
+		return item
+	}
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		if item, ok := p.whitelist[u.Host]; ok {
+			return item
+		}
+	}
+	return nil
+}
+
+func normalizeOrigin(origin string) string {
+	return strings.TrimRight(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
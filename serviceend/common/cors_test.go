@@ -0,0 +1,116 @@
+package common
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func newCorsEngine(cors *configModel.Cors) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	global.GvaLog = zap.NewNop()
+	global.GvaConfig.Cors = cors
+	LoadCorsPolicy()
+	engine := gin.New()
+	engine.Use(Cors())
+	engine.Any("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	return engine
+}
+
+func doCors(engine *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/ping", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+var corsWhitelist = []configModel.CorsWhitelist{
+	{AllowOrigin: "example1.com", AllowHeaders: "content-type", AllowMethods: "GET, POST", ExposeHeaders: "X-One", AllowCredentials: true},
+	{AllowOrigin: "https://example2.com/", AllowHeaders: "x-token", AllowMethods: "GET", ExposeHeaders: "X-Two"},
+}
+
+func TestCorsModes(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		method      string
+		origin      string
+		status      int
+		allowOrigin string
+		expose      string
+		credentials string
+	}{
+		{"未配置 放行", "", http.MethodGet, "http://a.com", http.StatusOK, "", "", ""},
+		{"同源请求 放行", CorsModeStrictWhitelist, http.MethodGet, "", http.StatusOK, "", "", ""},
+		{"放行全部", CorsModeAllowAll, http.MethodGet, "http://a.com", http.StatusOK, "http://a.com", corsDefaultExposeHeaders, "true"},
+		{"放行全部 预检", CorsModeAllowAll, http.MethodOptions, "http://a.com", http.StatusNoContent, "http://a.com", corsDefaultExposeHeaders, "true"},
+		{"白名单 按主机名匹配", CorsModeWhitelist, http.MethodGet, "https://example1.com", http.StatusOK, "https://example1.com", "X-One", "true"},
+		{"白名单 按完整来源匹配", CorsModeWhitelist, http.MethodGet, "https://example2.com", http.StatusOK, "https://example2.com", "X-Two", ""},
+		{"白名单 预检", CorsModeWhitelist, http.MethodOptions, "http://example1.com", http.StatusNoContent, "http://example1.com", "X-One", "true"},
+		{"白名单 非白名单来源不加头", CorsModeWhitelist, http.MethodGet, "http://evil.com", http.StatusOK, "", "", ""},
+		{"白名单 非白名单来源预检", CorsModeWhitelist, http.MethodOptions, "http://evil.com", http.StatusNoContent, "", "", ""},
+		{"严格白名单 白名单来源", CorsModeStrictWhitelist, http.MethodPost, "http://example1.com", http.StatusOK, "http://example1.com", "X-One", "true"},
+		{"严格白名单 拒绝", CorsModeStrictWhitelist, http.MethodGet, "http://evil.com", http.StatusForbidden, "", "", ""},
+		{"严格白名单 拒绝预检", CorsModeStrictWhitelist, http.MethodOptions, "http://evil.com", http.StatusForbidden, "", "", ""},
+		{"未知模式 放行", "unknown", http.MethodGet, "http://a.com", http.StatusOK, "", "", ""},
+		{"模式忽略大小写", " Allow-All ", http.MethodGet, "http://a.com", http.StatusOK, "http://a.com", corsDefaultExposeHeaders, "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newCorsEngine(&configModel.Cors{Mode: tt.mode, Whitelist: corsWhitelist})
+			w := doCors(engine, tt.method, tt.origin)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Expose-Headers"); got != tt.expose {
+				t.Errorf("Expose-Headers = %q, want %q", got, tt.expose)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+		})
+	}
+}
+
+func TestCorsNilConfig(t *testing.T) {
+	engine := newCorsEngine(nil)
+	if w := doCors(engine, http.MethodGet, "http://a.com"); w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("status = %d, Allow-Origin = %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCorsHotReload(t *testing.T) {
+	engine := newCorsEngine(&configModel.Cors{Mode: CorsModeStrictWhitelist, Whitelist: corsWhitelist})
+	if w := doCors(engine, http.MethodGet, "http://new.com"); w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	// 已创建的中间件使用重新加载后的策略
+	global.GvaConfig.Cors = &configModel.Cors{Mode: CorsModeStrictWhitelist, Whitelist: []configModel.CorsWhitelist{
+		{AllowOrigin: "new.com", ExposeHeaders: "X-New"},
+	}}
+	LoadCorsPolicy()
+	w := doCors(engine, http.MethodGet, "http://new.com")
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Expose-Headers") != "X-New" {
+		t.Errorf("status = %d, Expose-Headers = %q", w.Code, w.Header().Get("Access-Control-Expose-Headers"))
+	}
+	// 删除的白名单项不再生效
+	if w := doCors(engine, http.MethodGet, "http://example1.com"); w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	global.GvaConfig.Cors = &configModel.Cors{Mode: CorsModeAllowAll}
+	LoadCorsPolicy()
+	if w := doCors(engine, http.MethodGet, "http://example1.com"); w.Header().Get("Access-Control-Allow-Origin") != "http://example1.com" {
+		t.Errorf("Allow-Origin = %q", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
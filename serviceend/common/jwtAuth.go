@@ -0,0 +1,54 @@
+package common
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvDevelop 开发环境 跳过鉴权
+const EnvDevelop = "develop"
+
+// JwtAuth 登录鉴权中间件 校验访问令牌并写入载荷
+func JwtAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if global.GvaConfig.System.Env == EnvDevelop {
+			c.Next()
+			return
+		}
+		token := utils.GetToken(c)
+		if token == "" {
+			response.WithApiReturn(ApiReturn.UnauthorizedAccess, c)
+			c.Abort()
+			return
+		}
+		claims, err := utils.NewJWT().ParseToken(token)
+		if err != nil {
+			if errors.Is(err, utils.TokenExpired) {
+				response.WithApiReturn(ApiReturn.LoginExpired, c)
+			} else {
+				response.WithApiReturn(ApiReturn.UnauthorizedAccess, c)
+			}
+			c.Abort()
+			return
+		}
+		if claims.TokenType != utils.TokenTypeAccess {
+			response.WithApiReturn(ApiReturn.UnauthorizedAccess, c)
+			c.Abort()
+			return
+		}
+		// 已退出/被强制下线/被其它客户端登录挤下线的会话
+		if service.ServiceGroupApp.JwtService.IsBlacklist(claims.ID) || !service.ServiceGroupApp.SessionService.IsActive(claims.SessionId) {
+			response.WithApiReturn(ApiReturn.LoginExpired, c)
+			c.Abort()
+			return
+		}
+		c.Set(utils.ClaimsKey, claims)
+		c.Next()
+	}
+}
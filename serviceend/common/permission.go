@@ -0,0 +1,37 @@
+package common
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Permission 接口权限中间件 需在 JwtAuth 之后使用 按 路由+请求方法 校验调用者角色
+func Permission() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if global.GvaConfig.System.Env == EnvDevelop {
+			c.Next()
+			return
+		}
+		claims := utils.GetClaims(c)
+		if claims == nil {
+			response.WithApiReturn(ApiReturn.UnauthorizedAccess, c)
+			c.Abort()
+			return
+		}
+		path := strings.TrimPrefix(c.FullPath(), "/"+global.GvaConfig.System.ApplicationName)
+		if !service.ServiceGroupApp.PermissionService.Enforce(claims.UserId, c.Request.Method, path) {
+			global.GvaLog.Warn("权限不足", zap.Uint("userId", claims.UserId), zap.String("method", c.Request.Method), zap.String("path", path))
+			response.WithApiReturn(ApiReturn.NoPermission, c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
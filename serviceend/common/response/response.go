@@ -2,9 +2,13 @@ package response
 
 import (
 	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 type Response struct {
@@ -18,6 +22,9 @@ const (
 	SUCCESS = 200
 )
 
+// CodeKey 业务码在 gin.Context 中的键 供访问日志记录
+const CodeKey = "responseCode"
+
 // 构造函数
 func response(code int, msg string) *Response {
 	return &Response{
@@ -27,6 +34,7 @@ func response(code int, msg string) *Response {
 	}
 }
 func Result(code int, data any, msg string, c *gin.Context) {
+	c.Set(CodeKey, code)
 	c.JSON(http.StatusOK, Response{
 		code,
 		data,
@@ -64,3 +72,53 @@ func FailWithDetailed(data interface{}, message string, c *gin.Context) {
 func WithApiReturn(a ApiReturn.ApiReturnCode, c *gin.Context) {
 	Result(a.Code, a.Data, a.Msg, c)
 }
+
+// FailWithError 业务错误码原样返回,校验错误按字段翻译返回,其它错误按通用错误返回
+func FailWithError(err error, c *gin.Context) {
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		FailWithValidate(err, c)
+		return
+	}
+	var apiErr ApiReturn.ApiReturnCode
+	if errors.As(err, &apiErr) {
+		WithApiReturn(apiErr, c)
+		return
+	}
+	Result(ApiReturn.Err.Code, nil, err.Error(), c)
+}
+
+// FailWithValidate 参数绑定/校验失败 校验错误经翻译器翻译后按字段返回
+func FailWithValidate(err error, c *gin.Context) {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || global.GvaTrans == nil {
+		Result(ApiReturn.ErrCheckParameterFailed.Code, nil, err.Error(), c)
+		return
+	}
+	WithApiReturn(ApiReturn.ErrCheckParameterFailed.WithData(TranslateValidate(errs)), c)
+}
+
+// TranslateValidate 将校验错误翻译为 字段 => 提示 去掉结构体名前缀
+func TranslateValidate(errs validator.ValidationErrors) map[string]string {
+	result := make(map[string]string, len(errs))
+	for field, msg := range errs.Translate(*global.GvaTrans) {
+		result[field[strings.Index(field, ".")+1:]] = msg
+	}
+	return result
+}
+
+// Wrap 将 service 返回值包装为统一响应结构 供 wails 绑定方法直接返回给前端
+func Wrap(data any, err error) Response {
+	if err == nil {
+		return Response{Code: SUCCESS, Data: data, Msg: "成功"}
+	}
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) && global.GvaTrans != nil {
+		err = ApiReturn.ErrCheckParameterFailed.WithData(TranslateValidate(errs))
+	}
+	var apiErr ApiReturn.ApiReturnCode
+	if errors.As(err, &apiErr) {
+		return Response{Code: apiErr.Code, Data: apiErr.Data, Msg: apiErr.Msg}
+	}
+	return Response{Code: ApiReturn.Err.Code, Msg: err.Error()}
+}
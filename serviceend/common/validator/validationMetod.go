@@ -43,6 +43,7 @@ func ValidateValuer(field reflect.Value) interface{} {
 // AddTranslation 自定义翻译器
 func AddTranslation(v **validator.Validate, translator ut.Translator) {
 	_ = (*v).RegisterTranslation("required", translator, registerTranslator("required", "{0}必填项[自定义翻译器]"), translate)
+	_ = (*v).RegisterTranslation("required_without", translator, registerTranslator("required_without", "{0}必填项[自定义翻译器]"), translate)
 	_ = (*v).RegisterTranslation("checkPhone", translator, registerTranslator("checkPhone", "{0}校验不通过[自定义翻译器]"), translate)
 	_ = (*v).RegisterTranslation("oneof", translator, registerTranslator("oneof", "{0}校验不通过[自定义翻译器]"), translate)
 }
@@ -50,7 +51,7 @@ func AddTranslation(v **validator.Validate, translator ut.Translator) {
 // registerTranslator 为自定义字段添加翻译功能
 func registerTranslator(tag string, msg string) validator.RegisterTranslationsFunc {
 	return func(trans ut.Translator) error {
-		if err := trans.Add(tag, msg, false); err != nil {
+		if err := trans.Add(tag, msg, true); err != nil {
 			return err
 		}
 		return nil
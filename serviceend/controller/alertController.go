@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertController 告警规则与告警记录
+type AlertController struct{}
+
+var (
+	alertService = service.ServiceGroupApp.AlertService
+)
+
+func NewAlertController() *AlertController {
+	return &AlertController{}
+}
+
+func (a *AlertController) SetupRouter(g *gin.RouterGroup) {
+	alertRouter := g.Group("/alert")
+	{
+		alertRouter.GET("/list", a.List)              // 告警规则列表
+		alertRouter.GET("/:id", a.Get)                // 告警规则详情
+		alertRouter.POST("", a.Create)                // 新增告警规则
+		alertRouter.PUT("", a.Update)                 // 修改告警规则
+		alertRouter.DELETE("", a.Delete)              // 删除告警规则
+		alertRouter.POST("/silence", a.Silence)       // 静默告警规则
+		alertRouter.GET("/events", a.Events)          // 告警记录
+		alertRouter.POST("/events/ack", a.Ack)        // 确认告警
+		alertRouter.GET("/events/unacked", a.Unacked) // 未确认告警数量
+	}
+}
+
+func (a *AlertController) List(ctx *gin.Context) {
+	var req reqModel.AlertRuleListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := alertService.List(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (a *AlertController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	rule, err := alertService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(rule, ctx)
+}
+
+func (a *AlertController) Create(ctx *gin.Context) {
+	var req reqModel.AlertRuleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	rule, err := alertService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(rule, ctx)
+}
+
+func (a *AlertController) Update(ctx *gin.Context) {
+	var req reqModel.AlertRuleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	rule, err := alertService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(rule, ctx)
+}
+
+func (a *AlertController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := alertService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (a *AlertController) Silence(ctx *gin.Context) {
+	var req reqModel.AlertSilenceReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	rule, err := alertService.Silence(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(rule, ctx)
+}
+
+func (a *AlertController) Events(ctx *gin.Context) {
+	var req reqModel.AlertEventListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := alertService.Events(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (a *AlertController) Ack(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := alertService.Ack(utils.GetUserID(ctx), req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (a *AlertController) Unacked(ctx *gin.Context) {
+	count, err := alertService.Unacked()
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(count, ctx)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BaseController 无需登录的基础接口
+type BaseController struct{}
+
+var (
+	userService         = service.ServiceGroupApp.UserService
+	verificationService = service.ServiceGroupApp.VerificationService
+)
+
+func NewBaseController() *BaseController {
+	return &BaseController{}
+}
+
+func (b *BaseController) SetupRouter(g *gin.RouterGroup) {
+	baseRouter := g.Group("/base")
+	{
+		baseRouter.POST("/login", b.Login)            // 登录
+		baseRouter.POST("/refresh", b.RefreshToken)   // 刷新令牌
+		baseRouter.POST("/code/send", b.SendCode)     // 发送短信/邮件验证码
+		baseRouter.POST("/code/verify", b.VerifyCode) // 校验验证码
+	}
+}
+
+func (b *BaseController) Login(ctx *gin.Context) {
+	var req reqModel.LoginReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := userService.Login(req, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithDetailed(res, "登录成功", ctx)
+}
+
+func (b *BaseController) RefreshToken(ctx *gin.Context) {
+	var req reqModel.RefreshTokenReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := userService.RefreshToken(req.RefreshToken)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (b *BaseController) SendCode(ctx *gin.Context) {
+	var req reqModel.SendCodeReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := verificationService.Send(req, ctx.ClientIP())
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithDetailed(res, "验证码已发送", ctx)
+}
+
+func (b *BaseController) VerifyCode(ctx *gin.Context) {
+	var req reqModel.VerifyCodeReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := verificationService.Verify(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithMessage("验证通过", ctx)
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulletinController 公告管理
+type BulletinController struct{}
+
+var (
+	bulletinService = service.ServiceGroupApp.BulletinService
+)
+
+func NewBulletinController() *BulletinController {
+	return &BulletinController{}
+}
+
+func (b *BulletinController) SetupRouter(g *gin.RouterGroup) {
+	bulletinRouter := g.Group("/bulletin")
+	{
+		bulletinRouter.GET("/list", b.List)              // 公告列表
+		bulletinRouter.GET("/:id", b.Get)                // 公告详情
+		bulletinRouter.POST("", b.Create)                // 新增公告
+		bulletinRouter.PUT("", b.Update)                 // 修改公告 仅草稿
+		bulletinRouter.DELETE("", b.Delete)              // 删除公告
+		bulletinRouter.POST("/publish", b.Publish)       // 发布/定时发布公告
+		bulletinRouter.POST("/withdraw/:id", b.Withdraw) // 撤回公告
+		bulletinRouter.GET("/receipts/:id", b.Receipts)  // 已读回执
+	}
+}
+
+func (b *BulletinController) List(ctx *gin.Context) {
+	var req reqModel.BulletinListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := bulletinService.List(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (b *BulletinController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	bulletin, err := bulletinService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(bulletin, ctx)
+}
+
+func (b *BulletinController) Create(ctx *gin.Context) {
+	var req reqModel.BulletinReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	bulletin, err := bulletinService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(bulletin, ctx)
+}
+
+func (b *BulletinController) Update(ctx *gin.Context) {
+	var req reqModel.BulletinReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	bulletin, err := bulletinService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(bulletin, ctx)
+}
+
+func (b *BulletinController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := bulletinService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (b *BulletinController) Publish(ctx *gin.Context) {
+	var req reqModel.BulletinPublishReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	bulletin, err := bulletinService.Publish(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(bulletin, ctx)
+}
+
+func (b *BulletinController) Withdraw(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err = bulletinService.Withdraw(uint(id)); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (b *BulletinController) Receipts(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	var info reqModel.PageInfo
+	if err = ctx.ShouldBindQuery(&info); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := bulletinService.Receipts(uint(id), info)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardController 仪表盘
+type DashboardController struct{}
+
+var (
+	dashboardService     = service.ServiceGroupApp.DashboardService
+	widgetRefreshService = service.ServiceGroupApp.WidgetRefreshService
+)
+
+func NewDashboardController() *DashboardController {
+	return &DashboardController{}
+}
+
+func (d *DashboardController) SetupRouter(g *gin.RouterGroup) {
+	dashboardRouter := g.Group("/dashboard")
+	{
+		dashboardRouter.GET("/list", d.List)                  // 仪表盘列表
+		dashboardRouter.GET("/:id", d.Get)                    // 仪表盘详情 含组件
+		dashboardRouter.POST("", d.Create)                    // 新增仪表盘
+		dashboardRouter.PUT("", d.Update)                     // 保存仪表盘 生成新版本
+		dashboardRouter.DELETE("", d.Delete)                  // 删除仪表盘
+		dashboardRouter.GET("/:id/versions", d.Versions)      // 历史版本
+		dashboardRouter.POST("/restore", d.Restore)           // 恢复历史版本
+		dashboardRouter.GET("/:id/data", d.Data)              // 各组件最近结果 自动刷新的组件返回缓存
+		dashboardRouter.GET("/widget/:id/data", d.WidgetData) // 组件数据
+	}
+}
+
+func (d *DashboardController) List(ctx *gin.Context) {
+	var req reqModel.PageInfo
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := dashboardService.List(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (d *DashboardController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	dashboard, err := dashboardService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(dashboard, ctx)
+}
+
+func (d *DashboardController) Create(ctx *gin.Context) {
+	var req reqModel.DashboardReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	dashboard, err := dashboardService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(dashboard, ctx)
+}
+
+func (d *DashboardController) Update(ctx *gin.Context) {
+	var req reqModel.DashboardReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	dashboard, err := dashboardService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(dashboard, ctx)
+}
+
+func (d *DashboardController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := dashboardService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (d *DashboardController) Versions(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	list, err := dashboardService.Versions(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(list, ctx)
+}
+
+func (d *DashboardController) Restore(ctx *gin.Context) {
+	var req reqModel.DashboardRestoreReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	dashboard, err := dashboardService.Restore(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(dashboard, ctx)
+}
+
+func (d *DashboardController) WidgetData(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	data, err := dashboardService.WidgetData(utils.GetUserID(ctx), uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(data, ctx)
+}
+
+func (d *DashboardController) Data(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	list, err := widgetRefreshService.Latest(utils.GetUserID(ctx), uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(list, ctx)
+}
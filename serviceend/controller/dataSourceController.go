@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataSourceController 外部数据源管理
+type DataSourceController struct{}
+
+var (
+	dataSourceService = service.ServiceGroupApp.DataSourceService
+)
+
+func NewDataSourceController() *DataSourceController {
+	return &DataSourceController{}
+}
+
+func (d *DataSourceController) SetupRouter(g *gin.RouterGroup) {
+	dataSourceRouter := g.Group("/dataSource")
+	{
+		dataSourceRouter.GET("/types", d.Types)        // 支持的数据源类型
+		dataSourceRouter.GET("/list", d.List)          // 数据源列表
+		dataSourceRouter.GET("/:id", d.Get)            // 数据源详情
+		dataSourceRouter.POST("", d.Create)            // 新增数据源
+		dataSourceRouter.PUT("", d.Update)             // 修改数据源
+		dataSourceRouter.DELETE("", d.Delete)          // 删除数据源
+		dataSourceRouter.POST("/test", d.Test)         // 测试连接参数
+		dataSourceRouter.POST("/test/:id", d.TestById) // 测试已保存的数据源
+	}
+}
+
+func (d *DataSourceController) Types(ctx *gin.Context) {
+	response.OkWithData(dataSourceService.Types(), ctx)
+}
+
+func (d *DataSourceController) List(ctx *gin.Context) {
+	var req reqModel.DataSourceListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := dataSourceService.List(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (d *DataSourceController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	source, err := dataSourceService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(source, ctx)
+}
+
+func (d *DataSourceController) Create(ctx *gin.Context) {
+	var req reqModel.DataSourceReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	source, err := dataSourceService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(source, ctx)
+}
+
+func (d *DataSourceController) Update(ctx *gin.Context) {
+	var req reqModel.DataSourceReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	source, err := dataSourceService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(source, ctx)
+}
+
+func (d *DataSourceController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := dataSourceService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (d *DataSourceController) Test(ctx *gin.Context) {
+	var req reqModel.DataSourceReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := dataSourceService.Test(ctx.Request.Context(), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithDetailed(res, "连接成功", ctx)
+}
+
+func (d *DataSourceController) TestById(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	res, err := dataSourceService.TestById(ctx.Request.Context(), uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithDetailed(res, "连接成功", ctx)
+}
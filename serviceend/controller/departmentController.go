@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DepartmentController 部门管理与成员分配
+type DepartmentController struct{}
+
+var (
+	departmentService = service.ServiceGroupApp.DepartmentService
+)
+
+func NewDepartmentController() *DepartmentController {
+	return &DepartmentController{}
+}
+
+func (d *DepartmentController) SetupRouter(g *gin.RouterGroup) {
+	departmentRouter := g.Group("/department")
+	{
+		departmentRouter.GET("/tree", d.Tree)                  // 部门树
+		departmentRouter.POST("", d.Create)                    // 新增部门
+		departmentRouter.PUT("", d.Update)                     // 修改部门
+		departmentRouter.DELETE("/:id", d.Delete)              // 删除部门
+		departmentRouter.POST("/move", d.Move)                 // 调整上级部门
+		departmentRouter.GET("/users", d.Users)                // 部门成员
+		departmentRouter.POST("/assign", d.AssignUser)         // 分配用户到部门
+		departmentRouter.POST("/remove/:userId", d.RemoveUser) // 将用户移出部门
+	}
+}
+
+func (d *DepartmentController) Tree(ctx *gin.Context) {
+	tree, err := departmentService.Tree(utils.GetUserID(ctx))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(tree, ctx)
+}
+
+func (d *DepartmentController) Create(ctx *gin.Context) {
+	var req reqModel.DepartmentReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	dept, err := departmentService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(dept, ctx)
+}
+
+func (d *DepartmentController) Update(ctx *gin.Context) {
+	var req reqModel.DepartmentReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	dept, err := departmentService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(dept, ctx)
+}
+
+func (d *DepartmentController) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err = departmentService.Delete(uint(id)); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (d *DepartmentController) Move(ctx *gin.Context) {
+	var req reqModel.DepartmentMoveReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := departmentService.Move(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (d *DepartmentController) Users(ctx *gin.Context) {
+	var req reqModel.DepartmentUsersReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := departmentService.Users(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (d *DepartmentController) AssignUser(ctx *gin.Context) {
+	var req reqModel.DepartmentUserReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := departmentService.AssignUser(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (d *DepartmentController) RemoveUser(ctx *gin.Context) {
+	userId, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err = departmentService.RemoveUser(uint(userId)); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"fmt"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExportController 导出查询结果为文件下载
+type ExportController struct{}
+
+var (
+	exportService = service.ServiceGroupApp.ExportService
+)
+
+func NewExportController() *ExportController {
+	return &ExportController{}
+}
+
+func (e *ExportController) SetupRouter(g *gin.RouterGroup) {
+	exportRouter := g.Group("/export")
+	{
+		exportRouter.POST("", e.Export) // 导出 csv/xlsx/json 文件
+	}
+}
+
+func (e *ExportController) Export(ctx *gin.Context) {
+	var req reqModel.ExportReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	export, err := exportService.Prepare(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	ctx.Header("Content-Type", export.ContentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(export.FileName)))
+	rows, err := export.Write(ctx.Writer)
+	if err == nil {
+		return
+	}
+	// 尚未写出内容时返回错误信息 已开始下载时只能中断
+	if !ctx.Writer.Written() {
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		response.FailWithError(err, ctx)
+		return
+	}
+	global.GvaLog.Error("导出中断", zap.String("file", export.FileName), zap.Int64("rows", rows), zap.Error(err))
+	ctx.Abort()
+}
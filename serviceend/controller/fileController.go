@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileController 文件上传下载
+type FileController struct{}
+
+var (
+	fileService = service.ServiceGroupApp.FileService
+)
+
+func NewFileController() *FileController {
+	return &FileController{}
+}
+
+func (f *FileController) SetupRouter(g *gin.RouterGroup) {
+	fileRouter := g.Group("/file")
+	{
+		fileRouter.POST("/upload", f.Upload)                      // 普通上传 表单字段 file
+		fileRouter.POST("/chunk/init", f.InitChunk)               // 创建/恢复分片上传任务
+		fileRouter.GET("/chunk/:uploadId", f.ChunkStatus)         // 分片上传进度
+		fileRouter.POST("/chunk/:uploadId/:index", f.UploadChunk) // 上传分片 表单字段 file, 可选 sha256
+		fileRouter.POST("/chunk/:uploadId/merge", f.MergeChunk)   // 合并分片
+		fileRouter.GET("/list", f.List)                           // 文件列表
+		fileRouter.GET("/:id", f.Get)                             // 文件详情
+		fileRouter.GET("/download/:id", f.Download)               // 下载 支持 Range 断点续传
+		fileRouter.GET("/verify/:id", f.Verify)                   // 完整性校验
+		fileRouter.POST("/enable/:id", f.Enable)                  // 启用文件
+		fileRouter.POST("/disable/:id", f.Disable)                // 禁用文件
+		fileRouter.DELETE("", f.Delete)                           // 删除文件
+	}
+}
+
+func (f *FileController) Upload(ctx *gin.Context) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		response.WithApiReturn(ApiReturn.NoFiles, ctx)
+		return
+	}
+	file, err := fileService.Upload(utils.GetUserID(ctx), header)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(file, ctx)
+}
+
+func (f *FileController) InitChunk(ctx *gin.Context) {
+	var req reqModel.FileChunkInitReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := fileService.InitChunk(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (f *FileController) ChunkStatus(ctx *gin.Context) {
+	res, err := fileService.ChunkStatus(utils.GetUserID(ctx), ctx.Param("uploadId"))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (f *FileController) UploadChunk(ctx *gin.Context) {
+	index, err := strconv.Atoi(ctx.Param("index"))
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		response.WithApiReturn(ApiReturn.NoFiles, ctx)
+		return
+	}
+	if err = fileService.UploadChunk(utils.GetUserID(ctx), ctx.Param("uploadId"), index, header, ctx.PostForm("sha256")); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (f *FileController) MergeChunk(ctx *gin.Context) {
+	file, err := fileService.MergeChunk(utils.GetUserID(ctx), ctx.Param("uploadId"))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(file, ctx)
+}
+
+func (f *FileController) List(ctx *gin.Context) {
+	var req reqModel.FileListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := fileService.List(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (f *FileController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	file, err := fileService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(file, ctx)
+}
+
+func (f *FileController) Download(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	file, reader, err := fileService.Open(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	defer reader.Close()
+	if file.MimeType != "" {
+		ctx.Header("Content-Type", file.MimeType)
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(file.Name)))
+	ctx.Header("ETag", `"`+file.Sha256+`"`)
+	// ServeContent 处理 Range/If-Range 请求 返回 206 分段内容
+	http.ServeContent(ctx.Writer, ctx.Request, file.Name, time.Time(file.UpdatedAt), reader)
+}
+
+func (f *FileController) Verify(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	res, err := fileService.Verify(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (f *FileController) Enable(ctx *gin.Context) {
+	f.setStatus(ctx, dbModel.FileEnable)
+}
+
+func (f *FileController) Disable(ctx *gin.Context) {
+	f.setStatus(ctx, dbModel.FileDisable)
+}
+
+func (f *FileController) setStatus(ctx *gin.Context, status int) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err = fileService.SetStatus(uint(id), status); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (f *FileController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := fileService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"context"
 	"dataPanel/serviceend/common/response"
 	"dataPanel/serviceend/global"
 	"dataPanel/serviceend/service"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type HelloController struct{}
@@ -20,7 +23,8 @@ func NewHelloController() *HelloController {
 func (h *HelloController) SetupRouter(g *gin.RouterGroup) {
 	helloRouter := g.Group("/")
 	{
-		helloRouter.GET("/hello", h.GetHello) // 健康监测
+		helloRouter.GET("/hello", h.GetHello)   // 健康监测
+		helloRouter.GET("/health", h.GetHealth) // 数据库健康检测
 	}
 }
 
@@ -32,3 +36,14 @@ func (h *HelloController) GetHello(ctx *gin.Context) {
 		response.FailWithMessage("系统异常", ctx)
 	}
 }
+
+func (h *HelloController) GetHealth(ctx *gin.Context) {
+	c, cancel := context.WithTimeout(ctx.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := helloService.DbHealth(c); err != nil {
+		global.GvaLog.Error("数据库健康检测失败", zap.Error(err))
+		response.FailWithMessage("数据库连接异常", ctx)
+		return
+	}
+	response.OkWithMessage("ok", ctx)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportController 导入 csv/xlsx 到本地数据库 文件先通过 /file/upload 上传
+type ImportController struct{}
+
+var (
+	importService = service.ServiceGroupApp.ImportService
+)
+
+func NewImportController() *ImportController {
+	return &ImportController{}
+}
+
+func (i *ImportController) SetupRouter(g *gin.RouterGroup) {
+	importRouter := g.Group("/import")
+	{
+		importRouter.GET("/tables", i.Tables)    // 可导入的表
+		importRouter.POST("/preview", i.Preview) // 预览文件并推断列类型
+		importRouter.POST("", i.Import)          // 导入
+	}
+}
+
+func (i *ImportController) Tables(ctx *gin.Context) {
+	list, err := importService.Tables()
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(list, ctx)
+}
+
+func (i *ImportController) Preview(ctx *gin.Context) {
+	var req reqModel.ImportPreviewReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	// 接口只能导入已上传的文件
+	req.Path = ""
+	res, err := importService.Preview(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (i *ImportController) Import(ctx *gin.Context) {
+	var req reqModel.ImportReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.Path = ""
+	res, err := importService.Import(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
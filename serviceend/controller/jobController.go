@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobController 定时任务
+type JobController struct{}
+
+var (
+	jobService = service.ServiceGroupApp.JobService
+)
+
+func NewJobController() *JobController {
+	return &JobController{}
+}
+
+func (j *JobController) SetupRouter(g *gin.RouterGroup) {
+	jobRouter := g.Group("/job")
+	{
+		jobRouter.GET("/list", j.List)              // 已注册的任务
+		jobRouter.POST("/:name/trigger", j.Trigger) // 手动执行
+		jobRouter.POST("/:name/pause", j.Pause)     // 暂停计划执行
+		jobRouter.POST("/:name/resume", j.Resume)   // 恢复计划执行
+		jobRouter.GET("/logs", j.Logs)              // 执行记录
+	}
+}
+
+func (j *JobController) List(ctx *gin.Context) {
+	list, err := jobService.List()
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(list, ctx)
+}
+
+func (j *JobController) Trigger(ctx *gin.Context) {
+	if err := jobService.Trigger(ctx.Param("name")); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (j *JobController) Pause(ctx *gin.Context) {
+	if err := jobService.Pause(ctx.Param("name")); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (j *JobController) Resume(ctx *gin.Context) {
+	if err := jobService.Resume(ctx.Param("name")); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (j *JobController) Logs(ctx *gin.Context) {
+	var req reqModel.JobRunListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := jobService.Logs(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
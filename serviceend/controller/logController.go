@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/service"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogController 运行日志
+type LogController struct{}
+
+var (
+	logService = service.ServiceGroupApp.LogService
+)
+
+func NewLogController() *LogController {
+	return &LogController{}
+}
+
+func (l *LogController) SetupRouter(g *gin.RouterGroup) {
+	logRouter := g.Group("/log")
+	{
+		logRouter.GET("/level", l.Level)           // 当前日志级别
+		logRouter.PUT("/level", l.SetLevel)        // 修改日志级别 可指定到期自动恢复
+		logRouter.GET("/files", l.Files)           // 日志文件列表
+		logRouter.POST("/search", l.Search)        // 搜索日志
+		logRouter.GET("/tail", l.Tail)             // 实时跟踪 以 SSE 推送 log:tail 事件
+		logRouter.POST("/tail/start", l.StartTail) // 开始向桌面窗口推送 log:tail 事件
+		logRouter.POST("/tail/stop", l.StopTail)   // 停止向桌面窗口推送
+	}
+}
+
+func (l *LogController) Level(ctx *gin.Context) {
+	response.OkWithData(logService.Level(), ctx)
+}
+
+func (l *LogController) SetLevel(ctx *gin.Context) {
+	var req reqModel.LogLevelReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := logService.SetLevel(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (l *LogController) Files(ctx *gin.Context) {
+	var req reqModel.LogFileListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := logService.Files(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (l *LogController) Search(ctx *gin.Context) {
+	var req reqModel.LogSearchReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := logService.Search(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+// Tail 条件以查询参数传入 连接断开时取消订阅
+func (l *LogController) Tail(ctx *gin.Context) {
+	var req reqModel.LogFilterReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	ch := make(chan []resModel.LogEntry, 16)
+	cancel, err := logService.Tail(req, func(list []resModel.LogEntry) {
+		// 客户端读取过慢时丢弃
+		select {
+		case ch <- list:
+		default:
+		}
+	})
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	defer cancel()
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case list := <-ch:
+			ctx.SSEvent(service.EventLogTail, list)
+			return true
+		}
+	})
+}
+
+func (l *LogController) StartTail(ctx *gin.Context) {
+	var req reqModel.LogFilterReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := logService.StartTail(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (l *LogController) StopTail(ctx *gin.Context) {
+	logService.StopTail()
+	response.Ok(ctx)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MenuController 菜单管理
+type MenuController struct{}
+
+var (
+	menuService = service.ServiceGroupApp.MenuService
+)
+
+func NewMenuController() *MenuController {
+	return &MenuController{}
+}
+
+func (m *MenuController) SetupRouter(g *gin.RouterGroup) {
+	menuRouter := g.Group("/menu")
+	{
+		menuRouter.GET("/tree", m.Tree)     // 全部菜单树
+		menuRouter.POST("", m.Create)       // 新增菜单
+		menuRouter.PUT("", m.Update)        // 修改菜单
+		menuRouter.DELETE("/:id", m.Delete) // 删除菜单
+	}
+}
+
+func (m *MenuController) Tree(ctx *gin.Context) {
+	tree, err := menuService.Tree()
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(tree, ctx)
+}
+
+func (m *MenuController) Create(ctx *gin.Context) {
+	var req reqModel.MenuReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	menu, err := menuService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(menu, ctx)
+}
+
+func (m *MenuController) Update(ctx *gin.Context) {
+	var req reqModel.MenuReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	menu, err := menuService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(menu, ctx)
+}
+
+func (m *MenuController) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err = menuService.Delete(uint(id)); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
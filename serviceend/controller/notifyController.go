@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotifyController 通知渠道与投递记录
+type NotifyController struct{}
+
+var (
+	notifyService = service.ServiceGroupApp.NotifyService
+)
+
+func NewNotifyController() *NotifyController {
+	return &NotifyController{}
+}
+
+func (n *NotifyController) SetupRouter(g *gin.RouterGroup) {
+	notifyRouter := g.Group("/notify")
+	{
+		notifyRouter.GET("/channels", n.Channels) // 已配置的通知渠道
+		notifyRouter.POST("/test/:name", n.Test)  // 发送测试通知
+		notifyRouter.GET("/logs", n.Logs)         // 投递记录
+	}
+}
+
+func (n *NotifyController) Channels(ctx *gin.Context) {
+	list, err := notifyService.Channels()
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(list, ctx)
+}
+
+func (n *NotifyController) Test(ctx *gin.Context) {
+	if err := notifyService.Test(ctx.Param("name")); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (n *NotifyController) Logs(ctx *gin.Context) {
+	var req reqModel.NotifyLogListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := notifyService.Logs(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
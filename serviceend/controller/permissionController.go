@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionController 接口权限管理
+type PermissionController struct{}
+
+var (
+	permissionService = service.ServiceGroupApp.PermissionService
+)
+
+func NewPermissionController() *PermissionController {
+	return &PermissionController{}
+}
+
+func (p *PermissionController) SetupRouter(g *gin.RouterGroup) {
+	permissionRouter := g.Group("/permission")
+	{
+		permissionRouter.GET("/list", p.List)      // 接口权限列表
+		permissionRouter.POST("", p.Create)        // 新增接口权限
+		permissionRouter.PUT("", p.Update)         // 修改接口权限
+		permissionRouter.DELETE("", p.Delete)      // 删除接口权限
+		permissionRouter.POST("/reload", p.Reload) // 重新加载权限策略
+	}
+}
+
+func (p *PermissionController) List(ctx *gin.Context) {
+	var info reqModel.PageInfo
+	if err := ctx.ShouldBindQuery(&info); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := permissionService.List(info)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (p *PermissionController) Create(ctx *gin.Context) {
+	var req reqModel.PermissionReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	perm, err := permissionService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(perm, ctx)
+}
+
+func (p *PermissionController) Update(ctx *gin.Context) {
+	var req reqModel.PermissionReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	perm, err := permissionService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(perm, ctx)
+}
+
+func (p *PermissionController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := permissionService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (p *PermissionController) Reload(ctx *gin.Context) {
+	if err := permissionService.ReloadPolicy(); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
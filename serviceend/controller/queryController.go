@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryController 即席查询
+type QueryController struct{}
+
+var (
+	queryService = service.ServiceGroupApp.QueryService
+)
+
+func NewQueryController() *QueryController {
+	return &QueryController{}
+}
+
+func (q *QueryController) SetupRouter(g *gin.RouterGroup) {
+	queryRouter := g.Group("/query")
+	{
+		queryRouter.POST("/run", q.Run)                // 执行查询 返回首页结果
+		queryRouter.GET("/:queryId/next", q.Next)      // 获取下一页结果
+		queryRouter.POST("/:queryId/cancel", q.Cancel) // 取消查询
+		queryRouter.GET("/logs", q.Logs)               // 查询执行记录
+	}
+}
+
+func (q *QueryController) Run(ctx *gin.Context) {
+	var req reqModel.QueryRunReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := queryService.Run(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (q *QueryController) Next(ctx *gin.Context) {
+	var req reqModel.QueryNextReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := queryService.Next(utils.GetUserID(ctx), ctx.Param("queryId"), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (q *QueryController) Cancel(ctx *gin.Context) {
+	if err := queryService.Cancel(utils.GetUserID(ctx), ctx.Param("queryId")); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (q *QueryController) Logs(ctx *gin.Context) {
+	var req reqModel.QueryLogListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := queryService.Logs(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleController 角色管理与授权
+type RoleController struct{}
+
+var (
+	roleService = service.ServiceGroupApp.RoleService
+)
+
+func NewRoleController() *RoleController {
+	return &RoleController{}
+}
+
+func (r *RoleController) SetupRouter(g *gin.RouterGroup) {
+	roleRouter := g.Group("/role")
+	{
+		roleRouter.GET("/list", r.List)                   // 角色列表
+		roleRouter.GET("/:id", r.Get)                     // 角色详情
+		roleRouter.POST("", r.Create)                     // 新增角色
+		roleRouter.PUT("", r.Update)                      // 修改角色
+		roleRouter.DELETE("", r.Delete)                   // 删除角色
+		roleRouter.POST("/permissions", r.SetPermissions) // 设置角色接口权限
+		roleRouter.POST("/menus", r.SetMenus)             // 设置角色菜单
+		roleRouter.POST("/userRoles", r.SetUserRoles)     // 为用户分配角色
+	}
+}
+
+func (r *RoleController) List(ctx *gin.Context) {
+	var info reqModel.PageInfo
+	if err := ctx.ShouldBindQuery(&info); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := roleService.List(info)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (r *RoleController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	role, err := roleService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(role, ctx)
+}
+
+func (r *RoleController) Create(ctx *gin.Context) {
+	var req reqModel.RoleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	role, err := roleService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(role, ctx)
+}
+
+func (r *RoleController) Update(ctx *gin.Context) {
+	var req reqModel.RoleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	role, err := roleService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(role, ctx)
+}
+
+func (r *RoleController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := roleService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (r *RoleController) SetPermissions(ctx *gin.Context) {
+	var req reqModel.RoleAuthReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := roleService.SetPermissions(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (r *RoleController) SetMenus(ctx *gin.Context) {
+	var req reqModel.RoleAuthReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := roleService.SetMenus(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (r *RoleController) SetUserRoles(ctx *gin.Context) {
+	var req reqModel.UserRoleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := roleService.SetUserRoles(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedQueryController 保存的查询
+type SavedQueryController struct{}
+
+var (
+	savedQueryService = service.ServiceGroupApp.SavedQueryService
+)
+
+func NewSavedQueryController() *SavedQueryController {
+	return &SavedQueryController{}
+}
+
+func (s *SavedQueryController) SetupRouter(g *gin.RouterGroup) {
+	savedQueryRouter := g.Group("/savedQuery")
+	{
+		savedQueryRouter.GET("/list", s.List)            // 保存的查询列表
+		savedQueryRouter.GET("/:id", s.Get)              // 保存的查询详情
+		savedQueryRouter.POST("", s.Create)              // 新增保存的查询
+		savedQueryRouter.PUT("", s.Update)               // 修改保存的查询
+		savedQueryRouter.DELETE("", s.Delete)            // 删除保存的查询
+		savedQueryRouter.POST("/:id/execute", s.Execute) // 按参数执行 返回首页结果
+	}
+}
+
+func (s *SavedQueryController) List(ctx *gin.Context) {
+	var req reqModel.SavedQueryListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := savedQueryService.List(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (s *SavedQueryController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	query, err := savedQueryService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(query, ctx)
+}
+
+func (s *SavedQueryController) Create(ctx *gin.Context) {
+	var req reqModel.SavedQueryReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	query, err := savedQueryService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(query, ctx)
+}
+
+func (s *SavedQueryController) Update(ctx *gin.Context) {
+	var req reqModel.SavedQueryReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	query, err := savedQueryService.Save(utils.GetUserID(ctx), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(query, ctx)
+}
+
+func (s *SavedQueryController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := savedQueryService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (s *SavedQueryController) Execute(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	var req reqModel.SavedQueryExecReq
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := savedQueryService.Execute(utils.GetUserID(ctx), uint(id), req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionController 登录会话管理 查看在线会话与强制下线
+type SessionController struct{}
+
+var (
+	sessionService = service.ServiceGroupApp.SessionService
+)
+
+func NewSessionController() *SessionController {
+	return &SessionController{}
+}
+
+func (s *SessionController) SetupRouter(g *gin.RouterGroup) {
+	sessionRouter := g.Group("/session")
+	{
+		sessionRouter.GET("/list", s.List)                            // 在线会话列表 可按 userId 过滤
+		sessionRouter.DELETE("/:sessionId", s.Terminate)              // 强制下线指定会话
+		sessionRouter.DELETE("/user/:userId", s.TerminateUserSession) // 强制下线用户全部会话
+	}
+}
+
+func (s *SessionController) List(ctx *gin.Context) {
+	var userId uint64
+	if id := ctx.Query("userId"); id != "" {
+		var err error
+		if userId, err = strconv.ParseUint(id, 10, 64); err != nil {
+			response.WithApiReturn(ApiReturn.ErrParam, ctx)
+			return
+		}
+	}
+	response.OkWithData(sessionService.List(uint(userId)), ctx)
+}
+
+func (s *SessionController) Terminate(ctx *gin.Context) {
+	sessionId := ctx.Param("sessionId")
+	if _, ok := sessionService.Get(sessionId); !ok {
+		response.WithApiReturn(ApiReturn.NoData, ctx)
+		return
+	}
+	if err := sessionService.Remove(sessionId); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithMessage("已强制下线", ctx)
+}
+
+func (s *SessionController) TerminateUserSession(ctx *gin.Context) {
+	userId, err := strconv.ParseUint(ctx.Param("userId"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	count, err := sessionService.RemoveByUser(uint(userId))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithDetailed(gin.H{"count": count}, "已强制下线", ctx)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserController 需登录的用户接口
+type UserController struct{}
+
+func NewUserController() *UserController {
+	return &UserController{}
+}
+
+func (u *UserController) SetupRouter(g *gin.RouterGroup) {
+	userRouter := g.Group("/user")
+	{
+		userRouter.POST("/logout", u.Logout)                   // 退出登录
+		userRouter.GET("/info", u.GetInfo)                     // 当前用户信息
+		userRouter.GET("/menus", u.GetMenus)                   // 当前用户可见的菜单树
+		userRouter.GET("/bulletins", u.GetBulletins)           // 当前用户的公告
+		userRouter.GET("/bulletins/unread", u.UnreadBulletins) // 未读公告数
+		userRouter.POST("/bulletins/:id/read", u.ReadBulletin) // 标记公告已读
+	}
+}
+
+func (u *UserController) Logout(ctx *gin.Context) {
+	var req reqModel.LogoutReq
+	_ = ctx.ShouldBindJSON(&req)
+	claims := utils.GetClaims(ctx)
+	if claims == nil {
+		response.WithApiReturn(ApiReturn.UnauthorizedAccess, ctx)
+		return
+	}
+	if err := userService.Logout(claims, req.RefreshToken); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithMessage("退出成功", ctx)
+}
+
+func (u *UserController) GetInfo(ctx *gin.Context) {
+	user, err := userService.GetUserInfo(utils.GetUserID(ctx))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(user, ctx)
+}
+
+func (u *UserController) GetMenus(ctx *gin.Context) {
+	tree, err := menuService.UserTree(utils.GetUserID(ctx))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(tree, ctx)
+}
+
+func (u *UserController) GetBulletins(ctx *gin.Context) {
+	var info reqModel.PageInfo
+	if err := ctx.ShouldBindQuery(&info); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := bulletinService.MyBulletins(utils.GetUserID(ctx), info)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (u *UserController) UnreadBulletins(ctx *gin.Context) {
+	count, err := bulletinService.UnreadCount(utils.GetUserID(ctx))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(gin.H{"count": count}, ctx)
+}
+
+func (u *UserController) ReadBulletin(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err = bulletinService.MarkRead(utils.GetUserID(ctx), uint(id)); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
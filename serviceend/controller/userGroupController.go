@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserGroupController 用户群管理 公告的面向人群
+type UserGroupController struct{}
+
+var (
+	userGroupService = service.ServiceGroupApp.UserGroupService
+)
+
+func NewUserGroupController() *UserGroupController {
+	return &UserGroupController{}
+}
+
+func (u *UserGroupController) SetupRouter(g *gin.RouterGroup) {
+	groupRouter := g.Group("/userGroup")
+	{
+		groupRouter.GET("/list", u.List)           // 用户群列表
+		groupRouter.GET("/:id", u.Get)             // 用户群详情
+		groupRouter.POST("", u.Create)             // 新增用户群
+		groupRouter.PUT("", u.Update)              // 修改用户群
+		groupRouter.DELETE("", u.Delete)           // 删除用户群
+		groupRouter.POST("/members", u.SetMembers) // 设置用户群成员
+	}
+}
+
+func (u *UserGroupController) List(ctx *gin.Context) {
+	var info reqModel.PageInfo
+	if err := ctx.ShouldBindQuery(&info); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	res, err := userGroupService.List(info)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(res, ctx)
+}
+
+func (u *UserGroupController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	group, err := userGroupService.Get(uint(id))
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(group, ctx)
+}
+
+func (u *UserGroupController) Create(ctx *gin.Context) {
+	var req reqModel.UserGroupReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	req.ID = 0
+	group, err := userGroupService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(group, ctx)
+}
+
+func (u *UserGroupController) Update(ctx *gin.Context) {
+	var req reqModel.UserGroupReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if req.ID == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	group, err := userGroupService.Save(req)
+	if err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.OkWithData(group, ctx)
+}
+
+func (u *UserGroupController) Delete(ctx *gin.Context) {
+	var req reqModel.IdsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil || len(req.Ids) == 0 {
+		response.WithApiReturn(ApiReturn.ErrParam, ctx)
+		return
+	}
+	if err := userGroupService.Delete(req.Ids); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
+
+func (u *UserGroupController) SetMembers(ctx *gin.Context) {
+	var req reqModel.UserGroupMemberReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.FailWithValidate(err, ctx)
+		return
+	}
+	if err := userGroupService.SetMembers(req); err != nil {
+		response.FailWithError(err, ctx)
+		return
+	}
+	response.Ok(ctx)
+}
@@ -6,11 +6,14 @@ import (
 	ut "github.com/go-playground/universal-translator"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 var (
-	GvaConfig configModel.ServerConfig
-	GavVp     *viper.Viper
-	GvaLog    *zap.Logger
-	GvaTrans  *ut.Translator
+	GvaConfig   configModel.ServerConfig
+	GavVp       *viper.Viper
+	GvaLog      *zap.Logger
+	GvaLogLevel = zap.NewAtomicLevel() // 日志级别 运行时修改立即生效
+	GvaTrans    *ut.Translator
+	GvaDb       *gorm.DB
 )
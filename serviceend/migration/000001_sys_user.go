@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/utils"
+
+	"gorm.io/gorm"
+)
+
+// 初始化用户表与令牌黑名单 并创建默认管理员 admin/123456
+func init() {
+	Register(Migration{
+		Version: 1,
+		Name:    "create_sys_user",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&dbModel.SysUser{}, &dbModel.SysJwtBlacklist{}); err != nil {
+				return err
+			}
+			password, err := utils.BcryptHash("123456")
+			if err != nil {
+				return err
+			}
+			return tx.Create(&dbModel.SysUser{
+				Username: "admin",
+				Password: password,
+				NickName: "超级管理员",
+				Enable:   dbModel.UserEnable,
+			}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysJwtBlacklist{}, &dbModel.SysUser{})
+		},
+	})
+}
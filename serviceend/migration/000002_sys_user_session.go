@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 登录会话表 用于多点登录拦截与会话管理
+func init() {
+	Register(Migration{
+		Version: 2,
+		Name:    "create_sys_user_session",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysUserSession{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysUserSession{})
+		},
+	})
+}
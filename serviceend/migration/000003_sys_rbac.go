@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 角色、接口权限、菜单 并为默认管理员分配超级管理员角色
+func init() {
+	Register(Migration{
+		Version: 3,
+		Name:    "create_sys_rbac",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&dbModel.SysPermission{}, &dbModel.SysMenu{}, &dbModel.SysRole{}, &dbModel.SysUser{}); err != nil {
+				return err
+			}
+			role := dbModel.SysRole{
+				RoleName: "超级管理员",
+				RoleKey:  dbModel.RoleKeySuperAdmin,
+				Status:   dbModel.RoleEnable,
+				Remark:   "拥有全部接口与菜单权限",
+			}
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+			var admin dbModel.SysUser
+			if err := tx.Where("username = ?", "admin").Limit(1).Find(&admin).Error; err != nil || admin.ID == 0 {
+				return err
+			}
+			return tx.Model(&admin).Association("Roles").Append(&role)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("sys_user_roles", "sys_role_menus", "sys_role_permissions",
+				&dbModel.SysRole{}, &dbModel.SysMenu{}, &dbModel.SysPermission{})
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 部门表 用户归属部门 角色数据权限
+func init() {
+	Register(Migration{
+		Version: 4,
+		Name:    "create_sys_department",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysDepartment{}, &dbModel.SysUser{}, &dbModel.SysRole{})
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, column := range []struct {
+				model interface{}
+				name  string
+			}{{&dbModel.SysUser{}, "department_id"}, {&dbModel.SysRole{}, "data_scope"}} {
+				if tx.Migrator().HasColumn(column.model, column.name) {
+					if err := tx.Migrator().DropColumn(column.model, column.name); err != nil {
+						return err
+					}
+				}
+			}
+			return tx.Migrator().DropTable(&dbModel.SysDepartment{})
+		},
+	})
+}
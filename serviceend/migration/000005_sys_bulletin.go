@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 用户群 公告 公告已读回执
+func init() {
+	Register(Migration{
+		Version: 5,
+		Name:    "create_sys_bulletin",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysUserGroup{}, &dbModel.SysBulletin{}, &dbModel.SysBulletinRead{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("sys_bulletin_groups", "sys_user_group_members",
+				&dbModel.SysBulletinRead{}, &dbModel.SysBulletin{}, &dbModel.SysUserGroup{})
+		},
+	})
+}
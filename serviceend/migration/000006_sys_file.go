@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 文件记录 分片上传任务
+func init() {
+	Register(Migration{
+		Version: 6,
+		Name:    "create_sys_file",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysFile{}, &dbModel.SysFileUpload{}, &dbModel.SysFileChunk{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysFileChunk{}, &dbModel.SysFileUpload{}, &dbModel.SysFile{})
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 短信/邮件验证码
+func init() {
+	Register(Migration{
+		Version: 7,
+		Name:    "create_sys_verification_code",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysVerificationCode{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysVerificationCode{})
+		},
+	})
+}
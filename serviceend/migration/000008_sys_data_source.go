@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 外部数据源
+func init() {
+	Register(Migration{
+		Version: 8,
+		Name:    "create_sys_data_source",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysDataSource{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysDataSource{})
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 即席查询 数据源增加可写标记与查询记录
+func init() {
+	Register(Migration{
+		Version: 9,
+		Name:    "create_sys_query_log",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysDataSource{}, &dbModel.SysQueryLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&dbModel.SysQueryLog{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&dbModel.SysDataSource{}, "Writable")
+		},
+	})
+}
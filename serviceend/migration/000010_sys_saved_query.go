@@ -0,0 +1,24 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 保存的查询 查询记录关联保存的查询
+func init() {
+	Register(Migration{
+		Version: 10,
+		Name:    "create_sys_saved_query",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysSavedQuery{}, &dbModel.SysQueryLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&dbModel.SysSavedQuery{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&dbModel.SysQueryLog{}, "SavedQueryId")
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 仪表盘 组件 历史版本
+func init() {
+	Register(Migration{
+		Version: 11,
+		Name:    "create_sys_dashboard",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysDashboard{}, &dbModel.SysDashboardWidget{}, &dbModel.SysDashboardVersion{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysDashboardVersion{}, &dbModel.SysDashboardWidget{}, &dbModel.SysDashboard{})
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 仪表盘组件自动刷新间隔
+func init() {
+	Register(Migration{
+		Version: 12,
+		Name:    "add_widget_refresh_interval",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysDashboardWidget{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&dbModel.SysDashboardWidget{}, "RefreshInterval")
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 定时任务 暂停状态与执行记录
+func init() {
+	Register(Migration{
+		Version: 13,
+		Name:    "create_sys_job",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysJob{}, &dbModel.SysJobRun{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysJobRun{}, &dbModel.SysJob{})
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 告警规则与告警记录
+func init() {
+	Register(Migration{
+		Version: 14,
+		Name:    "create_sys_alert",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysAlertRule{}, &dbModel.SysAlertEvent{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysAlertEvent{}, &dbModel.SysAlertRule{})
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+
+	"gorm.io/gorm"
+)
+
+// 通知投递记录
+func init() {
+	Register(Migration{
+		Version: 15,
+		Name:    "create_sys_notify_log",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dbModel.SysNotifyLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dbModel.SysNotifyLog{})
+		},
+	})
+}
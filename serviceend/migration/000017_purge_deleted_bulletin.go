@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// 清除已软删除的用户群与公告 这些表的唯一索引包含已删除的行, 之后改为物理删除
+func init() {
+	Register(Migration{
+		Version: 17,
+		Name:    "purge_deleted_bulletin",
+		Up: func(tx *gorm.DB) error {
+			for _, table := range []string{"sys_user_groups", "sys_bulletins"} {
+				if err := tx.Exec("DELETE FROM " + table + " WHERE deleted_at IS NOT NULL").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		// 已清除的行无法恢复
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// 清除已软删除的数据源 名称唯一索引包含已删除的行, 之后改为物理删除
+func init() {
+	Register(Migration{
+		Version: 18,
+		Name:    "purge_deleted_data_source",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM sys_data_sources WHERE deleted_at IS NOT NULL").Error
+		},
+		// 已清除的行无法恢复
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
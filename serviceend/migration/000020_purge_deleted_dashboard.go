@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// 清除已软删除的仪表盘与组件 仪表盘名称唯一索引包含已删除的行, 之后二者改为物理删除
+func init() {
+	Register(Migration{
+		Version: 20,
+		Name:    "purge_deleted_dashboard",
+		Up: func(tx *gorm.DB) error {
+			for _, table := range []string{"sys_dashboard_widgets", "sys_dashboards"} {
+				if err := tx.Exec("DELETE FROM " + table + " WHERE deleted_at IS NOT NULL").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		// 已清除的行无法恢复
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// 清除已软删除的告警规则 名称唯一索引包含已删除的行, 之后改为物理删除
+func init() {
+	Register(Migration{
+		Version: 21,
+		Name:    "purge_deleted_alert_rule",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM sys_alert_rules WHERE deleted_at IS NOT NULL").Error
+		},
+		// 已清除的行无法恢复
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
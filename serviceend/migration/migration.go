@@ -0,0 +1,160 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// 嵌入的 sql 迁移脚本 命名规则: {版本号}_{名称}.{up|down}[.{方言}].sql
+// 例: 000003_add_index.up.sql / 000003_add_index.up.postgres.sql, 方言脚本优先于通用脚本
+//
+//go:embed scripts
+var scripts embed.FS
+
+var scriptNameReg = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)(?:\.(mysql|postgres|sqlite))?\.sql$`)
+
+// MigrateFunc go 迁移方法 在事务中执行
+type MigrateFunc func(tx *gorm.DB) error
+
+// Migration 单个版本迁移
+type Migration struct {
+	Version int64       // 版本号 全局唯一且递增
+	Name    string      // 名称
+	Up      MigrateFunc // 升级
+	Down    MigrateFunc // 回滚
+
+	upSql   map[string]string // 方言 => 升级脚本, "" 为通用脚本
+	downSql map[string]string // 方言 => 回滚脚本
+}
+
+var (
+	registry   = map[int64]*Migration{}
+	registryMu sync.Mutex
+)
+
+// Register 注册 go 迁移 一般在迁移文件的 init 中调用
+func Register(m Migration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if m.Version <= 0 || m.Up == nil {
+		panic(fmt.Sprintf("迁移注册失败: 版本号必须大于0且必须提供 Up 方法, version=%d", m.Version))
+	}
+	if _, ok := registry[m.Version]; ok {
+		panic(fmt.Sprintf("迁移注册失败: 版本号重复 version=%d", m.Version))
+	}
+	registry[m.Version] = &m
+}
+
+// Migrations 合并 go 迁移与嵌入的 sql 脚本 按版本号升序返回
+func Migrations() ([]*Migration, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	all := make(map[int64]*Migration, len(registry))
+	for v, m := range registry {
+		all[v] = m
+	}
+	err := fs.WalkDir(scripts, "scripts", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		match := scriptNameReg.FindStringSubmatch(path.Base(p))
+		if match == nil {
+			return nil // 非迁移脚本(如说明文档)忽略
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := scripts.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		m, ok := all[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			all[version] = m
+		}
+		if m.Up != nil || m.Down != nil {
+			return fmt.Errorf("版本号 %d 同时存在 go 迁移与 sql 脚本", version)
+		}
+		if m.Name != match[2] {
+			return fmt.Errorf("版本号 %d 对应多个名称: %s, %s", version, m.Name, match[2])
+		}
+		if m.upSql == nil {
+			m.upSql, m.downSql = map[string]string{}, map[string]string{}
+		}
+		if match[3] == "up" {
+			m.upSql[match[4]] = string(content)
+		} else {
+			m.downSql[match[4]] = string(content)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Migration, 0, len(all))
+	for _, m := range all {
+		if m.IsSql() && len(m.upSql) == 0 {
+			return nil, fmt.Errorf("版本号 %d 缺少 up 脚本", m.Version)
+		}
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// IsSql 是否为 sql 脚本迁移
+func (m *Migration) IsSql() bool {
+	return m.upSql != nil
+}
+
+// UpSql 获取指定方言的升级脚本
+func (m *Migration) UpSql(dialect string) (string, bool) {
+	return pickSql(m.upSql, dialect)
+}
+
+// DownSql 获取指定方言的回滚脚本
+func (m *Migration) DownSql(dialect string) (string, bool) {
+	return pickSql(m.downSql, dialect)
+}
+
+func pickSql(scripts map[string]string, dialect string) (string, bool) {
+	if s, ok := scripts[dialect]; ok {
+		return s, true
+	}
+	s, ok := scripts[""]
+	return s, ok
+}
+
+// splitStatements 按行尾分号拆分多条语句 部分驱动不支持一次执行多条
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		builder    strings.Builder
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			if stmt := strings.TrimSpace(builder.String()); stmt != ";" {
+				statements = append(statements, stmt)
+			}
+			builder.Reset()
+		}
+	}
+	if stmt := strings.TrimSpace(builder.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
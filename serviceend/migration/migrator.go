@@ -0,0 +1,240 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dataPanel/serviceend/global"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrSchemaNewer 数据库结构版本高于程序内置的最新迁移版本
+var ErrSchemaNewer = errors.New("数据库结构版本高于当前程序版本,请升级程序后再启动")
+
+// SchemaMigration 迁移版本记录表
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"size:191;not null" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"appliedAt"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "sys_schema_migrations"
+}
+
+// Status 迁移状态
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Kind      string     `json:"kind"` // go|sql
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db     *gorm.DB
+	dryRun bool // 仅打印将要执行的迁移,不实际执行
+}
+
+func NewMigrator(db *gorm.DB, dryRun bool) *Migrator {
+	return &Migrator{db: db, dryRun: dryRun}
+}
+
+// dialect 当前数据库方言 mysql|postgres|sqlite
+func (m *Migrator) dialect() string {
+	return m.db.Dialector.Name()
+}
+
+func (m *Migrator) ensureTable() error {
+	return m.db.AutoMigrate(&SchemaMigration{})
+}
+
+// applied 已执行的迁移 版本号 => 记录
+// dry-run 不建表, 版本记录表不存在时视为尚未执行任何迁移
+func (m *Migrator) applied() (map[int64]SchemaMigration, error) {
+	if m.dryRun {
+		if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+			return map[int64]SchemaMigration{}, nil
+		}
+	} else if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	var records []SchemaMigration
+	if err := m.db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]SchemaMigration, len(records))
+	for _, r := range records {
+		result[r.Version] = r
+	}
+	return result, nil
+}
+
+// Check 校验数据库版本不高于程序版本
+func (m *Migrator) Check() error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	var latest int64
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	for version := range applied {
+		if version > latest {
+			return fmt.Errorf("%w: 数据库版本 %d, 程序版本 %d", ErrSchemaNewer, version, latest)
+		}
+	}
+	return nil
+}
+
+// Status 查询全部迁移的执行状态
+func (m *Migrator) Status() ([]Status, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Status, 0, len(migrations))
+	for _, mg := range migrations {
+		s := Status{Version: mg.Version, Name: mg.Name, Kind: "go"}
+		if mg.IsSql() {
+			s.Kind = "sql"
+		}
+		if r, ok := applied[mg.Version]; ok {
+			appliedAt := r.AppliedAt
+			s.Applied, s.AppliedAt = true, &appliedAt
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// Up 按版本号升序执行全部未执行的迁移 返回执行(dry-run 时为将要执行)的迁移
+func (m *Migrator) Up() ([]*Migration, error) {
+	if err := m.Check(); err != nil {
+		return nil, err
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var done []*Migration
+	for _, mg := range migrations {
+		if _, ok := applied[mg.Version]; ok {
+			continue
+		}
+		if err = m.run(mg, true); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Down 按版本号倒序回滚最近 steps 个已执行的迁移
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var done []*Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mg := migrations[i]
+		if _, ok := applied[mg.Version]; !ok {
+			continue
+		}
+		if err = m.run(mg, false); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// run 在事务中执行单个迁移并维护版本记录
+func (m *Migrator) run(mg *Migration, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+	fields := []zap.Field{zap.Int64("version", mg.Version), zap.String("name", mg.Name), zap.String("direction", direction)}
+	if m.dryRun {
+		if script, ok := m.script(mg, up); ok {
+			fields = append(fields, zap.String("sql", script))
+		}
+		global.GvaLog.Info("[dry-run] 待执行迁移", fields...)
+		return nil
+	}
+	start := time.Now()
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := m.exec(tx, mg, up); err != nil {
+			return err
+		}
+		if up {
+			return tx.Create(&SchemaMigration{Version: mg.Version, Name: mg.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&SchemaMigration{}, mg.Version).Error
+	})
+	if err != nil {
+		global.GvaLog.Error("迁移执行失败", append(fields, zap.Error(err))...)
+		return fmt.Errorf("迁移 %d_%s %s 执行失败: %w", mg.Version, mg.Name, direction, err)
+	}
+	global.GvaLog.Info("迁移执行成功", append(fields, zap.Duration("cost", time.Since(start)))...)
+	return nil
+}
+
+func (m *Migrator) exec(tx *gorm.DB, mg *Migration, up bool) error {
+	if !mg.IsSql() {
+		fn := mg.Up
+		if !up {
+			fn = mg.Down
+		}
+		if fn == nil {
+			return errors.New("未提供回滚方法")
+		}
+		return fn(tx)
+	}
+	script, ok := m.script(mg, up)
+	if !ok {
+		return fmt.Errorf("缺少 %s 方言的脚本", m.dialect())
+	}
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) script(mg *Migration, up bool) (string, bool) {
+	if !mg.IsSql() {
+		return "", false
+	}
+	if up {
+		return mg.UpSql(m.dialect())
+	}
+	return mg.DownSql(m.dialect())
+}
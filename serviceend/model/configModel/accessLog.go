@@ -0,0 +1,16 @@
+package configModel
+
+type AccessLog struct {
+	Enable        bool              `mapstructure:"enable" json:"enable" yaml:"enable"`                         // 是否记录访问日志
+	SkipPaths     []string          `mapstructure:"skip-paths" json:"skip-paths" yaml:"skip-paths"`             // 不记录的路径 不含应用名前缀, 以 * 结尾时按前缀匹配
+	Samples       []AccessLogSample `mapstructure:"samples" json:"samples" yaml:"samples"`                      // 按比例记录的路径
+	SlowThreshold string            `mapstructure:"slow-threshold" json:"slow-threshold" yaml:"slow-threshold"` // 慢请求耗时 如 1s, 慢请求以 warn 级别记录
+	Body          bool              `mapstructure:"body" json:"body" yaml:"body"`                               // 是否记录请求体 只记录 json 与表单
+	BodyMaxSize   int               `mapstructure:"body-max-size" json:"body-max-size" yaml:"body-max-size"`    // 记录请求体的最大字节数 超出时不记录
+	RedactFields  []string          `mapstructure:"redact-fields" json:"redact-fields" yaml:"redact-fields"`    // 脱敏字段 请求体与查询参数中的同名字段(忽略大小写)替换为 ***
+}
+
+type AccessLogSample struct {
+	Path string  `mapstructure:"path" json:"path" yaml:"path"` // 路径 规则同 skip-paths
+	Rate float64 `mapstructure:"rate" json:"rate" yaml:"rate"` // 记录比例 0-1
+}
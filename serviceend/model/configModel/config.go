@@ -1,6 +1,14 @@
 package configModel
 
 type ServerConfig struct {
-	System *System `mapstructure:"system" json:"system" yaml:"system"`
-	Zap    *Zap    `mapstructure:"zap" json:"zap" yaml:"zap"`
+	System       *System       `mapstructure:"system" json:"system" yaml:"system"`
+	Zap          *Zap          `mapstructure:"zap" json:"zap" yaml:"zap"`
+	Cors         *Cors         `mapstructure:"cors" json:"cors" yaml:"cors"`
+	AccessLog    *AccessLog    `mapstructure:"access-log" json:"access-log" yaml:"access-log"`
+	Db           *Db           `mapstructure:"db" json:"db" yaml:"db"`
+	Jwt          *Jwt          `mapstructure:"jwt" json:"jwt" yaml:"jwt"`
+	File         *File         `mapstructure:"file" json:"file" yaml:"file"`
+	Verification *Verification `mapstructure:"verification" json:"verification" yaml:"verification"`
+	Query        *Query        `mapstructure:"query" json:"query" yaml:"query"`
+	Notify       *Notify       `mapstructure:"notify" json:"notify" yaml:"notify"`
 }
@@ -0,0 +1,14 @@
+package configModel
+
+type Cors struct {
+	Mode      string          `mapstructure:"mode" json:"mode" yaml:"mode"`                // 放行模式: allow-all|whitelist|strict-whitelist
+	Whitelist []CorsWhitelist `mapstructure:"whitelist" json:"whitelist" yaml:"whitelist"` // 白名单
+}
+
+type CorsWhitelist struct {
+	AllowOrigin      string `mapstructure:"allow-origin" json:"allow-origin" yaml:"allow-origin"`                // 允许的来源域名
+	AllowMethods     string `mapstructure:"allow-methods" json:"allow-methods" yaml:"allow-methods"`             // 允许的请求方法
+	AllowHeaders     string `mapstructure:"allow-headers" json:"allow-headers" yaml:"allow-headers"`             // 允许的请求头
+	ExposeHeaders    string `mapstructure:"expose-headers" json:"expose-headers" yaml:"expose-headers"`          // 暴露给前端的响应头
+	AllowCredentials bool   `mapstructure:"allow-credentials" json:"allow-credentials" yaml:"allow-credentials"` // 是否允许携带凭证
+}
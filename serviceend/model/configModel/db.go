@@ -0,0 +1,59 @@
+package configModel
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Db 数据库配置 按驱动分别配置,由 system.db-type 决定启用哪一个
+type Db struct {
+	Mysql  Mysql  `mapstructure:"mysql" json:"mysql" yaml:"mysql"`
+	Pgsql  Pgsql  `mapstructure:"pgsql" json:"pgsql" yaml:"pgsql"`
+	Sqlite Sqlite `mapstructure:"sqlite" json:"sqlite" yaml:"sqlite"`
+}
+
+// GeneralDB 各驱动通用配置
+type GeneralDB struct {
+	Path            string `mapstructure:"path" json:"path" yaml:"path"`                                        // 数据库地址(sqlite 为文件所在目录)
+	Port            string `mapstructure:"port" json:"port" yaml:"port"`                                        // 端口
+	Config          string `mapstructure:"config" json:"config" yaml:"config"`                                  // 高级配置
+	DbName          string `mapstructure:"db-name" json:"db-name" yaml:"db-name"`                               // 数据库名
+	Username        string `mapstructure:"username" json:"username" yaml:"username"`                            // 用户名
+	Password        string `mapstructure:"password" json:"password" yaml:"password"`                            // 密码
+	MaxIdleConns    int    `mapstructure:"max-idle-conns" json:"max-idle-conns" yaml:"max-idle-conns"`          // 空闲中的最大连接数
+	MaxOpenConns    int    `mapstructure:"max-open-conns" json:"max-open-conns" yaml:"max-open-conns"`          // 打开到数据库的最大连接数
+	ConnMaxLifetime int    `mapstructure:"conn-max-lifetime" json:"conn-max-lifetime" yaml:"conn-max-lifetime"` // 连接最大存活时间(秒)
+	LogMode         string `mapstructure:"log-mode" json:"log-mode" yaml:"log-mode"`                            // sql日志级别: silent|error|warn|info
+}
+
+type Mysql struct {
+	GeneralDB `yaml:",inline" mapstructure:",squash"`
+}
+
+// Dsn 基于配置文件获取 dsn
+func (m *Mysql) Dsn() string {
+	return m.Username + ":" + m.Password + "@tcp(" + m.Path + ":" + m.Port + ")/" + m.DbName + "?" + m.Config
+}
+
+type Pgsql struct {
+	GeneralDB `yaml:",inline" mapstructure:",squash"`
+}
+
+// Dsn 基于配置文件获取 dsn
+func (p *Pgsql) Dsn() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s %s", p.Path, p.Username, p.Password, p.DbName, p.Port, p.Config)
+}
+
+type Sqlite struct {
+	GeneralDB `yaml:",inline" mapstructure:",squash"`
+}
+
+// Dsn sqlite 数据库文件路径,附加配置以 ? 拼接
+func (s *Sqlite) Dsn() string {
+	dsn := filepath.Join(s.Path, s.DbName+".db")
+	if config := strings.TrimPrefix(s.Config, "?"); config != "" {
+		dsn += "?" + config
+	}
+	return dsn
+}
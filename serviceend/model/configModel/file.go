@@ -0,0 +1,8 @@
+package configModel
+
+type File struct {
+	StorageType string `mapstructure:"storage-type" json:"storage-type" yaml:"storage-type"` // 存储方式 local
+	Path        string `mapstructure:"path" json:"path" yaml:"path"`                         // 本地存储目录
+	MaxSize     int64  `mapstructure:"max-size" json:"max-size" yaml:"max-size"`             // 单个文件大小上限(MB) 0 不限制
+	ChunkExpire string `mapstructure:"chunk-expire" json:"chunk-expire" yaml:"chunk-expire"` // 未完成的分片上传保留时长 如 24h
+}
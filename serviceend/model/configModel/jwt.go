@@ -0,0 +1,8 @@
+package configModel
+
+type Jwt struct {
+	SigningKey         string `mapstructure:"signing-key" json:"signing-key" yaml:"signing-key"`                            // jwt签名
+	ExpiresTime        string `mapstructure:"expires-time" json:"expires-time" yaml:"expires-time"`                         // 访问令牌过期时间 如 2h
+	RefreshExpiresTime string `mapstructure:"refresh-expires-time" json:"refresh-expires-time" yaml:"refresh-expires-time"` // 刷新令牌过期时间 如 7d
+	Issuer             string `mapstructure:"issuer" json:"issuer" yaml:"issuer"`                                           // 签发者
+}
@@ -0,0 +1,28 @@
+package configModel
+
+// Notify 通知渠道 告警触发、任务失败等事件按渠道订阅推送
+type Notify struct {
+	Retries  int             `mapstructure:"retries" json:"retries" yaml:"retries"`    // 发送失败重试次数
+	Backoff  string          `mapstructure:"backoff" json:"backoff" yaml:"backoff"`    // 首次重试等待时间 之后每次加倍 如 5s
+	Timeout  string          `mapstructure:"timeout" json:"timeout" yaml:"timeout"`    // 单次发送超时 如 10s
+	Channels []NotifyChannel `mapstructure:"channels" json:"channels" yaml:"channels"` // 通知渠道
+}
+
+// NotifyChannel 通知渠道 按类型使用对应字段
+type NotifyChannel struct {
+	Name     string            `mapstructure:"name" json:"name" yaml:"name"`             // 渠道名称 唯一
+	Type     string            `mapstructure:"type" json:"type" yaml:"type"`             // 类型 webhook|smtp|file
+	Enable   bool              `mapstructure:"enable" json:"enable" yaml:"enable"`       // 是否启用
+	Events   []string          `mapstructure:"events" json:"events" yaml:"events"`       // 订阅的事件前缀 如 alert、job.failed, 为空订阅全部
+	Url      string            `mapstructure:"url" json:"url" yaml:"url"`                // webhook 地址
+	Secret   string            `mapstructure:"secret" json:"-" yaml:"secret"`            // webhook 签名密钥
+	Headers  map[string]string `mapstructure:"headers" json:"-" yaml:"headers"`          // webhook 附加请求头
+	Host     string            `mapstructure:"host" json:"host" yaml:"host"`             // 邮件服务器
+	Port     int               `mapstructure:"port" json:"port" yaml:"port"`             // 邮件服务器端口
+	Username string            `mapstructure:"username" json:"username" yaml:"username"` // 邮箱账号
+	Password string            `mapstructure:"password" json:"-" yaml:"password"`        // 邮箱密码/授权码
+	From     string            `mapstructure:"from" json:"from" yaml:"from"`             // 发件人
+	To       []string          `mapstructure:"to" json:"to" yaml:"to"`                   // 收件人
+	Tls      string            `mapstructure:"tls" json:"tls" yaml:"tls"`                // none|starttls|ssl 为空时按端口自动选择
+	Path     string            `mapstructure:"path" json:"path" yaml:"path"`             // file 类型的输出文件
+}
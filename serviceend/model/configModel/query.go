@@ -0,0 +1,11 @@
+package configModel
+
+type Query struct {
+	Timeout    string `mapstructure:"timeout" json:"timeout" yaml:"timeout"`             // 单次查询超时时间 如 60s
+	PageSize   int    `mapstructure:"page-size" json:"page-size" yaml:"page-size"`       // 分页/推送每批行数
+	MaxRows    int    `mapstructure:"max-rows" json:"max-rows" yaml:"max-rows"`          // 单次查询最多返回行数 0 不限制
+	IdleExpire string `mapstructure:"idle-expire" json:"idle-expire" yaml:"idle-expire"` // 分页查询闲置超时 超时后自动关闭
+
+	ExportTimeout string `mapstructure:"export-timeout" json:"export-timeout" yaml:"export-timeout"`    // 导出超时时间 如 10m
+	ExportMaxRows int    `mapstructure:"export-max-rows" json:"export-max-rows" yaml:"export-max-rows"` // 单次导出最多行数 0 不限制
+}
@@ -4,6 +4,7 @@ type System struct {
 	ApplicationName string `mapstructure:"applicationName" json:"applicationName" yaml:"applicationName"` // 项目名称
 	Env             string `mapstructure:"env" json:"env" yaml:"env"`                                     // 环境值
 	Addr            int    `mapstructure:"addr" json:"addr" yaml:"addr"`                                  // 端口值
-	DbType          string `mapstructure:"db-type" json:"db-type" yaml:"db-type"`                         // 数据库类型:mysql(默认)|sqlite|sqlserver|postgresql
+	DbType          string `mapstructure:"db-type" json:"db-type" yaml:"db-type"`                         // 数据库类型:mysql(默认)|sqlite|postgresql
 	UseMultipoint   bool   `mapstructure:"use-multipoint" json:"use-multipoint" yaml:"use-multipoint"`    // 多点登录拦截
+	SecretKey       string `mapstructure:"secret-key" json:"secret-key" yaml:"secret-key"`                // 数据加密密钥 用于加密数据源密码等敏感信息
 }
@@ -0,0 +1,10 @@
+package configModel
+
+type Verification struct {
+	Length      int    `mapstructure:"length" json:"length" yaml:"length"`                   // 验证码长度
+	ExpiresTime string `mapstructure:"expires-time" json:"expires-time" yaml:"expires-time"` // 验证码有效期 如 5m
+	HourlyLimit int    `mapstructure:"hourly-limit" json:"hourly-limit" yaml:"hourly-limit"` // 同一手机号/邮箱每小时可发送次数
+	MaxAttempts int    `mapstructure:"max-attempts" json:"max-attempts" yaml:"max-attempts"` // 单个验证码可校验次数 超出后失效
+	Sender      string `mapstructure:"sender" json:"sender" yaml:"sender"`                   // 发送方式: log 仅写日志; file 写入本地文件
+	FilePath    string `mapstructure:"file-path" json:"file-path" yaml:"file-path"`          // sender 为 file 时的输出文件
+}
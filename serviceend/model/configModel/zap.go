@@ -9,12 +9,15 @@ import (
 type Zap struct {
 	Level         string `mapstructure:"level" json:"level" yaml:"level"`                            // 级别
 	Prefix        string `mapstructure:"prefix" json:"prefix" yaml:"prefix"`                         // 日志前缀
-	Format        string `mapstructure:"format" json:"format" yaml:"format"`                         // 输出
+	Format        string `mapstructure:"format" json:"format" yaml:"format"`                         // 输出格式 console|json|logfmt
 	Director      string `mapstructure:"director" json:"director"  yaml:"director"`                  // 日志文件夹
 	EncodeLevel   string `mapstructure:"encode-level" json:"encode-level" yaml:"encode-level"`       // 编码级
 	StacktraceKey string `mapstructure:"stacktrace-key" json:"stacktrace-key" yaml:"stacktrace-key"` // 栈名
 
 	MaxAge       int  `mapstructure:"max-age" json:"max-age" yaml:"max-age"`                      // 日志留存时间
+	MaxSize      int  `mapstructure:"max-size" json:"max-size" yaml:"max-size"`                   // 单个日志文件大小上限(MB) 超过后分割 0 只按天分割
+	MaxTotalSize int  `mapstructure:"max-total-size" json:"max-total-size" yaml:"max-total-size"` // 日志目录总大小上限(MB) 超过后删除最旧的分割文件 0 不限制
+	Compress     bool `mapstructure:"compress" json:"compress" yaml:"compress"`                   // 分割出的文件压缩为 gzip
 	ShowLine     bool `mapstructure:"show-line" json:"show-line" yaml:"show-line"`                // 显示行
 	LogInConsole bool `mapstructure:"log-in-console" json:"log-in-console" yaml:"log-in-console"` // 输出控制台
 }
@@ -46,7 +49,7 @@ func (z *Zap) TransportLevel() zapcore.Level {
 	case "warn":
 		return zapcore.WarnLevel
 	case "error":
-		return zapcore.WarnLevel
+		return zapcore.ErrorLevel
 	case "dpanic":
 		return zapcore.DPanicLevel
 	case "panic":
@@ -0,0 +1,15 @@
+package dbModel
+
+import (
+	"dataPanel/serviceend/utils"
+
+	"gorm.io/gorm"
+)
+
+// BaseModel 数据表公共字段
+type BaseModel struct {
+	ID        uint            `gorm:"primarykey" json:"id"` // 主键ID
+	CreatedAt utils.LocalTime `json:"createdAt"`            // 创建时间
+	UpdatedAt utils.LocalTime `json:"updatedAt"`            // 更新时间
+	DeletedAt gorm.DeletedAt  `gorm:"index" json:"-"`       // 删除时间
+}
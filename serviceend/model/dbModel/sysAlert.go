@@ -0,0 +1,69 @@
+package dbModel
+
+import "dataPanel/serviceend/utils"
+
+// SysAlertRule 告警规则 按计划执行保存的查询, 条件连续满足指定次数后触发
+type SysAlertRule struct {
+	BaseModel
+	Name         string                 `gorm:"size:64;uniqueIndex;not null;comment:规则名称" json:"name"`          // 规则名称
+	SavedQueryId uint                   `gorm:"index;not null;comment:保存的查询ID" json:"savedQueryId"`             // 保存的查询ID
+	Params       map[string]interface{} `gorm:"type:text;serializer:json;comment:查询参数" json:"params"`           // 查询参数
+	Cron         string                 `gorm:"size:64;not null;comment:执行计划" json:"cron"`                      // cron 表达式
+	Metric       string                 `gorm:"size:16;not null;comment:指标 value|rowCount" json:"metric"`       // 指标
+	Field        string                 `gorm:"size:64;comment:取值列" json:"field"`                               // 指标为 value 时取首行该列
+	Operator     string                 `gorm:"size:16;not null;comment:条件 above|below|change" json:"operator"` // 条件
+	Threshold    float64                `gorm:"comment:阈值" json:"threshold"`                                    // 阈值 change 为变化百分比
+	Times        int                    `gorm:"default:1;comment:连续满足次数" json:"times"`                          // 连续满足多少次后触发
+	Status       int                    `gorm:"default:1;comment:状态 1启用 2停用" json:"status"`                     // 状态
+	Remark       string                 `gorm:"size:255;comment:备注" json:"remark"`                              // 备注
+	CreatedBy    uint                   `gorm:"index;comment:创建人" json:"createdBy"`                             // 创建人
+	State        string                 `gorm:"size:16;default:ok;comment:告警状态 ok|firing" json:"state"`         // 告警状态
+	Hits         int                    `gorm:"default:0;comment:当前连续满足次数" json:"hits"`                         // 当前连续满足次数
+	LastValue    *float64               `gorm:"comment:上次取值" json:"lastValue"`                                  // 上次取值
+	LastEvalAt   *utils.LocalTime       `gorm:"comment:上次执行时间" json:"lastEvalAt"`                               // 上次执行时间
+	LastError    string                 `gorm:"size:1024;comment:上次执行错误" json:"lastError"`                      // 上次执行错误
+	SilenceUntil *utils.LocalTime       `gorm:"comment:静默截止时间" json:"silenceUntil"`                             // 静默期间触发只记录不通知
+}
+
+func (SysAlertRule) TableName() string {
+	return "sys_alert_rules"
+}
+
+// SysAlertEvent 告警记录 每次触发一条, 条件不再满足时记录恢复时间
+type SysAlertEvent struct {
+	ID         uint             `gorm:"primarykey" json:"id"`
+	RuleId     uint             `gorm:"index;not null;comment:规则ID" json:"ruleId"`     // 规则ID
+	RuleName   string           `gorm:"size:64;not null;comment:规则名称" json:"ruleName"` // 规则名称
+	Value      float64          `gorm:"comment:触发时的取值" json:"value"`                   // 触发时的取值
+	Message    string           `gorm:"size:512;comment:告警内容" json:"message"`          // 告警内容
+	Silenced   bool             `gorm:"default:false;comment:是否处于静默期" json:"silenced"` // 静默期间触发 未通知
+	AckedBy    uint             `gorm:"comment:确认人" json:"ackedBy"`                    // 确认人
+	AckedAt    *utils.LocalTime `gorm:"index;comment:确认时间" json:"ackedAt"`             // 确认时间
+	ResolvedAt *utils.LocalTime `gorm:"comment:恢复时间" json:"resolvedAt"`                // 恢复时间
+	CreatedAt  utils.LocalTime  `gorm:"index;comment:触发时间" json:"createdAt"`           // 触发时间
+}
+
+func (SysAlertEvent) TableName() string {
+	return "sys_alert_events"
+}
+
+const (
+	AlertEnable  = 1 // 启用
+	AlertDisable = 2 // 停用
+)
+
+const (
+	AlertMetricValue    = "value"    // 首行指定列的值
+	AlertMetricRowCount = "rowCount" // 结果行数
+)
+
+const (
+	AlertAbove  = "above"  // 大于阈值
+	AlertBelow  = "below"  // 小于阈值
+	AlertChange = "change" // 与上次取值相比变化百分比超过阈值
+)
+
+const (
+	AlertOk     = "ok"     // 正常
+	AlertFiring = "firing" // 告警中
+)
@@ -0,0 +1,56 @@
+package dbModel
+
+import "dataPanel/serviceend/utils"
+
+// SysUserGroup 用户群 公告的面向人群
+type SysUserGroup struct {
+	BaseModel
+	Name   string    `gorm:"size:64;uniqueIndex;not null;comment:用户群名称" json:"name"`   // 用户群名称
+	Status int       `gorm:"default:1;comment:状态 1正常 2禁用" json:"status"`               // 状态 1正常 2禁用
+	Remark string    `gorm:"size:255;comment:备注" json:"remark"`                        // 备注
+	Users  []SysUser `gorm:"many2many:sys_user_group_members;" json:"users,omitempty"` // 成员
+}
+
+func (SysUserGroup) TableName() string {
+	return "sys_user_groups"
+}
+
+const (
+	GroupEnable  = 1 // 正常
+	GroupDisable = 2 // 禁用
+)
+
+// SysBulletin 公告
+type SysBulletin struct {
+	BaseModel
+	Title     string           `gorm:"size:128;uniqueIndex;not null;comment:公告标题" json:"title"` // 公告标题
+	Content   string           `gorm:"type:text;comment:公告内容" json:"content"`                   // 公告内容
+	Level     int              `gorm:"default:1;comment:级别 1普通 2重要 3紧急" json:"level"`           // 级别
+	Status    int              `gorm:"default:1;index;comment:状态 1草稿 2已发布 3已撤回" json:"status"`  // 状态
+	PublishAt *utils.LocalTime `gorm:"index;comment:生效时间" json:"publishAt"`                     // 生效时间 晚于发布操作时间即为定时公告
+	ExpireAt  *utils.LocalTime `gorm:"index;comment:过期时间" json:"expireAt"`                      // 过期时间 为空则长期有效
+	CreatedBy uint             `gorm:"index;comment:创建人" json:"createdBy"`                      // 创建人
+	Groups    []SysUserGroup   `gorm:"many2many:sys_bulletin_groups;" json:"groups,omitempty"`  // 面向的用户群
+}
+
+func (SysBulletin) TableName() string {
+	return "sys_bulletins"
+}
+
+const (
+	BulletinDraft     = 1 // 草稿
+	BulletinPublished = 2 // 已发布
+	BulletinWithdrawn = 3 // 已撤回
+)
+
+// SysBulletinRead 公告已读回执
+type SysBulletinRead struct {
+	ID         uint            `gorm:"primarykey" json:"id"`
+	BulletinId uint            `gorm:"not null;uniqueIndex:idx_bulletin_read_user;comment:公告ID" json:"bulletinId"` // 公告ID
+	UserId     uint            `gorm:"not null;uniqueIndex:idx_bulletin_read_user;comment:用户ID" json:"userId"`     // 用户ID
+	ReadAt     utils.LocalTime `gorm:"comment:阅读时间" json:"readAt"`                                                 // 阅读时间
+}
+
+func (SysBulletinRead) TableName() string {
+	return "sys_bulletin_reads"
+}
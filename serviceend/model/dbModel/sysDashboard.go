@@ -0,0 +1,70 @@
+package dbModel
+
+import "dataPanel/serviceend/utils"
+
+// SysDashboard 仪表盘 每次保存生成一个版本
+type SysDashboard struct {
+	BaseModel
+	Name      string               `gorm:"size:64;uniqueIndex;not null;comment:仪表盘名称" json:"name"` // 仪表盘名称
+	Remark    string               `gorm:"size:255;comment:备注" json:"remark"`                      // 备注
+	Version   int                  `gorm:"default:0;comment:当前版本号" json:"version"`                 // 当前版本号 修改时需携带
+	CreatedBy uint                 `gorm:"index;comment:创建人" json:"createdBy"`                     // 创建人
+	Widgets   []SysDashboardWidget `gorm:"foreignKey:DashboardId" json:"widgets,omitempty"`        // 组件
+}
+
+func (SysDashboard) TableName() string {
+	return "sys_dashboards"
+}
+
+// SysDashboardWidget 仪表盘组件 绑定保存的查询 按栅格布局
+type SysDashboardWidget struct {
+	BaseModel
+	DashboardId     uint                   `gorm:"index;not null;comment:仪表盘ID" json:"dashboardId"`                 // 仪表盘ID
+	Title           string                 `gorm:"size:64;not null;comment:标题" json:"title"`                        // 标题
+	Type            string                 `gorm:"size:16;not null;comment:类型 table|line|bar|pie|stat" json:"type"` // 类型
+	SavedQueryId    uint                   `gorm:"index;not null;comment:保存的查询ID" json:"savedQueryId"`              // 保存的查询ID
+	Params          map[string]interface{} `gorm:"type:text;serializer:json;comment:查询参数" json:"params"`            // 查询参数值
+	Options         WidgetOptions          `gorm:"type:text;serializer:json;comment:展示配置" json:"options"`           // 展示配置
+	X               int                    `gorm:"comment:列位置" json:"x"`                                            // 列位置 从0开始
+	Y               int                    `gorm:"comment:行位置" json:"y"`                                            // 行位置 从0开始
+	W               int                    `gorm:"comment:宽度(列)" json:"w"`                                          // 宽度(列)
+	H               int                    `gorm:"comment:高度(行)" json:"h"`                                          // 高度(行)
+	RefreshInterval int                    `gorm:"default:0;comment:自动刷新间隔(秒)" json:"refreshInterval"`              // 自动刷新间隔(秒) 0 不自动刷新
+}
+
+func (SysDashboardWidget) TableName() string {
+	return "sys_dashboard_widgets"
+}
+
+// WidgetOptions 组件展示配置 字段名对应查询结果的列名
+type WidgetOptions struct {
+	XField     string   `json:"xField,omitempty"`     // 折线/柱状 横轴字段
+	YFields    []string `json:"yFields,omitempty"`    // 折线/柱状 数值字段 每个字段一条序列
+	LabelField string   `json:"labelField,omitempty"` // 饼图 名称字段
+	ValueField string   `json:"valueField,omitempty"` // 饼图/单值 数值字段
+	Columns    []string `json:"columns,omitempty"`    // 表格 显示的列 为空显示全部
+	Unit       string   `json:"unit,omitempty"`       // 单位
+}
+
+const (
+	WidgetTable = "table" // 表格
+	WidgetLine  = "line"  // 折线图
+	WidgetBar   = "bar"   // 柱状图
+	WidgetPie   = "pie"   // 饼图
+	WidgetStat  = "stat"  // 单值
+)
+
+// SysDashboardVersion 仪表盘历史版本 保存仪表盘与组件的快照
+type SysDashboardVersion struct {
+	ID          uint            `gorm:"primarykey" json:"id"`
+	DashboardId uint            `gorm:"not null;uniqueIndex:idx_dashboard_version;comment:仪表盘ID" json:"dashboardId"` // 仪表盘ID
+	Version     int             `gorm:"not null;uniqueIndex:idx_dashboard_version;comment:版本号" json:"version"`       // 版本号
+	Snapshot    string          `gorm:"type:text;not null;comment:快照(JSON)" json:"-"`                                // 快照
+	Remark      string          `gorm:"size:255;comment:版本说明" json:"remark"`                                         // 版本说明
+	CreatedBy   uint            `gorm:"comment:保存人" json:"createdBy"`                                                // 保存人
+	CreatedAt   utils.LocalTime `json:"createdAt"`                                                                   // 保存时间
+}
+
+func (SysDashboardVersion) TableName() string {
+	return "sys_dashboard_versions"
+}
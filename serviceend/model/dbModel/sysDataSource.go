@@ -0,0 +1,30 @@
+package dbModel
+
+// SysDataSource 外部数据源连接定义 密码加密保存
+type SysDataSource struct {
+	BaseModel
+	Name      string `gorm:"size:64;uniqueIndex;not null;comment:数据源名称" json:"name"`            // 数据源名称
+	Type      string `gorm:"size:32;not null;comment:类型 mysql|postgres|sqlite|csv" json:"type"` // 类型
+	Host      string `gorm:"size:128;comment:主机地址" json:"host"`                                 // 主机地址
+	Port      int    `gorm:"comment:端口" json:"port"`                                            // 端口
+	Database  string `gorm:"size:128;comment:数据库名" json:"database"`                             // 数据库名
+	Username  string `gorm:"size:128;comment:用户名" json:"username"`                              // 用户名
+	Password  string `gorm:"size:512;comment:密码(加密)" json:"-"`                                  // 密码 AES-GCM 加密
+	Params    string `gorm:"size:512;comment:连接参数" json:"params"`                               // 额外连接参数
+	Path      string `gorm:"size:512;comment:文件路径/目录" json:"path"`                              // sqlite 文件/csv 目录
+	Status    int    `gorm:"default:1;comment:状态 1正常 2禁用" json:"status"`                        // 状态
+	Writable  bool   `gorm:"default:false;comment:是否允许写操作" json:"writable"`                     // 是否允许执行写语句
+	Remark    string `gorm:"size:255;comment:备注" json:"remark"`                                 // 备注
+	CreatedBy uint   `gorm:"index;comment:创建人" json:"createdBy"`                                // 创建人
+
+	HasPassword bool `gorm:"-" json:"hasPassword"` // 是否已设置密码
+}
+
+func (SysDataSource) TableName() string {
+	return "sys_data_sources"
+}
+
+const (
+	DataSourceEnable  = 1 // 正常
+	DataSourceDisable = 2 // 禁用
+)
@@ -0,0 +1,31 @@
+package dbModel
+
+// SysDepartment 部门 以 TreePath 物化路径存储层级 便于查询子树
+type SysDepartment struct {
+	BaseModel
+	ParentId uint            `gorm:"default:0;index;comment:上级部门ID" json:"parentId"`     // 上级部门ID 0为顶级部门
+	TreePath string          `gorm:"size:512;index;comment:层级路径 如/1/3/" json:"treePath"` // 层级路径 包含自身 如 /1/3/
+	Name     string          `gorm:"size:64;not null;comment:部门名称" json:"name"`          // 部门名称
+	Leader   string          `gorm:"size:64;comment:负责人" json:"leader"`                  // 负责人
+	Phone    string          `gorm:"size:20;comment:联系电话" json:"phone"`                  // 联系电话
+	Sort     int             `gorm:"default:0;comment:排序" json:"sort"`                   // 排序
+	Status   int             `gorm:"default:1;comment:部门状态 1正常 2停用" json:"status"`       // 部门状态 1正常 2停用
+	Children []SysDepartment `gorm:"-" json:"children,omitempty"`                        // 下级部门
+}
+
+func (SysDepartment) TableName() string {
+	return "sys_departments"
+}
+
+const (
+	DepartmentEnable  = 1 // 正常
+	DepartmentDisable = 2 // 停用
+)
+
+// 角色数据权限范围 用户拥有多个角色时取范围最大者
+const (
+	DataScopeAll      = 1 // 全部数据
+	DataScopeDeptTree = 2 // 本部门及以下
+	DataScopeDept     = 3 // 仅本部门
+	DataScopeSelf     = 4 // 仅本人
+)
@@ -0,0 +1,56 @@
+package dbModel
+
+import "time"
+
+// SysFile 文件记录 删除为软删除,存储中的文件保留
+type SysFile struct {
+	BaseModel
+	Name        string `gorm:"size:255;not null;comment:原始文件名" json:"name"`      // 原始文件名
+	Ext         string `gorm:"size:32;comment:扩展名" json:"ext"`                   // 扩展名
+	MimeType    string `gorm:"size:128;comment:文件类型" json:"mimeType"`            // 文件类型
+	Size        int64  `gorm:"comment:文件大小(字节)" json:"size"`                     // 文件大小
+	Sha256      string `gorm:"size:64;index;comment:SHA-256 校验值" json:"sha256"`  // SHA-256 校验值
+	StorageType string `gorm:"size:32;comment:存储方式" json:"storageType"`          // 存储方式
+	StorageKey  string `gorm:"size:255;not null;comment:存储路径" json:"-"`          // 存储路径
+	Status      int    `gorm:"default:1;index;comment:状态 1正常 2禁用" json:"status"` // 状态
+	CreatedBy   uint   `gorm:"index;comment:上传人" json:"createdBy"`               // 上传人
+}
+
+func (SysFile) TableName() string {
+	return "sys_files"
+}
+
+const (
+	FileEnable  = 1 // 正常
+	FileDisable = 2 // 禁用
+)
+
+// SysFileUpload 分片上传任务 合并完成后删除
+type SysFileUpload struct {
+	ID         uint      `gorm:"primarykey" json:"-"`
+	UploadId   string    `gorm:"size:64;uniqueIndex;not null;comment:上传任务ID" json:"uploadId"` // 上传任务ID
+	FileName   string    `gorm:"size:255;not null;comment:原始文件名" json:"fileName"`             // 原始文件名
+	Size       int64     `gorm:"comment:文件大小(字节)" json:"size"`                                // 文件大小
+	Sha256     string    `gorm:"size:64;index;comment:文件 SHA-256 校验值" json:"sha256"`          // 文件校验值 合并后校验
+	ChunkSize  int64     `gorm:"comment:分片大小(字节)" json:"chunkSize"`                           // 分片大小
+	ChunkTotal int       `gorm:"comment:分片数量" json:"chunkTotal"`                              // 分片数量
+	CreatedBy  uint      `gorm:"index;comment:上传人" json:"createdBy"`                          // 上传人
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (SysFileUpload) TableName() string {
+	return "sys_file_uploads"
+}
+
+// SysFileChunk 已上传的分片
+type SysFileChunk struct {
+	ID         uint   `gorm:"primarykey" json:"-"`
+	UploadId   string `gorm:"size:64;not null;uniqueIndex:idx_file_chunk;comment:上传任务ID" json:"uploadId"` // 上传任务ID
+	ChunkIndex int    `gorm:"not null;uniqueIndex:idx_file_chunk;comment:分片序号" json:"chunkIndex"`         // 分片序号 从0开始
+	Size       int64  `gorm:"comment:分片大小(字节)" json:"size"`                                               // 分片大小
+	Sha256     string `gorm:"size:64;comment:分片 SHA-256 校验值" json:"sha256"`                               // 分片校验值
+}
+
+func (SysFileChunk) TableName() string {
+	return "sys_file_chunks"
+}
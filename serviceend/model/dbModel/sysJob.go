@@ -0,0 +1,45 @@
+package dbModel
+
+import "dataPanel/serviceend/utils"
+
+// SysJob 定时任务状态 任务在代码中注册, 这里只保存暂停状态
+type SysJob struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	Name      string          `gorm:"size:64;uniqueIndex;not null;comment:任务标识" json:"name"` // 任务标识
+	Paused    bool            `gorm:"default:false;comment:是否暂停" json:"paused"`              // 是否暂停
+	UpdatedAt utils.LocalTime `json:"updatedAt"`                                             // 更新时间
+}
+
+func (SysJob) TableName() string {
+	return "sys_jobs"
+}
+
+// SysJobRun 任务执行记录 每次尝试(含重试)一条
+type SysJobRun struct {
+	ID         uint             `gorm:"primarykey" json:"id"`
+	JobName    string           `gorm:"size:64;index;not null;comment:任务标识" json:"jobName"`                                       // 任务标识
+	Trigger    string           `gorm:"size:16;not null;comment:触发方式 schedule|manual" json:"trigger"`                             // 触发方式
+	Attempt    int              `gorm:"default:1;comment:第几次尝试" json:"attempt"`                                                   // 第几次尝试
+	Status     string           `gorm:"size:16;index;not null;comment:状态 running|success|failed|skipped|cancelled" json:"status"` // 状态
+	Duration   int64            `gorm:"default:0;comment:执行耗时(毫秒)" json:"duration"`                                               // 执行耗时(毫秒)
+	Error      string           `gorm:"size:1024;comment:错误信息" json:"error"`                                                      // 错误信息
+	CreatedAt  utils.LocalTime  `gorm:"index;comment:开始时间" json:"createdAt"`                                                      // 开始时间
+	FinishedAt *utils.LocalTime `gorm:"comment:结束时间" json:"finishedAt"`                                                           // 结束时间
+}
+
+func (SysJobRun) TableName() string {
+	return "sys_job_runs"
+}
+
+const (
+	JobTriggerSchedule = "schedule" // 按计划触发
+	JobTriggerManual   = "manual"   // 手动触发
+)
+
+const (
+	JobRunning   = "running"   // 执行中
+	JobSuccess   = "success"   // 成功
+	JobFailed    = "failed"    // 失败
+	JobSkipped   = "skipped"   // 上一次未结束 跳过
+	JobCancelled = "cancelled" // 程序退出时中断
+)
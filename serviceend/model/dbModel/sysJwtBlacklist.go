@@ -0,0 +1,15 @@
+package dbModel
+
+import "time"
+
+// SysJwtBlacklist 已注销的令牌 过期后可清理
+type SysJwtBlacklist struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Jti       string    `gorm:"size:64;uniqueIndex;not null;comment:令牌ID" json:"jti"` // 令牌ID
+	ExpiresAt time.Time `gorm:"index;not null;comment:令牌过期时间" json:"expiresAt"`       // 令牌过期时间
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (SysJwtBlacklist) TableName() string {
+	return "sys_jwt_blacklists"
+}
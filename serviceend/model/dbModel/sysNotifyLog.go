@@ -0,0 +1,27 @@
+package dbModel
+
+import "dataPanel/serviceend/utils"
+
+// SysNotifyLog 通知投递记录 每个渠道每条通知一条, 重试时更新尝试次数
+type SysNotifyLog struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	Channel   string          `gorm:"size:64;index;not null;comment:渠道名称" json:"channel"`                     // 渠道名称
+	Type      string          `gorm:"size:16;not null;comment:渠道类型" json:"type"`                              // 渠道类型
+	Event     string          `gorm:"size:64;index;not null;comment:事件" json:"event"`                         // 事件
+	Title     string          `gorm:"size:255;comment:标题" json:"title"`                                       // 标题
+	Status    string          `gorm:"size:16;index;not null;comment:状态 sending|success|failed" json:"status"` // 状态
+	Attempts  int             `gorm:"default:0;comment:尝试次数" json:"attempts"`                                 // 尝试次数
+	Duration  int64           `gorm:"default:0;comment:总耗时(毫秒)" json:"duration"`                              // 总耗时(毫秒)
+	Error     string          `gorm:"size:1024;comment:最后一次错误" json:"error"`                                  // 最后一次错误
+	CreatedAt utils.LocalTime `gorm:"index;comment:发送时间" json:"createdAt"`                                    // 发送时间
+}
+
+func (SysNotifyLog) TableName() string {
+	return "sys_notify_logs"
+}
+
+const (
+	NotifySending = "sending" // 发送中
+	NotifySuccess = "success" // 成功
+	NotifyFailed  = "failed"  // 重试后仍失败
+)
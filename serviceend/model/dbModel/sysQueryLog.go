@@ -0,0 +1,30 @@
+package dbModel
+
+import "dataPanel/serviceend/utils"
+
+// SysQueryLog 查询执行记录 包括即席查询与保存的查询
+type SysQueryLog struct {
+	ID           uint            `gorm:"primarykey" json:"id"`
+	QueryId      string          `gorm:"size:36;uniqueIndex;not null;comment:查询ID" json:"queryId"`                           // 查询ID
+	DataSourceId uint            `gorm:"index;not null;comment:数据源ID" json:"dataSourceId"`                                   // 数据源ID
+	SavedQueryId uint            `gorm:"index;comment:保存的查询ID" json:"savedQueryId"`                                          // 保存的查询ID 即席查询为0
+	Sql          string          `gorm:"type:text;not null;comment:执行的SQL" json:"sql"`                                       // 执行的SQL
+	Status       string          `gorm:"size:16;not null;comment:状态 running|success|failed|cancelled|timeout" json:"status"` // 状态
+	RowCount     int64           `gorm:"default:0;comment:返回/影响行数" json:"rowCount"`                                          // 返回/影响行数
+	Duration     int64           `gorm:"default:0;comment:执行耗时(毫秒)" json:"duration"`                                         // 执行耗时(毫秒)
+	Error        string          `gorm:"size:1024;comment:错误信息" json:"error"`                                                // 错误信息
+	CreatedBy    uint            `gorm:"index;comment:执行人" json:"createdBy"`                                                 // 执行人
+	CreatedAt    utils.LocalTime `gorm:"index;comment:执行时间" json:"createdAt"`                                                // 执行时间
+}
+
+func (SysQueryLog) TableName() string {
+	return "sys_query_logs"
+}
+
+const (
+	QueryRunning   = "running"   // 执行中
+	QuerySuccess   = "success"   // 成功
+	QueryFailed    = "failed"    // 失败
+	QueryCancelled = "cancelled" // 已取消
+	QueryTimeout   = "timeout"   // 超时
+)
@@ -0,0 +1,56 @@
+package dbModel
+
+// SysRole 角色
+type SysRole struct {
+	BaseModel
+	RoleName    string          `gorm:"size:64;uniqueIndex;not null;comment:角色名" json:"roleName"`      // 角色名
+	RoleKey     string          `gorm:"size:64;index;comment:角色标识" json:"roleKey"`                     // 角色标识 super_admin 拥有全部权限
+	Status      int             `gorm:"default:1;comment:角色状态 1正常 2停用" json:"status"`                  // 角色状态 1正常 2停用
+	Sort        int             `gorm:"default:0;comment:排序" json:"sort"`                              // 排序
+	DataScope   int             `gorm:"default:1;comment:数据权限 1全部 2本部门及以下 3本部门 4仅本人" json:"dataScope"` // 数据权限范围
+	Remark      string          `gorm:"size:255;comment:备注" json:"remark"`                             // 备注
+	Permissions []SysPermission `gorm:"many2many:sys_role_permissions;" json:"permissions,omitempty"`  // 接口权限
+	Menus       []SysMenu       `gorm:"many2many:sys_role_menus;" json:"menus,omitempty"`              // 菜单权限
+}
+
+func (SysRole) TableName() string {
+	return "sys_roles"
+}
+
+const (
+	RoleEnable  = 1 // 正常
+	RoleDisable = 2 // 停用
+
+	RoleKeySuperAdmin = "super_admin" // 超级管理员 跳过接口权限校验
+)
+
+// SysPermission 接口权限 路由+请求方法
+type SysPermission struct {
+	BaseModel
+	Path        string `gorm:"size:191;not null;uniqueIndex:idx_permission_path_method;comment:路由(不含应用名前缀)" json:"path"` // 路由 如 /role/list
+	Method      string `gorm:"size:16;not null;uniqueIndex:idx_permission_path_method;comment:请求方法" json:"method"`       // 请求方法
+	ApiGroup    string `gorm:"size:64;comment:分组" json:"apiGroup"`                                                       // 分组
+	Description string `gorm:"size:255;comment:描述" json:"description"`                                                   // 描述
+}
+
+func (SysPermission) TableName() string {
+	return "sys_permissions"
+}
+
+// SysMenu 前端菜单
+type SysMenu struct {
+	BaseModel
+	ParentId  uint      `gorm:"default:0;index;comment:父菜单ID" json:"parentId"`           // 父菜单ID 0为顶级菜单
+	Name      string    `gorm:"size:64;uniqueIndex;not null;comment:路由name" json:"name"` // 路由name
+	Title     string    `gorm:"size:64;comment:菜单名" json:"title"`                        // 菜单名
+	Path      string    `gorm:"size:191;comment:路由path" json:"path"`                     // 路由path
+	Component string    `gorm:"size:191;comment:前端组件路径" json:"component"`                // 前端组件路径
+	Icon      string    `gorm:"size:64;comment:图标" json:"icon"`                          // 图标
+	Sort      int       `gorm:"default:0;comment:排序" json:"sort"`                        // 排序
+	Hidden    bool      `gorm:"default:false;comment:是否在菜单中隐藏" json:"hidden"`            // 是否在菜单中隐藏
+	Children  []SysMenu `gorm:"-" json:"children,omitempty"`                             // 子菜单
+}
+
+func (SysMenu) TableName() string {
+	return "sys_menus"
+}
@@ -0,0 +1,33 @@
+package dbModel
+
+// SysSavedQuery 保存的查询 SQL 中以 :name 引用参数
+type SysSavedQuery struct {
+	BaseModel
+	Name         string       `gorm:"size:64;uniqueIndex;not null;comment:查询名称" json:"name"` // 查询名称
+	DataSourceId uint         `gorm:"index;not null;comment:数据源ID" json:"dataSourceId"`      // 数据源ID
+	Sql          string       `gorm:"type:text;not null;comment:SQL模板" json:"sql"`           // SQL模板
+	Params       []QueryParam `gorm:"type:text;serializer:json;comment:参数定义" json:"params"`  // 参数定义
+	Remark       string       `gorm:"size:255;comment:备注" json:"remark"`                     // 备注
+	CreatedBy    uint         `gorm:"index;comment:创建人" json:"createdBy"`                    // 创建人
+}
+
+func (SysSavedQuery) TableName() string {
+	return "sys_saved_queries"
+}
+
+// QueryParam 查询参数定义
+type QueryParam struct {
+	Name     string   `json:"name"`              // 参数名 对应 SQL 中的 :name
+	Label    string   `json:"label"`             // 显示名称 校验提示使用
+	Type     string   `json:"type"`              // 类型 date|number|string|enum
+	Required bool     `json:"required"`          // 是否必填
+	Default  string   `json:"default"`           // 默认值 未传值时使用
+	Options  []string `json:"options,omitempty"` // 可选值 仅 enum
+}
+
+const (
+	QueryParamDate   = "date"   // 日期 yyyy-MM-dd
+	QueryParamNumber = "number" // 数值
+	QueryParamString = "string" // 字符串
+	QueryParamEnum   = "enum"   // 枚举
+)
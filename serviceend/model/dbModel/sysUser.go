@@ -0,0 +1,23 @@
+package dbModel
+
+// SysUser 系统用户
+type SysUser struct {
+	BaseModel
+	Username     string    `gorm:"size:64;uniqueIndex;not null;comment:用户登录名" json:"username"` // 用户登录名
+	Password     string    `gorm:"size:128;not null;comment:用户登录密码" json:"-"`                  // 用户登录密码 bcrypt
+	NickName     string    `gorm:"size:64;default:系统用户;comment:用户昵称" json:"nickName"`          // 用户昵称
+	Phone        string    `gorm:"size:20;index;comment:手机号" json:"phone"`                     // 手机号
+	Email        string    `gorm:"size:128;comment:邮箱" json:"email"`                           // 邮箱
+	Enable       int       `gorm:"default:1;comment:用户是否可用 1正常 2冻结" json:"enable"`             // 用户是否可用 1正常 2冻结
+	DepartmentId uint      `gorm:"default:0;index;comment:归属部门ID" json:"departmentId"`         // 归属部门ID 0为未分配
+	Roles        []SysRole `gorm:"many2many:sys_user_roles;" json:"roles,omitempty"`           // 角色
+}
+
+func (SysUser) TableName() string {
+	return "sys_users"
+}
+
+const (
+	UserEnable  = 1 // 正常
+	UserDisable = 2 // 冻结
+)
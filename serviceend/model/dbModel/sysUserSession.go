@@ -0,0 +1,20 @@
+package dbModel
+
+import "time"
+
+// SysUserSession 登录会话 一次登录对应一个会话,刷新令牌沿用同一会话
+type SysUserSession struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	SessionId    string    `gorm:"size:64;uniqueIndex;not null;comment:会话ID" json:"sessionId"` // 会话ID 写入令牌载荷
+	UserId       uint      `gorm:"index;not null;comment:用户ID" json:"userId"`                  // 用户ID
+	Username     string    `gorm:"size:64;comment:用户登录名" json:"username"`                      // 用户登录名
+	ClientIp     string    `gorm:"size:64;comment:登录IP" json:"clientIp"`                       // 登录IP
+	UserAgent    string    `gorm:"size:512;comment:客户端标识" json:"userAgent"`                    // 客户端标识
+	ExpiresAt    time.Time `gorm:"index;not null;comment:会话过期时间" json:"expiresAt"`             // 会话过期时间 与刷新令牌一致
+	LastActiveAt time.Time `gorm:"comment:最后活跃时间" json:"lastActiveAt"`                         // 最后活跃时间
+	CreatedAt    time.Time `json:"createdAt"`                                                  // 登录时间
+}
+
+func (SysUserSession) TableName() string {
+	return "sys_user_sessions"
+}
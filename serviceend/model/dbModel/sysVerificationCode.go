@@ -0,0 +1,21 @@
+package dbModel
+
+import "time"
+
+// SysVerificationCode 短信/邮件验证码 仅保存验证码摘要
+type SysVerificationCode struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Channel   string    `gorm:"size:16;not null;comment:发送渠道 sms|email" json:"channel"`                       // 发送渠道
+	Target    string    `gorm:"size:128;not null;index:idx_verification_target;comment:手机号/邮箱" json:"target"` // 手机号/邮箱
+	Scene     string    `gorm:"size:32;not null;index:idx_verification_target;comment:业务场景" json:"scene"`     // 业务场景
+	CodeHash  string    `gorm:"size:64;not null;comment:验证码摘要" json:"-"`                                      // 验证码摘要
+	Attempts  int       `gorm:"default:0;comment:已校验次数" json:"attempts"`                                      // 已校验次数
+	Used      bool      `gorm:"default:false;comment:是否已使用" json:"used"`                                      // 是否已使用
+	ClientIp  string    `gorm:"size:64;comment:请求IP" json:"clientIp"`                                         // 请求IP
+	ExpiresAt time.Time `gorm:"not null;comment:过期时间" json:"expiresAt"`                                       // 过期时间
+	CreatedAt time.Time `gorm:"index;comment:发送时间" json:"createdAt"`                                          // 发送时间
+}
+
+func (SysVerificationCode) TableName() string {
+	return "sys_verification_codes"
+}
@@ -0,0 +1,36 @@
+package reqModel
+
+// AlertRuleReq 新增/修改告警规则
+type AlertRuleReq struct {
+	ID           uint                   `json:"id" label:"规则ID"`
+	Name         string                 `json:"name" label:"规则名称" binding:"required,max=64"`
+	SavedQueryId uint                   `json:"savedQueryId" label:"保存的查询" binding:"required"`
+	Params       map[string]interface{} `json:"params" label:"查询参数"`
+	Cron         string                 `json:"cron" label:"执行计划" binding:"required,max=64"`
+	Metric       string                 `json:"metric" label:"指标" binding:"required,oneof=value rowCount"`
+	Field        string                 `json:"field" label:"取值列" binding:"required_if=Metric value,max=64"`
+	Operator     string                 `json:"operator" label:"条件" binding:"required,oneof=above below change"`
+	Threshold    float64                `json:"threshold" label:"阈值"`
+	Times        int                    `json:"times" label:"连续满足次数" binding:"omitempty,min=1,max=100"`
+	Status       int                    `json:"status" label:"状态" binding:"omitempty,oneof=1 2"`
+	Remark       string                 `json:"remark" label:"备注" binding:"max=255"`
+}
+
+// AlertRuleListReq 告警规则列表
+type AlertRuleListReq struct {
+	PageInfo
+	State string `json:"state" form:"state" label:"告警状态"`
+}
+
+// AlertEventListReq 告警记录
+type AlertEventListReq struct {
+	PageInfo
+	RuleId  uint `json:"ruleId" form:"ruleId" label:"规则"`
+	Unacked bool `json:"unacked" form:"unacked" label:"仅未确认"`
+}
+
+// AlertSilenceReq 静默告警规则 时长为 0 取消静默
+type AlertSilenceReq struct {
+	ID       uint   `json:"id" label:"规则ID" binding:"required"`
+	Duration string `json:"duration" label:"静默时长" binding:"required,max=16"`
+}
@@ -0,0 +1,39 @@
+package reqModel
+
+import "dataPanel/serviceend/utils"
+
+// UserGroupReq 新增/修改用户群
+type UserGroupReq struct {
+	ID     uint   `json:"id" label:"用户群ID"`
+	Name   string `json:"name" label:"用户群名称" binding:"required,max=64"`
+	Status int    `json:"status" label:"状态" binding:"omitempty,oneof=1 2"`
+	Remark string `json:"remark" label:"备注" binding:"max=255"`
+}
+
+// UserGroupMemberReq 设置用户群成员
+type UserGroupMemberReq struct {
+	GroupId uint   `json:"groupId" label:"用户群ID" binding:"required"`
+	UserIds []uint `json:"userIds" label:"用户ID列表"`
+}
+
+// BulletinReq 新增/修改公告 已发布的公告需撤回后修改
+type BulletinReq struct {
+	ID       uint   `json:"id" label:"公告ID"`
+	Title    string `json:"title" label:"公告标题" binding:"required,max=128"`
+	Content  string `json:"content" label:"公告内容" binding:"required"`
+	Level    int    `json:"level" label:"级别" binding:"omitempty,oneof=1 2 3"`
+	GroupIds []uint `json:"groupIds" label:"面向人群" binding:"required,min=1"`
+}
+
+// BulletinPublishReq 发布公告 生效时间为空则立即生效
+type BulletinPublishReq struct {
+	ID        uint             `json:"id" label:"公告ID" binding:"required"`
+	PublishAt *utils.LocalTime `json:"publishAt" label:"生效时间"`
+	ExpireAt  *utils.LocalTime `json:"expireAt" label:"过期时间"`
+}
+
+// BulletinListReq 公告查询
+type BulletinListReq struct {
+	PageInfo
+	Status int `json:"status" form:"status" label:"状态"`
+}
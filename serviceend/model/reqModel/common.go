@@ -0,0 +1,28 @@
+package reqModel
+
+// PageInfo 分页参数
+type PageInfo struct {
+	Page     int    `json:"page" form:"page" label:"页码"`           // 页码
+	PageSize int    `json:"pageSize" form:"pageSize" label:"每页数量"` // 每页大小
+	Keyword  string `json:"keyword" form:"keyword" label:"关键字"`    // 关键字
+}
+
+// Limit 返回 limit/offset 未传时默认第一页 每页10条 最大100条
+func (p PageInfo) Limit() (limit, offset int) {
+	page, pageSize := p.Page, p.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	switch {
+	case pageSize > 100:
+		pageSize = 100
+	case pageSize <= 0:
+		pageSize = 10
+	}
+	return pageSize, pageSize * (page - 1)
+}
+
+// IdsReq 批量ID
+type IdsReq struct {
+	Ids []uint `json:"ids" form:"ids" label:"ID列表"`
+}
@@ -0,0 +1,34 @@
+package reqModel
+
+import "dataPanel/serviceend/model/dbModel"
+
+// DashboardReq 新增/修改仪表盘 组件整体提交 修改时需携带当前版本号
+type DashboardReq struct {
+	ID            uint                 `json:"id" label:"仪表盘ID"`
+	Name          string               `json:"name" label:"仪表盘名称" binding:"required,max=64"`
+	Remark        string               `json:"remark" label:"备注" binding:"max=255"`
+	Version       int                  `json:"version" label:"版本号" binding:"min=0"`
+	VersionRemark string               `json:"versionRemark" label:"版本说明" binding:"max=255"`
+	Widgets       []DashboardWidgetReq `json:"widgets" label:"组件" binding:"dive"`
+}
+
+// DashboardWidgetReq 仪表盘组件 ID 为空表示新增
+type DashboardWidgetReq struct {
+	ID              uint                   `json:"id" label:"组件ID"`
+	Title           string                 `json:"title" label:"组件标题" binding:"required,max=64"`
+	Type            string                 `json:"type" label:"组件类型" binding:"required,oneof=table line bar pie stat"`
+	SavedQueryId    uint                   `json:"savedQueryId" label:"保存的查询" binding:"required"`
+	Params          map[string]interface{} `json:"params" label:"查询参数"`
+	Options         dbModel.WidgetOptions  `json:"options" label:"展示配置"`
+	X               int                    `json:"x" label:"列位置" binding:"min=0"`
+	Y               int                    `json:"y" label:"行位置" binding:"min=0"`
+	W               int                    `json:"w" label:"宽度" binding:"min=1"`
+	H               int                    `json:"h" label:"高度" binding:"min=1,max=100"`
+	RefreshInterval int                    `json:"refreshInterval" label:"刷新间隔" binding:"omitempty,min=5,max=86400"` // 自动刷新间隔(秒) 0 不自动刷新
+}
+
+// DashboardRestoreReq 恢复历史版本
+type DashboardRestoreReq struct {
+	ID      uint `json:"id" label:"仪表盘ID" binding:"required"`
+	Version int  `json:"version" label:"版本号" binding:"required"`
+}
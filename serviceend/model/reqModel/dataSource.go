@@ -0,0 +1,24 @@
+package reqModel
+
+// DataSourceReq 新增/修改/测试数据源 修改时密码为空表示不修改
+type DataSourceReq struct {
+	ID       uint   `json:"id" label:"数据源ID"`
+	Name     string `json:"name" label:"数据源名称" binding:"required,max=64"`
+	Type     string `json:"type" label:"数据源类型" binding:"required,oneof=mysql postgres sqlite csv"`
+	Host     string `json:"host" label:"主机地址" binding:"max=128"`
+	Port     int    `json:"port" label:"端口" binding:"min=0,max=65535"`
+	Database string `json:"database" label:"数据库名" binding:"max=128"`
+	Username string `json:"username" label:"用户名" binding:"max=128"`
+	Password string `json:"password" label:"密码" binding:"max=256"`
+	Params   string `json:"params" label:"连接参数" binding:"max=512"`
+	Path     string `json:"path" label:"文件路径" binding:"max=512"`
+	Status   int    `json:"status" label:"状态" binding:"omitempty,oneof=1 2"`
+	Writable bool   `json:"writable" label:"允许写操作"`
+	Remark   string `json:"remark" label:"备注" binding:"max=255"`
+}
+
+// DataSourceListReq 数据源查询
+type DataSourceListReq struct {
+	PageInfo
+	Type string `json:"type" form:"type" label:"数据源类型"`
+}
@@ -0,0 +1,30 @@
+package reqModel
+
+// DepartmentReq 新增/修改部门
+type DepartmentReq struct {
+	ID       uint   `json:"id" label:"部门ID"`
+	ParentId uint   `json:"parentId" label:"上级部门ID"`
+	Name     string `json:"name" label:"部门名称" binding:"required,max=64"`
+	Leader   string `json:"leader" label:"负责人" binding:"max=64"`
+	Phone    string `json:"phone" label:"联系电话" binding:"omitempty,checkPhone"`
+	Sort     int    `json:"sort" label:"排序"`
+	Status   int    `json:"status" label:"部门状态" binding:"omitempty,oneof=1 2"`
+}
+
+// DepartmentMoveReq 调整上级部门
+type DepartmentMoveReq struct {
+	ID       uint `json:"id" label:"部门ID" binding:"required"`
+	ParentId uint `json:"parentId" label:"上级部门ID"`
+}
+
+// DepartmentUserReq 用户部门分配
+type DepartmentUserReq struct {
+	UserId       uint `json:"userId" label:"用户ID" binding:"required"`
+	DepartmentId uint `json:"departmentId" label:"部门ID" binding:"required"`
+}
+
+// DepartmentUsersReq 部门成员查询
+type DepartmentUsersReq struct {
+	PageInfo
+	DepartmentId uint `json:"departmentId" form:"departmentId" label:"部门ID"`
+}
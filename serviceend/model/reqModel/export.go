@@ -0,0 +1,12 @@
+package reqModel
+
+// ExportReq 导出查询结果 数据来源三选一: 组件、保存的查询、数据源+SQL
+type ExportReq struct {
+	Format       string                 `json:"format" label:"导出格式" binding:"required,oneof=csv xlsx json"`
+	WidgetId     uint                   `json:"widgetId" label:"组件"`
+	SavedQueryId uint                   `json:"savedQueryId" label:"保存的查询"`
+	Params       map[string]interface{} `json:"params" label:"参数"` // 保存的查询的参数值
+	DataSourceId uint                   `json:"dataSourceId" label:"数据源"`
+	Sql          string                 `json:"sql" label:"SQL" binding:"max=65535"`
+	FileName     string                 `json:"fileName" label:"文件名" binding:"max=128"` // 不含扩展名 不传时按来源生成
+}
@@ -0,0 +1,15 @@
+package reqModel
+
+// FileListReq 文件查询
+type FileListReq struct {
+	PageInfo
+	Status int `json:"status" form:"status" label:"状态"`
+}
+
+// FileChunkInitReq 创建/恢复分片上传任务 相同文件(大小+校验值)的未完成任务将被恢复
+type FileChunkInitReq struct {
+	FileName  string `json:"fileName" label:"文件名" binding:"required,max=255"`
+	Size      int64  `json:"size" label:"文件大小" binding:"required,gt=0"`
+	Sha256    string `json:"sha256" label:"文件校验值" binding:"omitempty,len=64,hexadecimal"`
+	ChunkSize int64  `json:"chunkSize" label:"分片大小" binding:"required,gt=0"`
+}
@@ -0,0 +1,34 @@
+package reqModel
+
+// ImportSourceReq 导入文件 上传后的文件ID 或桌面端选择的本地路径
+type ImportSourceReq struct {
+	FileId   uint   `json:"fileId" label:"文件"`
+	Path     string `json:"path" label:"文件路径"`                  // 仅桌面端可用
+	Sheet    string `json:"sheet" label:"工作表" binding:"max=64"` // xlsx 工作表 不传读取第一个
+	NoHeader bool   `json:"noHeader" label:"无表头"`               // 首行为数据 列名按 column1、column2 生成
+}
+
+// ImportPreviewReq 预览导入文件
+type ImportPreviewReq struct {
+	ImportSourceReq
+	Rows int `json:"rows" label:"预览行数" binding:"omitempty,min=1,max=200"`
+}
+
+// ImportReq 导入到本地数据库
+type ImportReq struct {
+	ImportSourceReq
+	Table       string            `json:"table" label:"目标表" binding:"required,max=64"`
+	Mode        string            `json:"mode" label:"导入方式" binding:"required,oneof=create append"` // create 新建表 append 追加到已有表
+	Columns     []ImportColumnReq `json:"columns" label:"列映射" binding:"required,min=1,dive"`
+	SkipInvalid bool              `json:"skipInvalid" label:"跳过错误行"` // 不跳过时有错误行则不导入任何数据
+	BatchSize   int               `json:"batchSize" label:"每批行数" binding:"omitempty,min=1,max=5000"`
+}
+
+// ImportColumnReq 文件列到目标列的映射
+type ImportColumnReq struct {
+	Source   int    `json:"source" label:"文件列" binding:"min=0"` // 文件中的列序号 从 0 开始
+	Name     string `json:"name" label:"目标列" binding:"required,max=64"`
+	Type     string `json:"type" label:"列类型" binding:"required,oneof=integer real boolean datetime text"`
+	Required bool   `json:"required" label:"必填"`
+	Rules    string `json:"rules" label:"校验规则" binding:"max=255"` // validator 规则 如 max=64,email 非空值时校验
+}
@@ -0,0 +1,8 @@
+package reqModel
+
+// JobRunListReq 任务执行记录
+type JobRunListReq struct {
+	PageInfo
+	JobName string `json:"jobName" form:"jobName" label:"任务"`
+	Status  string `json:"status" form:"status" label:"状态"`
+}
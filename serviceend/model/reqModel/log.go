@@ -0,0 +1,28 @@
+package reqModel
+
+// LogLevelReq 修改日志级别
+type LogLevelReq struct {
+	Level    string `json:"level" label:"日志级别" binding:"required,oneof=debug info warn error dpanic panic fatal"`
+	Duration string `json:"duration" label:"持续时间" binding:"max=32"` // 如 30m 到期后恢复为配置文件中的级别 不传则一直生效
+}
+
+// LogFileListReq 日志文件列表
+type LogFileListReq struct {
+	Date  string `json:"date" form:"date" label:"日期" binding:"omitempty,datetime=2006-01-02"`
+	Level string `json:"level" form:"level" label:"级别" binding:"omitempty,oneof=debug info warn error dpanic panic fatal"`
+}
+
+// LogFilterReq 日志过滤条件 搜索与实时跟踪共用
+type LogFilterReq struct {
+	Levels  []string `json:"levels" form:"levels" label:"级别" binding:"omitempty,dive,oneof=debug info warn error dpanic panic fatal"`
+	Keyword string   `json:"keyword" form:"keyword" label:"关键字" binding:"max=256"`
+	Regex   bool     `json:"regex" form:"regex" label:"正则匹配"` // 关键字按正则表达式匹配 否则忽略大小写包含匹配
+}
+
+// LogSearchReq 日志搜索 不指定时间范围时搜索当天
+type LogSearchReq struct {
+	LogFilterReq
+	Start string `json:"start" form:"start" label:"开始时间" binding:"omitempty,datetime=2006-01-02 15:04:05"`
+	End   string `json:"end" form:"end" label:"结束时间" binding:"omitempty,datetime=2006-01-02 15:04:05"`
+	Limit int    `json:"limit" form:"limit" label:"条数" binding:"omitempty,min=1,max=1000"` // 默认 200
+}
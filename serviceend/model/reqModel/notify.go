@@ -0,0 +1,9 @@
+package reqModel
+
+// NotifyLogListReq 通知投递记录
+type NotifyLogListReq struct {
+	PageInfo
+	Channel string `json:"channel" form:"channel" label:"渠道"`
+	Event   string `json:"event" form:"event" label:"事件"`
+	Status  string `json:"status" form:"status" label:"状态"`
+}
@@ -0,0 +1,21 @@
+package reqModel
+
+// QueryRunReq 执行即席查询
+type QueryRunReq struct {
+	DataSourceId uint   `json:"dataSourceId" label:"数据源" binding:"required"`
+	Sql          string `json:"sql" label:"SQL" binding:"required,max=65535"`
+	PageSize     int    `json:"pageSize" label:"每页行数" binding:"omitempty,min=1,max=5000"` // 每页/每批行数 不传使用配置
+}
+
+// QueryNextReq 获取下一页结果
+type QueryNextReq struct {
+	PageSize int `json:"pageSize" form:"pageSize" label:"每页行数" binding:"omitempty,min=1,max=5000"`
+}
+
+// QueryLogListReq 查询记录
+type QueryLogListReq struct {
+	PageInfo
+	DataSourceId uint   `json:"dataSourceId" form:"dataSourceId" label:"数据源"`
+	SavedQueryId uint   `json:"savedQueryId" form:"savedQueryId" label:"保存的查询"`
+	Status       string `json:"status" form:"status" label:"状态"`
+}
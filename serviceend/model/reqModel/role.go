@@ -0,0 +1,46 @@
+package reqModel
+
+// RoleReq 新增/修改角色
+type RoleReq struct {
+	ID        uint   `json:"id" label:"角色ID"`
+	RoleName  string `json:"roleName" label:"角色名" binding:"required,max=64"`
+	RoleKey   string `json:"roleKey" label:"角色标识" binding:"max=64"`
+	Status    int    `json:"status" label:"角色状态" binding:"omitempty,oneof=1 2"`
+	Sort      int    `json:"sort" label:"排序"`
+	DataScope int    `json:"dataScope" label:"数据权限" binding:"omitempty,oneof=1 2 3 4"`
+	Remark    string `json:"remark" label:"备注" binding:"max=255"`
+}
+
+// RoleAuthReq 设置角色的接口权限或菜单
+type RoleAuthReq struct {
+	RoleId uint   `json:"roleId" label:"角色ID" binding:"required"`
+	Ids    []uint `json:"ids" label:"授权ID列表"`
+}
+
+// UserRoleReq 为用户分配角色
+type UserRoleReq struct {
+	UserId  uint   `json:"userId" label:"用户ID" binding:"required"`
+	RoleIds []uint `json:"roleIds" label:"角色ID列表"`
+}
+
+// PermissionReq 新增/修改接口权限
+type PermissionReq struct {
+	ID          uint   `json:"id" label:"权限ID"`
+	Path        string `json:"path" label:"路由" binding:"required,max=191"`
+	Method      string `json:"method" label:"请求方法" binding:"required,oneof=GET POST PUT DELETE PATCH"`
+	ApiGroup    string `json:"apiGroup" label:"分组" binding:"max=64"`
+	Description string `json:"description" label:"描述" binding:"max=255"`
+}
+
+// MenuReq 新增/修改菜单
+type MenuReq struct {
+	ID        uint   `json:"id" label:"菜单ID"`
+	ParentId  uint   `json:"parentId" label:"父菜单ID"`
+	Name      string `json:"name" label:"路由name" binding:"required,max=64"`
+	Title     string `json:"title" label:"菜单名" binding:"required,max=64"`
+	Path      string `json:"path" label:"路由path" binding:"max=191"`
+	Component string `json:"component" label:"前端组件路径" binding:"max=191"`
+	Icon      string `json:"icon" label:"图标" binding:"max=64"`
+	Sort      int    `json:"sort" label:"排序"`
+	Hidden    bool   `json:"hidden" label:"是否隐藏"`
+}
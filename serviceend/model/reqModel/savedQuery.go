@@ -0,0 +1,33 @@
+package reqModel
+
+// SavedQueryReq 新增/修改保存的查询
+type SavedQueryReq struct {
+	ID           uint                 `json:"id" label:"查询ID"`
+	Name         string               `json:"name" label:"查询名称" binding:"required,max=64"`
+	DataSourceId uint                 `json:"dataSourceId" label:"数据源" binding:"required"`
+	Sql          string               `json:"sql" label:"SQL" binding:"required,max=65535"`
+	Params       []SavedQueryParamReq `json:"params" label:"参数" binding:"dive"`
+	Remark       string               `json:"remark" label:"备注" binding:"max=255"`
+}
+
+// SavedQueryParamReq 查询参数定义
+type SavedQueryParamReq struct {
+	Name     string   `json:"name" label:"参数名" binding:"required,max=64"`
+	Label    string   `json:"label" label:"参数显示名" binding:"max=64"`
+	Type     string   `json:"type" label:"参数类型" binding:"required,oneof=date number string enum"`
+	Required bool     `json:"required" label:"是否必填"`
+	Default  string   `json:"default" label:"默认值" binding:"max=255"`
+	Options  []string `json:"options" label:"可选值" binding:"required_if=Type enum,dive,required,max=64"`
+}
+
+// SavedQueryListReq 保存的查询列表
+type SavedQueryListReq struct {
+	PageInfo
+	DataSourceId uint `json:"dataSourceId" form:"dataSourceId" label:"数据源"`
+}
+
+// SavedQueryExecReq 执行保存的查询
+type SavedQueryExecReq struct {
+	Params   map[string]interface{} `json:"params" label:"参数"`
+	PageSize int                    `json:"pageSize" label:"每页行数" binding:"omitempty,min=1,max=5000"`
+}
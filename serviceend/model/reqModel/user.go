@@ -0,0 +1,17 @@
+package reqModel
+
+// LoginReq 用户登录
+type LoginReq struct {
+	Username string `json:"username" label:"用户名" binding:"required,max=64"` // 用户名
+	Password string `json:"password" label:"密码" binding:"required,max=64"`  // 密码
+}
+
+// RefreshTokenReq 刷新令牌
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refreshToken" label:"刷新令牌" binding:"required"`
+}
+
+// LogoutReq 退出登录 可同时注销刷新令牌
+type LogoutReq struct {
+	RefreshToken string `json:"refreshToken" label:"刷新令牌"`
+}
@@ -0,0 +1,16 @@
+package reqModel
+
+// SendCodeReq 发送验证码 手机号与邮箱二选一
+type SendCodeReq struct {
+	Phone string `json:"phone" label:"手机号" binding:"required_without=Email,omitempty,checkPhone"`
+	Email string `json:"email" label:"邮箱" binding:"required_without=Phone,omitempty,email,max=128"`
+	Scene string `json:"scene" label:"业务场景" binding:"required,max=32,alphanum"`
+}
+
+// VerifyCodeReq 校验验证码
+type VerifyCodeReq struct {
+	Phone string `json:"phone" label:"手机号" binding:"required_without=Email,omitempty,checkPhone"`
+	Email string `json:"email" label:"邮箱" binding:"required_without=Phone,omitempty,email,max=128"`
+	Scene string `json:"scene" label:"业务场景" binding:"required,max=32,alphanum"`
+	Code  string `json:"code" label:"验证码" binding:"required,numeric,max=10"`
+}
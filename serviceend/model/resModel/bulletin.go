@@ -0,0 +1,31 @@
+package resModel
+
+import (
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/utils"
+)
+
+// BulletinRes 当前用户可见的公告 附带已读状态
+type BulletinRes struct {
+	dbModel.SysBulletin
+	Read   bool             `json:"read"`             // 是否已读
+	ReadAt *utils.LocalTime `json:"readAt,omitempty"` // 阅读时间
+}
+
+// BulletinReceiptRes 公告已读回执
+type BulletinReceiptRes struct {
+	UserId   uint            `json:"userId"`
+	Username string          `json:"username"`
+	NickName string          `json:"nickName"`
+	ReadAt   utils.LocalTime `json:"readAt"`
+}
+
+// BulletinEvent 推送到桌面窗口的公告事件 前端按 groupIds 判断当前用户是否可见
+type BulletinEvent struct {
+	ID        uint             `json:"id"`
+	Title     string           `json:"title"`
+	Level     int              `json:"level"`
+	PublishAt *utils.LocalTime `json:"publishAt"`
+	ExpireAt  *utils.LocalTime `json:"expireAt"`
+	GroupIds  []uint           `json:"groupIds"`
+}
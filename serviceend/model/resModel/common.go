@@ -0,0 +1,9 @@
+package resModel
+
+// PageResult 分页结果
+type PageResult struct {
+	List     interface{} `json:"list"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+}
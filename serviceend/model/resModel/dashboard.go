@@ -0,0 +1,23 @@
+package resModel
+
+// WidgetData 组件数据 按组件类型整理 前端直接渲染
+// table: Columns/Rows; line/bar/pie: Categories/Series; stat: Value
+type WidgetData struct {
+	WidgetId   uint            `json:"widgetId"`
+	Type       string          `json:"type"`
+	Columns    []QueryColumn   `json:"columns,omitempty"`
+	Rows       [][]interface{} `json:"rows,omitempty"`
+	Categories []interface{}   `json:"categories,omitempty"`
+	Series     []WidgetSeries  `json:"series,omitempty"`
+	Value      interface{}     `json:"value,omitempty"`
+	Unit       string          `json:"unit,omitempty"`
+	Truncated  bool            `json:"truncated"`       // 结果超出行数上限被截断
+	UpdatedAt  string          `json:"updatedAt"`       // 数据时间
+	Error      string          `json:"error,omitempty"` // 自动刷新失败的原因
+}
+
+// WidgetSeries 图表序列
+type WidgetSeries struct {
+	Name string        `json:"name"`
+	Data []interface{} `json:"data"`
+}
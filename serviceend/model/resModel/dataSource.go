@@ -0,0 +1,6 @@
+package resModel
+
+// DataSourceTestRes 连接测试结果
+type DataSourceTestRes struct {
+	Latency int64 `json:"latency"` // 连接耗时(毫秒)
+}
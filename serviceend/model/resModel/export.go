@@ -0,0 +1,7 @@
+package resModel
+
+// ExportResult 导出到本地文件的结果
+type ExportResult struct {
+	Path string `json:"path"` // 保存路径 取消保存时为空
+	Rows int64  `json:"rows"` // 导出行数
+}
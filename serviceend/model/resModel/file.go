@@ -0,0 +1,19 @@
+package resModel
+
+// FileChunkRes 分片上传任务状态
+type FileChunkRes struct {
+	UploadId   string `json:"uploadId"`
+	FileName   string `json:"fileName"`
+	Size       int64  `json:"size"`
+	ChunkSize  int64  `json:"chunkSize"`
+	ChunkTotal int    `json:"chunkTotal"`
+	Uploaded   []int  `json:"uploaded"` // 已上传的分片序号 断点续传时跳过
+}
+
+// FileVerifyRes 文件完整性校验结果
+type FileVerifyRes struct {
+	ID       uint   `json:"id"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Valid    bool   `json:"valid"`
+}
@@ -0,0 +1,40 @@
+package resModel
+
+// ImportPreview 导入文件预览
+type ImportPreview struct {
+	FileName string         `json:"fileName"`
+	Format   string         `json:"format"` // csv|xlsx
+	Sheets   []string       `json:"sheets"` // xlsx 工作表
+	Sheet    string         `json:"sheet"`  // 当前工作表
+	Columns  []ImportColumn `json:"columns"`
+	Rows     [][]string     `json:"rows"` // 前若干行数据 不含表头
+}
+
+// ImportColumn 文件中的列 类型按前若干行推断
+type ImportColumn struct {
+	Source int    `json:"source"` // 列序号
+	Name   string `json:"name"`
+	Type   string `json:"type"` // integer|real|boolean|datetime|text
+}
+
+// ImportTable 本地数据库中可导入的表
+type ImportTable struct {
+	Name    string        `json:"name"`
+	Columns []QueryColumn `json:"columns"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Table    string           `json:"table"`
+	Total    int              `json:"total"`    // 读取的数据行数
+	Imported int              `json:"imported"` // 导入的行数
+	Failed   int              `json:"failed"`   // 校验未通过的行数
+	Errors   []ImportRowError `json:"errors"`   // 错误明细 最多保留前 200 条
+}
+
+// ImportRowError 行错误
+type ImportRowError struct {
+	Row     int    `json:"row"` // 文件中的行号
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
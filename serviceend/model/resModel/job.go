@@ -0,0 +1,18 @@
+package resModel
+
+import "dataPanel/serviceend/model/dbModel"
+
+// JobInfo 已注册的定时任务
+type JobInfo struct {
+	Name    string             `json:"name"`    // 任务标识
+	Title   string             `json:"title"`   // 显示名称
+	Cron    string             `json:"cron"`    // cron 表达式 一次性任务为空
+	At      string             `json:"at"`      // 一次性任务执行时间
+	Policy  string             `json:"policy"`  // 并发策略 skip|queue|parallel
+	Retries int                `json:"retries"` // 失败重试次数
+	Paused  bool               `json:"paused"`  // 是否暂停
+	Running int                `json:"running"` // 执行中的数量
+	Pending int                `json:"pending"` // 排队等待的数量
+	NextRun string             `json:"nextRun"` // 下次执行时间 暂停或一次性任务已完成时为空
+	LastRun *dbModel.SysJobRun `json:"lastRun"` // 最近一次执行
+}
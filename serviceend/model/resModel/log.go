@@ -0,0 +1,37 @@
+package resModel
+
+// LogLevel 当前日志级别
+type LogLevel struct {
+	Level       string `json:"level"`       // 当前生效的级别
+	ConfigLevel string `json:"configLevel"` // 配置文件中的级别
+	RevertAt    string `json:"revertAt"`    // 自动恢复为配置级别的时间 不自动恢复时为空
+}
+
+// LogFile 日志文件
+type LogFile struct {
+	Date       string `json:"date"`       // 日期目录 如 2006-01-02
+	Level      string `json:"level"`      // 级别 即文件名前缀
+	Name       string `json:"name"`       // 文件名 按大小切分的文件带时分秒
+	Size       int64  `json:"size"`       // 文件大小 压缩文件为压缩后的大小
+	Compressed bool   `json:"compressed"` // 是否已 gzip 压缩
+	ModifiedAt string `json:"modifiedAt"` // 最后修改时间
+}
+
+// LogEntry 解析后的一条日志
+type LogEntry struct {
+	Time    string                 `json:"time"`
+	Level   string                 `json:"level"`
+	Logger  string                 `json:"logger,omitempty"`
+	Caller  string                 `json:"caller,omitempty"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"` // 附加字段
+	Stack   string                 `json:"stack,omitempty"`  // 堆栈
+	File    string                 `json:"file"`             // 所在文件 相对日志目录
+}
+
+// LogSearchResult 日志搜索结果 按时间倒序
+type LogSearchResult struct {
+	List      []LogEntry `json:"list"`
+	Truncated bool       `json:"truncated"` // 匹配条数超过上限 只返回最新的部分
+	Files     int        `json:"files"`     // 搜索的文件数
+}
@@ -0,0 +1,34 @@
+package resModel
+
+// QueryColumn 结果列
+type QueryColumn struct {
+	Name string `json:"name"` // 列名
+	Type string `json:"type"` // 数据库类型
+}
+
+// QueryPage 分页查询结果 Done 为 true 时查询已结束,不能再获取下一页
+type QueryPage struct {
+	QueryId  string          `json:"queryId"`
+	Columns  []QueryColumn   `json:"columns,omitempty"`
+	Rows     [][]interface{} `json:"rows"`
+	RowCount int64           `json:"rowCount"` // 已返回行数 写语句为影响行数
+	Duration int64           `json:"duration"` // 已执行耗时(毫秒)
+	Done     bool            `json:"done"`
+}
+
+// QueryChunk 流式查询推送的一批结果 事件 query:chunk
+type QueryChunk struct {
+	QueryId string          `json:"queryId"`
+	Seq     int             `json:"seq"`               // 批次序号 从0开始
+	Columns []QueryColumn   `json:"columns,omitempty"` // 仅首批携带
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// QueryDone 查询结束 事件 query:done
+type QueryDone struct {
+	QueryId  string `json:"queryId"`
+	Status   string `json:"status"`   // success|failed|cancelled|timeout
+	RowCount int64  `json:"rowCount"` // 返回/影响行数
+	Duration int64  `json:"duration"` // 执行耗时(毫秒)
+	Error    string `json:"error"`
+}
@@ -0,0 +1,12 @@
+package resModel
+
+import "dataPanel/serviceend/model/dbModel"
+
+// LoginRes 登录/刷新令牌返回
+type LoginRes struct {
+	User             dbModel.SysUser `json:"user"`
+	Token            string          `json:"token"`
+	ExpiresAt        int64           `json:"expiresAt"` // 访问令牌过期时间 毫秒时间戳
+	RefreshToken     string          `json:"refreshToken"`
+	RefreshExpiresAt int64           `json:"refreshExpiresAt"` // 刷新令牌过期时间 毫秒时间戳
+}
@@ -0,0 +1,7 @@
+package resModel
+
+// SendCodeRes 发送验证码结果
+type SendCodeRes struct {
+	ExpiresIn int64 `json:"expiresIn"` // 验证码有效期(秒)
+	Remaining int   `json:"remaining"` // 本小时剩余可发送次数
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"dataPanel/serviceend/common"
 	"dataPanel/serviceend/controller"
 
 	"github.com/gin-gonic/gin"
@@ -12,5 +13,35 @@ type RouterInf interface {
 }
 
 func SetupRouter(g *gin.RouterGroup) {
-	controller.NewHelloController().SetupRouter(g)
+	// 公共路由 无需登录
+	publicGroup := g.Group("")
+	controller.NewHelloController().SetupRouter(publicGroup)
+	controller.NewBaseController().SetupRouter(publicGroup)
+
+	// 私有路由 需登录鉴权
+	privateGroup := g.Group("")
+	privateGroup.Use(common.JwtAuth())
+	controller.NewUserController().SetupRouter(privateGroup)
+
+	// 授权路由 需登录且角色拥有对应接口权限
+	authGroup := g.Group("")
+	authGroup.Use(common.JwtAuth(), common.Permission())
+	controller.NewSessionController().SetupRouter(authGroup)
+	controller.NewRoleController().SetupRouter(authGroup)
+	controller.NewPermissionController().SetupRouter(authGroup)
+	controller.NewMenuController().SetupRouter(authGroup)
+	controller.NewDepartmentController().SetupRouter(authGroup)
+	controller.NewUserGroupController().SetupRouter(authGroup)
+	controller.NewBulletinController().SetupRouter(authGroup)
+	controller.NewFileController().SetupRouter(authGroup)
+	controller.NewDataSourceController().SetupRouter(authGroup)
+	controller.NewQueryController().SetupRouter(authGroup)
+	controller.NewSavedQueryController().SetupRouter(authGroup)
+	controller.NewDashboardController().SetupRouter(authGroup)
+	controller.NewJobController().SetupRouter(authGroup)
+	controller.NewAlertController().SetupRouter(authGroup)
+	controller.NewNotifyController().SetupRouter(authGroup)
+	controller.NewExportController().SetupRouter(authGroup)
+	controller.NewImportController().SetupRouter(authGroup)
+	controller.NewLogController().SetupRouter(authGroup)
 }
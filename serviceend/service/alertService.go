@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/notify"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	EventAlertFire = "alert:fire" // 告警触发
+	EventAlertOpen = "alert:open" // 从状态栏打开告警列表
+)
+
+// alertMaxRows 指标为行数时最多读取的行数 超出按上限计
+const alertMaxRows = 10000
+
+// AlertNotifyFunc 桌面端告警通知 由 App.Startup 注册, 弹出系统通知并更新状态栏的未确认数量
+// 仅未确认数量变化时 event 为 nil
+type AlertNotifyFunc func(event *dbModel.SysAlertEvent, unacked int64)
+
+var alertNotifier atomic.Pointer[AlertNotifyFunc]
+
+type AlertService struct{}
+
+// SetNotifier 注册桌面端告警通知 仅以 http 服务运行时不注册
+func (a AlertService) SetNotifier(fn AlertNotifyFunc) {
+	alertNotifier.Store(&fn)
+}
+
+// LoadRules 为启用的告警规则注册定时任务 需在 JobService.Start 之后调用
+func (a AlertService) LoadRules() error {
+	var rules []dbModel.SysAlertRule
+	if err := global.GvaDb.Where("status = ?", dbModel.AlertEnable).Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := a.schedule(rule); err != nil {
+			global.GvaLog.Error("告警规则加载失败", zap.String("name", rule.Name), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// List 告警规则列表
+func (a AlertService) List(req reqModel.AlertRuleListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysAlertRule{})
+	if req.State != "" {
+		db = db.Where("state = ?", req.State)
+	}
+	if req.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysAlertRule
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 告警规则详情
+func (a AlertService) Get(id uint) (rule dbModel.SysAlertRule, err error) {
+	err = global.GvaDb.First(&rule, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return rule, ApiReturn.NoAlertRule
+	}
+	return
+}
+
+// Save 新增/修改告警规则 保存后重置告警状态, 未恢复的告警记为已恢复
+func (a AlertService) Save(userId uint, req reqModel.AlertRuleReq) (rule dbModel.SysAlertRule, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysAlertRule{}).Where("name = ? AND id <> ?", req.Name, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return rule, ApiReturn.AlertRuleNameExisted
+	}
+	if err = a.check(req); err != nil {
+		return
+	}
+	if req.ID != 0 {
+		if rule, err = a.Get(req.ID); err != nil {
+			return
+		}
+	} else {
+		rule.CreatedBy = userId
+	}
+	rule.Name, rule.SavedQueryId, rule.Params, rule.Cron = req.Name, req.SavedQueryId, req.Params, req.Cron
+	rule.Metric, rule.Field, rule.Operator, rule.Threshold = req.Metric, req.Field, req.Operator, req.Threshold
+	rule.Times, rule.Status, rule.Remark = max(req.Times, 1), req.Status, req.Remark
+	if rule.Status == 0 {
+		rule.Status = dbModel.AlertEnable
+	}
+	if rule.Metric != dbModel.AlertMetricValue {
+		rule.Field = ""
+	}
+	rule.State, rule.Hits, rule.LastValue, rule.LastError = dbModel.AlertOk, 0, nil, ""
+	err = global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&rule).Error; err != nil {
+			return err
+		}
+		return a.resolve(tx, rule.ID)
+	})
+	if err != nil {
+		return
+	}
+	err = a.schedule(rule)
+	return
+}
+
+// Delete 删除告警规则及其告警记录
+func (a AlertService) Delete(ids []uint) error {
+	for _, id := range ids {
+		ServiceGroupApp.JobService.Unregister(a.jobName(id))
+	}
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id IN ?", ids).Delete(&dbModel.SysAlertEvent{}).Error; err != nil {
+			return err
+		}
+		// 物理删除 名称唯一索引包含已软删除的行
+		return tx.Unscoped().Delete(&dbModel.SysAlertRule{}, ids).Error
+	})
+	if err != nil {
+		return err
+	}
+	a.notify(nil)
+	return nil
+}
+
+// Silence 静默告警规则 静默期间触发只记录不通知
+func (a AlertService) Silence(req reqModel.AlertSilenceReq) (rule dbModel.SysAlertRule, err error) {
+	duration, err := utils.ParseDuration(req.Duration)
+	if err != nil || duration < 0 {
+		return rule, ApiReturn.ErrParam.WithData("静默时长格式错误 如 30m、2h、1d")
+	}
+	if rule, err = a.Get(req.ID); err != nil {
+		return
+	}
+	rule.SilenceUntil = nil
+	if duration > 0 {
+		until := utils.LocalTime(time.Now().Add(duration))
+		rule.SilenceUntil = &until
+	}
+	err = global.GvaDb.Model(&rule).Update("silence_until", rule.SilenceUntil).Error
+	return
+}
+
+// Events 告警记录
+func (a AlertService) Events(req reqModel.AlertEventListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysAlertEvent{})
+	if req.RuleId != 0 {
+		db = db.Where("rule_id = ?", req.RuleId)
+	}
+	if req.Unacked {
+		db = db.Where("acked_at IS NULL")
+	}
+	if req.Keyword != "" {
+		db = db.Where("rule_name LIKE ? OR message LIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysAlertEvent
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Ack 确认告警记录
+func (a AlertService) Ack(userId uint, ids []uint) error {
+	now := utils.LocalTime(time.Now())
+	if err := global.GvaDb.Model(&dbModel.SysAlertEvent{}).Where("id IN ? AND acked_at IS NULL", ids).
+		Updates(map[string]interface{}{"acked_by": userId, "acked_at": now}).Error; err != nil {
+		return err
+	}
+	a.notify(nil)
+	return nil
+}
+
+// Unacked 未确认且已通知的告警数量
+func (a AlertService) Unacked() (count int64, err error) {
+	err = global.GvaDb.Model(&dbModel.SysAlertEvent{}).Where("acked_at IS NULL AND silenced = ?", false).Count(&count).Error
+	return
+}
+
+// check 校验执行计划、查询参数与阈值
+func (a AlertService) check(req reqModel.AlertRuleReq) error {
+	if _, err := jobCronParser.Parse(req.Cron); err != nil {
+		return ApiReturn.ErrParam.WithData("执行计划格式错误: " + err.Error())
+	}
+	if req.Operator == dbModel.AlertChange && req.Threshold <= 0 {
+		return ApiReturn.ErrParam.WithData("变化百分比阈值必须大于 0")
+	}
+	query, err := ServiceGroupApp.SavedQueryService.Get(req.SavedQueryId)
+	if err != nil {
+		return err
+	}
+	_, err = ServiceGroupApp.SavedQueryService.values(query.Params, req.Params)
+	return err
+}
+
+// schedule 按规则注册定时任务 停用的规则只取消注册
+func (a AlertService) schedule(rule dbModel.SysAlertRule) error {
+	name := a.jobName(rule.ID)
+	ServiceGroupApp.JobService.Unregister(name)
+	if rule.Status != dbModel.AlertEnable {
+		return nil
+	}
+	id := rule.ID
+	return ServiceGroupApp.JobService.Register(FuncJob{
+		JobSpec: JobSpec{Name: name, Title: "告警规则 " + rule.Name, Cron: rule.Cron},
+		Fn: func(ctx context.Context) error {
+			return a.evaluate(id)
+		},
+	})
+}
+
+func (a AlertService) jobName(id uint) string {
+	return "alert:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// evaluate 执行一次规则 连续满足次数达到要求时触发, 条件不再满足时恢复
+func (a AlertService) evaluate(id uint) error {
+	rule, err := a.Get(id)
+	if err != nil || rule.Status != dbModel.AlertEnable {
+		return err
+	}
+	now := utils.LocalTime(time.Now())
+	value, err := a.measure(rule)
+	if err != nil {
+		msg := err.Error()
+		var apiErr ApiReturn.ApiReturnCode
+		if errors.As(err, &apiErr) && apiErr.Data != nil {
+			msg = fmt.Sprintf("%s: %v", apiErr.Msg, apiErr.Data)
+		}
+		if dbErr := global.GvaDb.Model(&rule).Updates(map[string]interface{}{"last_eval_at": now, "last_error": msg}).Error; dbErr != nil {
+			global.GvaLog.Error("告警规则状态保存失败", zap.Uint("id", id), zap.Error(dbErr))
+		}
+		return err
+	}
+	holds := a.holds(rule, value)
+	updates := map[string]interface{}{"hits": 0, "last_value": value, "last_eval_at": now, "last_error": ""}
+	if holds {
+		updates["hits"] = rule.Hits + 1
+	}
+	fire := holds && rule.Hits+1 >= rule.Times && rule.State != dbModel.AlertFiring
+	resolve := !holds && rule.State == dbModel.AlertFiring
+	event := dbModel.SysAlertEvent{RuleId: rule.ID, RuleName: rule.Name, Value: value, Message: a.message(rule, value)}
+	err = global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		switch {
+		case fire:
+			updates["state"] = dbModel.AlertFiring
+			event.Silenced = rule.SilenceUntil != nil && rule.SilenceUntil.ToTime().After(time.Now())
+			if err := tx.Create(&event).Error; err != nil {
+				return err
+			}
+		case resolve:
+			updates["state"] = dbModel.AlertOk
+			if err := a.resolve(tx, rule.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&rule).Updates(updates).Error
+	})
+	if err != nil {
+		return err
+	}
+	if fire {
+		global.GvaLog.Warn("告警触发", zap.String("name", rule.Name), zap.Float64("value", value), zap.Bool("silenced", event.Silenced))
+		if !event.Silenced {
+			ServiceGroupApp.EventService.Emit(EventAlertFire, event)
+			a.notify(&event)
+			ServiceGroupApp.NotifyService.Send(notify.Message{Event: NotifyAlertFire, Level: "warning", Title: "告警: " + rule.Name,
+				Content: event.Message, Data: event})
+		}
+	}
+	if resolve {
+		global.GvaLog.Info("告警恢复", zap.String("name", rule.Name), zap.Float64("value", value))
+		ServiceGroupApp.NotifyService.Send(notify.Message{Event: NotifyAlertResolve, Level: "info", Title: "告警恢复: " + rule.Name,
+			Content: fmt.Sprintf("当前值 %s, 告警条件已不再满足", strconv.FormatFloat(value, 'f', -1, 64))})
+	}
+	return nil
+}
+
+// measure 执行规则绑定的查询并取指标值
+func (a AlertService) measure(rule dbModel.SysAlertRule) (float64, error) {
+	runReq, opts, err := ServiceGroupApp.SavedQueryService.bind(rule.SavedQueryId, reqModel.SavedQueryExecReq{Params: rule.Params})
+	if err != nil {
+		return 0, err
+	}
+	runReq.PageSize, opts.maxRows = 1, 1
+	if rule.Metric == dbModel.AlertMetricRowCount {
+		runReq.PageSize, opts.maxRows = alertMaxRows, alertMaxRows
+	}
+	page, err := ServiceGroupApp.QueryService.run(0, runReq, opts)
+	if err != nil {
+		return 0, err
+	}
+	if rule.Metric == dbModel.AlertMetricRowCount {
+		return float64(len(page.Rows)), nil
+	}
+	if len(page.Rows) == 0 {
+		return 0, errors.New("查询无结果")
+	}
+	for i, column := range page.Columns {
+		if column.Name != rule.Field {
+			continue
+		}
+		switch v := page.Rows[0][i].(type) {
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+		return 0, fmt.Errorf("列 %s 的值 %v 不是数值", rule.Field, page.Rows[0][i])
+	}
+	return 0, fmt.Errorf("查询结果中没有列 %s", rule.Field)
+}
+
+// holds 判断条件是否满足 变化百分比与上次取值比较, 无上次取值或上次为 0 时不满足
+func (a AlertService) holds(rule dbModel.SysAlertRule, value float64) bool {
+	switch rule.Operator {
+	case dbModel.AlertAbove:
+		return value > rule.Threshold
+	case dbModel.AlertBelow:
+		return value < rule.Threshold
+	case dbModel.AlertChange:
+		if rule.LastValue == nil || *rule.LastValue == 0 {
+			return false
+		}
+		return math.Abs(value-*rule.LastValue)/math.Abs(*rule.LastValue)*100 >= rule.Threshold
+	}
+	return false
+}
+
+// message 告警内容
+func (a AlertService) message(rule dbModel.SysAlertRule, value float64) string {
+	metric := "行数"
+	if rule.Metric == dbModel.AlertMetricValue {
+		metric = rule.Field
+	}
+	current := strconv.FormatFloat(value, 'f', -1, 64)
+	threshold := strconv.FormatFloat(rule.Threshold, 'f', -1, 64)
+	switch rule.Operator {
+	case dbModel.AlertAbove:
+		return fmt.Sprintf("%s 为 %s, 高于阈值 %s", metric, current, threshold)
+	case dbModel.AlertBelow:
+		return fmt.Sprintf("%s 为 %s, 低于阈值 %s", metric, current, threshold)
+	default:
+		return fmt.Sprintf("%s 为 %s, 变化超过 %s%%", metric, current, threshold)
+	}
+}
+
+// resolve 规则下未恢复的告警记为已恢复
+func (a AlertService) resolve(tx *gorm.DB, ruleId uint) error {
+	return tx.Model(&dbModel.SysAlertEvent{}).Where("rule_id = ? AND resolved_at IS NULL", ruleId).
+		Update("resolved_at", utils.LocalTime(time.Now())).Error
+}
+
+// notify 通知桌面端 event 为空时只更新未确认数量
+func (a AlertService) notify(event *dbModel.SysAlertEvent) {
+	fn := alertNotifier.Load()
+	if fn == nil || *fn == nil {
+		return
+	}
+	count, err := a.Unacked()
+	if err != nil {
+		global.GvaLog.Warn("未确认告警数量查询失败", zap.Error(err))
+	}
+	(*fn)(event, count)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"time"
+)
+
+// builtinJobs 内置定时任务 JobService.Start 时注册
+var builtinJobs = []Job{
+	FuncJob{
+		JobSpec: JobSpec{Name: "cleanChunks", Title: "清理过期分片上传", Cron: "@hourly"},
+		Fn: func(ctx context.Context) error {
+			return ServiceGroupApp.FileService.CleanChunks()
+		},
+	},
+	FuncJob{
+		JobSpec: JobSpec{Name: "cleanVerificationCodes", Title: "清理过期验证码", Cron: "@hourly"},
+		Fn: func(ctx context.Context) error {
+			return ServiceGroupApp.VerificationService.CleanExpired()
+		},
+	},
+	FuncJob{
+		JobSpec: JobSpec{Name: "cleanJobRuns", Title: "清理任务执行记录", Cron: "0 30 3 * * *", Retries: 2},
+		Fn: func(ctx context.Context) error {
+			return global.GvaDb.WithContext(ctx).Where("created_at < ? AND status <> ?", time.Now().Add(-jobRunKeepTime), dbModel.JobRunning).
+				Delete(&dbModel.SysJobRun{}).Error
+		},
+	},
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	EventBulletinNew    = "bulletin:new"    // 公告生效
+	EventBulletinExpire = "bulletin:expire" // 公告过期/撤回
+)
+
+type BulletinService struct{}
+
+// bulletinTimers 定时公告的生效/过期定时器 公告ID => 定时器
+var bulletinTimers = struct {
+	sync.Mutex
+	timers map[uint][]*time.Timer
+}{timers: map[uint][]*time.Timer{}}
+
+// LoadSchedules 启动时为已发布且未过期的公告重建定时器
+func (b BulletinService) LoadSchedules() error {
+	var list []dbModel.SysBulletin
+	if err := global.GvaDb.Preload("Groups").Where("status = ? AND (expire_at IS NULL OR expire_at > ?)", dbModel.BulletinPublished, time.Now()).
+		Find(&list).Error; err != nil {
+		return err
+	}
+	for _, bulletin := range list {
+		b.schedule(bulletin, false)
+	}
+	global.GvaLog.Info("公告定时任务加载完成", zap.Int("count", len(list)))
+	return nil
+}
+
+// List 公告管理列表
+func (b BulletinService) List(req reqModel.BulletinListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysBulletin{})
+	if req.Status != 0 {
+		db = db.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		db = db.Where("title LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysBulletin
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Preload("Groups").Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 公告详情
+func (b BulletinService) Get(id uint) (bulletin dbModel.SysBulletin, err error) {
+	err = global.GvaDb.Preload("Groups").First(&bulletin, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bulletin, ApiReturn.NoData
+	}
+	return
+}
+
+// Save 新增/修改公告 仅草稿/已撤回的公告可修改
+func (b BulletinService) Save(userId uint, req reqModel.BulletinReq) (bulletin dbModel.SysBulletin, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysBulletin{}).Where("title = ? AND id <> ?", req.Title, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return bulletin, ApiReturn.ExistingBulletinName
+	}
+	groups, err := b.checkGroups(req.GroupIds)
+	if err != nil {
+		return
+	}
+	if req.ID != 0 {
+		if bulletin, err = b.Get(req.ID); err != nil {
+			return
+		}
+		if bulletin.Status == dbModel.BulletinPublished {
+			return bulletin, ApiReturn.ErrParam.WithData("已发布的公告请先撤回再修改")
+		}
+	} else {
+		bulletin.Status, bulletin.CreatedBy = dbModel.BulletinDraft, userId
+	}
+	if req.Level == 0 {
+		req.Level = 1
+	}
+	bulletin.Title, bulletin.Content, bulletin.Level = req.Title, req.Content, req.Level
+	err = global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Groups").Save(&bulletin).Error; err != nil {
+			return err
+		}
+		return tx.Model(&bulletin).Association("Groups").Replace(groups)
+	})
+	if err != nil {
+		global.GvaLog.Error("公告保存失败", zap.String("title", req.Title), zap.Error(err))
+		return bulletin, ApiReturn.BulletinFailed
+	}
+	bulletin.Groups = groups
+	return
+}
+
+// Publish 发布公告 生效时间晚于当前时间为定时公告,到期自动推送
+func (b BulletinService) Publish(req reqModel.BulletinPublishReq) (bulletin dbModel.SysBulletin, err error) {
+	if bulletin, err = b.Get(req.ID); err != nil {
+		return
+	}
+	if bulletin.Status == dbModel.BulletinPublished {
+		return bulletin, ApiReturn.ErrParam.WithData("公告已发布")
+	}
+	groupIds := make([]uint, 0, len(bulletin.Groups))
+	for _, group := range bulletin.Groups {
+		groupIds = append(groupIds, group.ID)
+	}
+	if _, err = b.checkGroups(groupIds); err != nil {
+		return
+	}
+	// 统一转换为本地时区 sqlite 以字符串比较时间,时区不一致会导致查询结果错误
+	now := utils.LocalTime(time.Now())
+	publishAt := req.PublishAt
+	if publishAt == nil || publishAt.ToTime().IsZero() || publishAt.ToTime().Before(now.ToTime()) {
+		publishAt = &now
+	} else {
+		local := utils.LocalTime(publishAt.ToTime().In(time.Local))
+		publishAt = &local
+	}
+	expireAt := req.ExpireAt
+	if expireAt != nil && expireAt.ToTime().IsZero() {
+		expireAt = nil
+	} else if expireAt != nil {
+		local := utils.LocalTime(expireAt.ToTime().In(time.Local))
+		expireAt = &local
+	}
+	if expireAt != nil && !expireAt.ToTime().After(publishAt.ToTime()) {
+		return bulletin, ApiReturn.ErrParam.WithData("过期时间需晚于生效时间")
+	}
+	bulletin.Status, bulletin.PublishAt, bulletin.ExpireAt = dbModel.BulletinPublished, publishAt, expireAt
+	if err = global.GvaDb.Model(&bulletin).Select("status", "publish_at", "expire_at").Updates(&bulletin).Error; err != nil {
+		return
+	}
+	// 重新发布的公告清空历史已读回执
+	if err = global.GvaDb.Where("bulletin_id = ?", bulletin.ID).Delete(&dbModel.SysBulletinRead{}).Error; err != nil {
+		return
+	}
+	b.schedule(bulletin, true)
+	return
+}
+
+// Withdraw 撤回公告 取消未触发的定时推送
+func (b BulletinService) Withdraw(id uint) error {
+	bulletin, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+	if bulletin.Status != dbModel.BulletinPublished {
+		return ApiReturn.ErrParam.WithData("公告未发布")
+	}
+	if err = global.GvaDb.Model(&bulletin).Update("status", dbModel.BulletinWithdrawn).Error; err != nil {
+		return err
+	}
+	b.cancel(id)
+	ServiceGroupApp.EventService.Emit(EventBulletinExpire, b.event(bulletin))
+	return nil
+}
+
+// Delete 删除公告 同时删除已读回执与面向人群关联
+func (b BulletinService) Delete(ids []uint) error {
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM sys_bulletin_groups WHERE sys_bulletin_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("bulletin_id IN ?", ids).Delete(&dbModel.SysBulletinRead{}).Error; err != nil {
+			return err
+		}
+		// 物理删除 标题唯一索引包含已软删除的行
+		return tx.Unscoped().Delete(&dbModel.SysBulletin{}, ids).Error
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		b.cancel(id)
+	}
+	return nil
+}
+
+// Receipts 公告已读回执列表
+func (b BulletinService) Receipts(id uint, info reqModel.PageInfo) (res resModel.PageResult, err error) {
+	if _, err = b.Get(id); err != nil {
+		return
+	}
+	limit, offset := info.Limit()
+	db := global.GvaDb.Table("sys_bulletin_reads r").
+		Joins("JOIN sys_users u ON u.id = r.user_id").
+		Where("r.bulletin_id = ?", id)
+	if info.Keyword != "" {
+		like := "%" + info.Keyword + "%"
+		db = db.Where("u.username LIKE ? OR u.nick_name LIKE ?", like, like)
+	}
+	var list []resModel.BulletinReceiptRes
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Select("r.user_id, u.username, u.nick_name, r.read_at").Order("r.read_at DESC").Limit(limit).Offset(offset).Scan(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// MyBulletins 当前用户所在用户群的生效公告
+func (b BulletinService) MyBulletins(userId uint, info reqModel.PageInfo) (res resModel.PageResult, err error) {
+	limit, offset := info.Limit()
+	db, err := b.visible(userId)
+	if err != nil {
+		return
+	}
+	if info.Keyword != "" {
+		db = db.Where("title LIKE ?", "%"+info.Keyword+"%")
+	}
+	var bulletins []dbModel.SysBulletin
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("level DESC, publish_at DESC").Limit(limit).Offset(offset).Find(&bulletins).Error; err != nil {
+		return
+	}
+	ids := make([]uint, 0, len(bulletins))
+	for _, bulletin := range bulletins {
+		ids = append(ids, bulletin.ID)
+	}
+	var reads []dbModel.SysBulletinRead
+	if len(ids) > 0 {
+		if err = global.GvaDb.Where("user_id = ? AND bulletin_id IN ?", userId, ids).Find(&reads).Error; err != nil {
+			return
+		}
+	}
+	readMap := make(map[uint]utils.LocalTime, len(reads))
+	for _, read := range reads {
+		readMap[read.BulletinId] = read.ReadAt
+	}
+	list := make([]resModel.BulletinRes, 0, len(bulletins))
+	for _, bulletin := range bulletins {
+		item := resModel.BulletinRes{SysBulletin: bulletin}
+		if readAt, ok := readMap[bulletin.ID]; ok {
+			item.Read, item.ReadAt = true, &readAt
+		}
+		list = append(list, item)
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// MarkRead 标记公告已读 重复标记忽略
+func (b BulletinService) MarkRead(userId, bulletinId uint) error {
+	db, err := b.visible(userId)
+	if err != nil {
+		return err
+	}
+	var count int64
+	if err = db.Where("id = ?", bulletinId).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ApiReturn.NoData
+	}
+	if err = global.GvaDb.Model(&dbModel.SysBulletinRead{}).Where("bulletin_id = ? AND user_id = ?", bulletinId, userId).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	return global.GvaDb.Create(&dbModel.SysBulletinRead{BulletinId: bulletinId, UserId: userId, ReadAt: utils.LocalTime(time.Now())}).Error
+}
+
+// UnreadCount 当前用户未读公告数
+func (b BulletinService) UnreadCount(userId uint) (count int64, err error) {
+	db, err := b.visible(userId)
+	if err != nil {
+		return
+	}
+	err = db.Where("id NOT IN (?)", global.GvaDb.Model(&dbModel.SysBulletinRead{}).Select("bulletin_id").Where("user_id = ?", userId)).
+		Count(&count).Error
+	return
+}
+
+// visible 用户可见的生效公告查询
+func (b BulletinService) visible(userId uint) (*gorm.DB, error) {
+	groupIds, err := ServiceGroupApp.UserGroupService.UserGroupIds(userId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	db := global.GvaDb.Model(&dbModel.SysBulletin{}).
+		Where("status = ? AND publish_at <= ? AND (expire_at IS NULL OR expire_at > ?)", dbModel.BulletinPublished, now, now)
+	if len(groupIds) == 0 {
+		return db.Where("1 = 0"), nil
+	}
+	return db.Where("id IN (?)", global.GvaDb.Table("sys_bulletin_groups").Select("sys_bulletin_id").Where("sys_user_group_id IN ?", groupIds)), nil
+}
+
+// checkGroups 校验面向人群均存在且可用
+func (b BulletinService) checkGroups(groupIds []uint) ([]dbModel.SysUserGroup, error) {
+	if len(groupIds) == 0 {
+		return nil, ApiReturn.NoGroupInfo
+	}
+	var groups []dbModel.SysUserGroup
+	if err := global.GvaDb.Find(&groups, uniqueIds(groupIds)).Error; err != nil {
+		return nil, err
+	}
+	if len(groups) != len(uniqueIds(groupIds)) {
+		return nil, ApiReturn.NoGroupInfo
+	}
+	for _, group := range groups {
+		if group.Status != dbModel.GroupEnable {
+			return nil, ApiReturn.GroupDisabled.WithData(group.Name)
+		}
+	}
+	return groups, nil
+}
+
+// schedule 按生效/过期时间设置推送定时器 已生效的公告在 pushNow 时立即推送
+func (b BulletinService) schedule(bulletin dbModel.SysBulletin, pushNow bool) {
+	b.cancel(bulletin.ID)
+	now := time.Now()
+	var timers []*time.Timer
+	if bulletin.PublishAt != nil && bulletin.PublishAt.ToTime().After(now) {
+		timers = append(timers, time.AfterFunc(bulletin.PublishAt.ToTime().Sub(now), func() { b.fire(bulletin.ID, EventBulletinNew) }))
+	} else if pushNow {
+		ServiceGroupApp.EventService.Emit(EventBulletinNew, b.event(bulletin))
+	}
+	if bulletin.ExpireAt != nil && bulletin.ExpireAt.ToTime().After(now) {
+		timers = append(timers, time.AfterFunc(bulletin.ExpireAt.ToTime().Sub(now), func() { b.fire(bulletin.ID, EventBulletinExpire) }))
+	}
+	if len(timers) > 0 {
+		bulletinTimers.Lock()
+		bulletinTimers.timers[bulletin.ID] = timers
+		bulletinTimers.Unlock()
+	}
+}
+
+// fire 定时器触发时重新查询 公告已撤回/删除则不推送
+func (b BulletinService) fire(id uint, eventName string) {
+	bulletin, err := b.Get(id)
+	if err != nil || bulletin.Status != dbModel.BulletinPublished {
+		return
+	}
+	if eventName == EventBulletinExpire {
+		bulletinTimers.Lock()
+		delete(bulletinTimers.timers, id)
+		bulletinTimers.Unlock()
+	}
+	global.GvaLog.Info("公告定时推送", zap.Uint("id", id), zap.String("event", eventName))
+	ServiceGroupApp.EventService.Emit(eventName, b.event(bulletin))
+}
+
+func (b BulletinService) cancel(id uint) {
+	bulletinTimers.Lock()
+	for _, timer := range bulletinTimers.timers[id] {
+		timer.Stop()
+	}
+	delete(bulletinTimers.timers, id)
+	bulletinTimers.Unlock()
+}
+
+func (b BulletinService) event(bulletin dbModel.SysBulletin) resModel.BulletinEvent {
+	groupIds := make([]uint, 0, len(bulletin.Groups))
+	for _, group := range bulletin.Groups {
+		groupIds = append(groupIds, group.ID)
+	}
+	return resModel.BulletinEvent{
+		ID:        bulletin.ID,
+		Title:     bulletin.Title,
+		Level:     bulletin.Level,
+		PublishAt: bulletin.PublishAt,
+		ExpireAt:  bulletin.ExpireAt,
+		GroupIds:  groupIds,
+	}
+}
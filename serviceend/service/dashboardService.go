@@ -0,0 +1,415 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	dashboardGridColumns = 12   // 栅格列数
+	dashboardMaxVersions = 50   // 每个仪表盘保留的历史版本数
+	widgetMaxRows        = 1000 // 组件数据行数上限
+)
+
+type DashboardService struct{}
+
+// dashboardSnapshot 版本快照内容
+type dashboardSnapshot struct {
+	Name    string                        `json:"name"`
+	Remark  string                        `json:"remark"`
+	Widgets []reqModel.DashboardWidgetReq `json:"widgets"`
+}
+
+// List 仪表盘列表 不含组件
+func (d DashboardService) List(req reqModel.PageInfo) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysDashboard{})
+	if req.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysDashboard
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 仪表盘详情 组件按布局从上到下、从左到右排列
+func (d DashboardService) Get(id uint) (dashboard dbModel.SysDashboard, err error) {
+	err = global.GvaDb.Preload("Widgets", func(db *gorm.DB) *gorm.DB {
+		return db.Order("y, x, id")
+	}).First(&dashboard, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dashboard, ApiReturn.NoDashboard
+	}
+	return
+}
+
+// Save 新增/修改仪表盘 组件整体提交, 携带 ID 的组件原地更新, 未提交的组件删除
+// 修改时版本号与当前版本不一致返回 DashboardVersionConflict, 保存成功后版本号加一并记录快照
+func (d DashboardService) Save(userId uint, req reqModel.DashboardReq) (dbModel.SysDashboard, error) {
+	var count int64
+	if err := global.GvaDb.Model(&dbModel.SysDashboard{}).Where("name = ? AND id <> ?", req.Name, req.ID).Count(&count).Error; err != nil {
+		return dbModel.SysDashboard{}, err
+	}
+	if count > 0 {
+		return dbModel.SysDashboard{}, ApiReturn.DashboardNameExisted
+	}
+	if err := d.check(req.Widgets); err != nil {
+		return dbModel.SysDashboard{}, err
+	}
+	var dashboard dbModel.SysDashboard
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if req.ID != 0 {
+			if err := tx.First(&dashboard, req.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ApiReturn.NoDashboard
+				}
+				return err
+			}
+			if dashboard.Version != req.Version {
+				return ApiReturn.DashboardVersionConflict
+			}
+		} else {
+			dashboard.CreatedBy = userId
+		}
+		dashboard.Name, dashboard.Remark = req.Name, req.Remark
+		dashboard.Version++
+		// 版本号作为条件 防止并发保存互相覆盖
+		if dashboard.ID != 0 {
+			res := tx.Model(&dashboard).Where("version = ?", req.Version).
+				Updates(map[string]interface{}{"name": dashboard.Name, "remark": dashboard.Remark, "version": dashboard.Version})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return ApiReturn.DashboardVersionConflict
+			}
+		} else if err := tx.Create(&dashboard).Error; err != nil {
+			return err
+		}
+		widgets, err := d.saveWidgets(tx, dashboard.ID, req.Widgets)
+		if err != nil {
+			return err
+		}
+		return d.saveVersion(tx, userId, dashboard, widgets, req.VersionRemark)
+	})
+	if err != nil {
+		return dashboard, err
+	}
+	if err = ServiceGroupApp.WidgetRefreshService.Sync(dashboard.ID); err != nil {
+		global.GvaLog.Warn("组件自动刷新同步失败", zap.Error(err))
+	}
+	return d.Get(dashboard.ID)
+}
+
+// Delete 删除仪表盘及其组件与历史版本
+func (d DashboardService) Delete(ids []uint) error {
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		// 物理删除 名称唯一索引包含已软删除的行
+		tx = tx.Unscoped()
+		if err := tx.Where("dashboard_id IN ?", ids).Delete(&dbModel.SysDashboardWidget{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("dashboard_id IN ?", ids).Delete(&dbModel.SysDashboardVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&dbModel.SysDashboard{}, ids).Error
+	})
+	if err != nil {
+		return err
+	}
+	if err = ServiceGroupApp.WidgetRefreshService.Sync(); err != nil {
+		global.GvaLog.Warn("组件自动刷新同步失败", zap.Error(err))
+	}
+	return nil
+}
+
+// Versions 仪表盘历史版本 按版本号倒序
+func (d DashboardService) Versions(id uint) (list []dbModel.SysDashboardVersion, err error) {
+	if _, err = d.Get(id); err != nil {
+		return
+	}
+	err = global.GvaDb.Where("dashboard_id = ?", id).Order("version DESC").Find(&list).Error
+	return
+}
+
+// Restore 恢复到历史版本 以快照内容保存为新版本
+func (d DashboardService) Restore(userId uint, req reqModel.DashboardRestoreReq) (dashboard dbModel.SysDashboard, err error) {
+	if dashboard, err = d.Get(req.ID); err != nil {
+		return
+	}
+	var version dbModel.SysDashboardVersion
+	err = global.GvaDb.Where("dashboard_id = ? AND version = ?", req.ID, req.Version).First(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dashboard, ApiReturn.NoDashboardVersion
+	}
+	if err != nil {
+		return
+	}
+	var snapshot dashboardSnapshot
+	if err = json.Unmarshal([]byte(version.Snapshot), &snapshot); err != nil {
+		return
+	}
+	return d.Save(userId, reqModel.DashboardReq{
+		ID:            dashboard.ID,
+		Name:          snapshot.Name,
+		Remark:        snapshot.Remark,
+		Version:       dashboard.Version,
+		VersionRemark: fmt.Sprintf("恢复自版本 %d", version.Version),
+		Widgets:       snapshot.Widgets,
+	})
+}
+
+// WidgetData 执行组件绑定的查询 并按组件类型整理结果
+func (d DashboardService) WidgetData(userId, widgetId uint) (data resModel.WidgetData, err error) {
+	var widget dbModel.SysDashboardWidget
+	err = global.GvaDb.First(&widget, widgetId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return data, ApiReturn.NoWidget
+	}
+	if err != nil {
+		return
+	}
+	runReq, opts, err := ServiceGroupApp.SavedQueryService.bind(widget.SavedQueryId, reqModel.SavedQueryExecReq{Params: widget.Params})
+	if err != nil {
+		return
+	}
+	// 多取一行用于判断是否截断
+	runReq.PageSize, opts.maxRows = widgetMaxRows+1, widgetMaxRows+1
+	page, err := ServiceGroupApp.QueryService.run(userId, runReq, opts)
+	if err != nil {
+		return
+	}
+	data = resModel.WidgetData{
+		WidgetId:  widget.ID,
+		Type:      widget.Type,
+		Unit:      widget.Options.Unit,
+		UpdatedAt: time.Now().Format(utils.TimeFormat),
+	}
+	rows := page.Rows
+	if len(rows) > widgetMaxRows {
+		rows, data.Truncated = rows[:widgetMaxRows], true
+	}
+	err = d.shape(&data, widget.Options, page.Columns, rows)
+	return
+}
+
+// shape 按组件类型整理查询结果
+func (d DashboardService) shape(data *resModel.WidgetData, opts dbModel.WidgetOptions, columns []resModel.QueryColumn, rows [][]interface{}) error {
+	index := make(map[string]int, len(columns))
+	for i, c := range columns {
+		index[c.Name] = i
+	}
+	column := func(name string) (int, error) {
+		i, ok := index[name]
+		if !ok {
+			return 0, ApiReturn.ErrParam.WithData(fmt.Sprintf("查询结果中不存在字段 %s", name))
+		}
+		return i, nil
+	}
+	pick := func(i int) []interface{} {
+		values := make([]interface{}, len(rows))
+		for r, row := range rows {
+			values[r] = row[i]
+		}
+		return values
+	}
+	switch data.Type {
+	case dbModel.WidgetLine, dbModel.WidgetBar:
+		x, err := column(opts.XField)
+		if err != nil {
+			return err
+		}
+		data.Categories = pick(x)
+		for _, field := range opts.YFields {
+			y, err := column(field)
+			if err != nil {
+				return err
+			}
+			data.Series = append(data.Series, resModel.WidgetSeries{Name: field, Data: pick(y)})
+		}
+	case dbModel.WidgetPie:
+		label, err := column(opts.LabelField)
+		if err != nil {
+			return err
+		}
+		value, err := column(opts.ValueField)
+		if err != nil {
+			return err
+		}
+		data.Categories = pick(label)
+		data.Series = []resModel.WidgetSeries{{Name: opts.ValueField, Data: pick(value)}}
+	case dbModel.WidgetStat:
+		value, err := column(opts.ValueField)
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			data.Value = rows[0][value]
+		}
+	default:
+		if len(opts.Columns) == 0 {
+			data.Columns, data.Rows = columns, rows
+			return nil
+		}
+		picked := make([]int, len(opts.Columns))
+		for i, name := range opts.Columns {
+			c, err := column(name)
+			if err != nil {
+				return err
+			}
+			picked[i] = c
+			data.Columns = append(data.Columns, columns[c])
+		}
+		data.Rows = make([][]interface{}, len(rows))
+		for r, row := range rows {
+			data.Rows[r] = make([]interface{}, len(picked))
+			for i, c := range picked {
+				data.Rows[r][i] = row[c]
+			}
+		}
+	}
+	return nil
+}
+
+// check 校验组件布局不越界、不重叠, 展示配置完整, 查询参数符合保存的查询的参数定义
+func (d DashboardService) check(widgets []reqModel.DashboardWidgetReq) error {
+	for i, w := range widgets {
+		if w.X+w.W > dashboardGridColumns {
+			return ApiReturn.ErrParam.WithData(fmt.Sprintf("组件 %s 超出栅格宽度(%d列)", w.Title, dashboardGridColumns))
+		}
+		for _, o := range widgets[:i] {
+			if w.X < o.X+o.W && o.X < w.X+w.W && w.Y < o.Y+o.H && o.Y < w.Y+w.H {
+				return ApiReturn.ErrParam.WithData(fmt.Sprintf("组件 %s 与 %s 位置重叠", w.Title, o.Title))
+			}
+		}
+		if err := d.checkOptions(w); err != nil {
+			return err
+		}
+		query, err := ServiceGroupApp.SavedQueryService.Get(w.SavedQueryId)
+		if err != nil {
+			return err
+		}
+		if _, err = ServiceGroupApp.SavedQueryService.values(query.Params, w.Params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d DashboardService) checkOptions(w reqModel.DashboardWidgetReq) error {
+	var missing string
+	switch w.Type {
+	case dbModel.WidgetLine, dbModel.WidgetBar:
+		if w.Options.XField == "" {
+			missing = "横轴字段"
+		} else if len(w.Options.YFields) == 0 {
+			missing = "数值字段"
+		}
+	case dbModel.WidgetPie:
+		if w.Options.LabelField == "" {
+			missing = "名称字段"
+		} else if w.Options.ValueField == "" {
+			missing = "数值字段"
+		}
+	case dbModel.WidgetStat:
+		if w.Options.ValueField == "" {
+			missing = "数值字段"
+		}
+	}
+	if missing != "" {
+		return ApiReturn.ErrParam.WithData(fmt.Sprintf("组件 %s 未配置%s", w.Title, missing))
+	}
+	return nil
+}
+
+// saveWidgets 保存组件 不属于该仪表盘的组件ID按新增处理
+func (d DashboardService) saveWidgets(tx *gorm.DB, dashboardId uint, reqs []reqModel.DashboardWidgetReq) ([]dbModel.SysDashboardWidget, error) {
+	var existing []dbModel.SysDashboardWidget
+	if err := tx.Where("dashboard_id = ?", dashboardId).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byId := make(map[uint]dbModel.SysDashboardWidget, len(existing))
+	for _, w := range existing {
+		byId[w.ID] = w
+	}
+	widgets := make([]dbModel.SysDashboardWidget, len(reqs))
+	keep := make([]uint, 0, len(reqs))
+	for i, r := range reqs {
+		widget, ok := byId[r.ID]
+		if !ok {
+			widget = dbModel.SysDashboardWidget{DashboardId: dashboardId}
+		}
+		delete(byId, r.ID)
+		widget.Title, widget.Type, widget.SavedQueryId = r.Title, r.Type, r.SavedQueryId
+		widget.Params, widget.Options = r.Params, r.Options
+		widget.X, widget.Y, widget.W, widget.H = r.X, r.Y, r.W, r.H
+		widget.RefreshInterval = r.RefreshInterval
+		if err := tx.Save(&widget).Error; err != nil {
+			return nil, err
+		}
+		widgets[i] = widget
+		keep = append(keep, widget.ID)
+	}
+	// 移除的组件物理删除 与删除仪表盘一致
+	db := tx.Unscoped().Where("dashboard_id = ?", dashboardId)
+	if len(keep) > 0 {
+		db = db.Where("id NOT IN ?", keep)
+	}
+	if err := db.Delete(&dbModel.SysDashboardWidget{}).Error; err != nil {
+		return nil, err
+	}
+	return widgets, nil
+}
+
+// saveVersion 记录版本快照 超出保留数量的旧版本删除
+func (d DashboardService) saveVersion(tx *gorm.DB, userId uint, dashboard dbModel.SysDashboard, widgets []dbModel.SysDashboardWidget, remark string) error {
+	snapshot := dashboardSnapshot{Name: dashboard.Name, Remark: dashboard.Remark, Widgets: make([]reqModel.DashboardWidgetReq, len(widgets))}
+	for i, w := range widgets {
+		snapshot.Widgets[i] = reqModel.DashboardWidgetReq{
+			ID:              w.ID,
+			Title:           w.Title,
+			Type:            w.Type,
+			SavedQueryId:    w.SavedQueryId,
+			Params:          w.Params,
+			Options:         w.Options,
+			X:               w.X,
+			Y:               w.Y,
+			W:               w.W,
+			H:               w.H,
+			RefreshInterval: w.RefreshInterval,
+		}
+	}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	version := dbModel.SysDashboardVersion{
+		DashboardId: dashboard.ID,
+		Version:     dashboard.Version,
+		Snapshot:    string(content),
+		Remark:      remark,
+		CreatedBy:   userId,
+	}
+	if err = tx.Create(&version).Error; err != nil {
+		return err
+	}
+	return tx.Where("dashboard_id = ? AND version <= ?", dashboard.ID, dashboard.Version-dashboardMaxVersions).
+		Delete(&dbModel.SysDashboardVersion{}).Error
+}
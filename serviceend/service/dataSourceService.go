@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/datasource"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// dataSourceTestTimeout 连接测试超时时间
+const dataSourceTestTimeout = 10 * time.Second
+
+type DataSourceService struct{}
+
+// dataSourcePool 数据源连接池缓存 数据源修改后按更新时间重建
+var dataSourcePool = struct {
+	sync.Mutex
+	dbs map[uint]*pooledDataSource
+}{dbs: map[uint]*pooledDataSource{}}
+
+type pooledDataSource struct {
+	db      *sql.DB
+	version time.Time
+}
+
+// Types 支持的数据源类型
+func (d DataSourceService) Types() []string {
+	return datasource.Types()
+}
+
+// List 数据源列表
+func (d DataSourceService) List(req reqModel.DataSourceListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysDataSource{})
+	if req.Type != "" {
+		db = db.Where("type = ?", req.Type)
+	}
+	if req.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysDataSource
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	for i := range list {
+		list[i].HasPassword = list[i].Password != ""
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 数据源详情 不返回密码
+func (d DataSourceService) Get(id uint) (source dbModel.SysDataSource, err error) {
+	err = global.GvaDb.First(&source, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return source, ApiReturn.NoDataSource
+	}
+	source.HasPassword = source.Password != ""
+	return
+}
+
+// Save 新增/修改数据源 修改时密码为空保留原密码
+func (d DataSourceService) Save(userId uint, req reqModel.DataSourceReq) (source dbModel.SysDataSource, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysDataSource{}).Where("name = ? AND id <> ?", req.Name, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return source, ApiReturn.DataSourceNameExisted
+	}
+	if err = d.validate(req); err != nil {
+		return
+	}
+	if req.ID != 0 {
+		if source, err = d.Get(req.ID); err != nil {
+			return
+		}
+	} else {
+		source.CreatedBy = userId
+	}
+	if req.Password != "" {
+		if source.Password, err = utils.AesEncrypt(req.Password, global.GvaConfig.System.SecretKey); err != nil {
+			return
+		}
+	}
+	if req.Status == 0 {
+		req.Status = dbModel.DataSourceEnable
+	}
+	source.Name, source.Type, source.Host, source.Port = req.Name, req.Type, req.Host, req.Port
+	source.Database, source.Username, source.Params, source.Path = req.Database, req.Username, req.Params, req.Path
+	source.Status, source.Writable, source.Remark = req.Status, req.Writable, req.Remark
+	if err = global.GvaDb.Save(&source).Error; err != nil {
+		return
+	}
+	d.closePool(source.ID)
+	source.HasPassword = source.Password != ""
+	return
+}
+
+// Delete 删除数据源并关闭连接池
+func (d DataSourceService) Delete(ids []uint) error {
+	// 物理删除 名称唯一索引包含已软删除的行
+	if err := global.GvaDb.Unscoped().Delete(&dbModel.SysDataSource{}, ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		d.closePool(id)
+	}
+	return nil
+}
+
+// Test 测试连接参数 修改已有数据源且未填写密码时使用已保存的密码
+func (d DataSourceService) Test(ctx context.Context, req reqModel.DataSourceReq) (res resModel.DataSourceTestRes, err error) {
+	if err = d.validate(req); err != nil {
+		return
+	}
+	cfg := d.toConfig(req)
+	if req.ID != 0 && req.Password == "" {
+		source, err := d.Get(req.ID)
+		if err != nil {
+			return res, err
+		}
+		if cfg.Password, err = utils.AesDecrypt(source.Password, global.GvaConfig.System.SecretKey); err != nil {
+			return res, err
+		}
+	}
+	return d.test(ctx, cfg)
+}
+
+// TestById 测试已保存的数据源
+func (d DataSourceService) TestById(ctx context.Context, id uint) (res resModel.DataSourceTestRes, err error) {
+	source, err := d.Get(id)
+	if err != nil {
+		return
+	}
+	cfg, err := d.config(source)
+	if err != nil {
+		return
+	}
+	return d.test(ctx, cfg)
+}
+
+// DB 获取数据源连接池 同一数据源复用连接池
+func (d DataSourceService) DB(id uint) (*sql.DB, dbModel.SysDataSource, error) {
+	source, err := d.Get(id)
+	if err != nil {
+		return nil, source, err
+	}
+	if source.Status != dbModel.DataSourceEnable {
+		return nil, source, ApiReturn.DataSourceConnFailed.WithData("数据源已禁用")
+	}
+	version := time.Time(source.UpdatedAt)
+	dataSourcePool.Lock()
+	defer dataSourcePool.Unlock()
+	if pooled, ok := dataSourcePool.dbs[id]; ok {
+		if pooled.version.Equal(version) {
+			return pooled.db, source, nil
+		}
+		_ = pooled.db.Close()
+		delete(dataSourcePool.dbs, id)
+	}
+	cfg, err := d.config(source)
+	if err != nil {
+		return nil, source, err
+	}
+	db, err := datasource.Open(cfg)
+	if err != nil {
+		return nil, source, ApiReturn.DataSourceConnFailed.WithData(err.Error())
+	}
+	dataSourcePool.dbs[id] = &pooledDataSource{db: db, version: version}
+	return db, source, nil
+}
+
+// CloseAll 关闭全部数据源连接池 程序退出时调用
+func (d DataSourceService) CloseAll() {
+	dataSourcePool.Lock()
+	defer dataSourcePool.Unlock()
+	for id, pooled := range dataSourcePool.dbs {
+		if err := pooled.db.Close(); err != nil {
+			global.GvaLog.Warn("数据源连接池关闭失败", zap.Uint("id", id), zap.Error(err))
+		}
+		delete(dataSourcePool.dbs, id)
+	}
+}
+
+func (d DataSourceService) closePool(id uint) {
+	dataSourcePool.Lock()
+	defer dataSourcePool.Unlock()
+	if pooled, ok := dataSourcePool.dbs[id]; ok {
+		_ = pooled.db.Close()
+		delete(dataSourcePool.dbs, id)
+	}
+}
+
+func (d DataSourceService) test(ctx context.Context, cfg datasource.Config) (res resModel.DataSourceTestRes, err error) {
+	ctx, cancel := context.WithTimeout(ctx, dataSourceTestTimeout)
+	defer cancel()
+	latency, err := datasource.Test(ctx, cfg)
+	if err != nil {
+		global.GvaLog.Info("数据源连接测试失败", zap.String("type", cfg.Type), zap.Error(err))
+		return res, ApiReturn.DataSourceConnFailed.WithData(err.Error())
+	}
+	res.Latency = latency.Milliseconds()
+	return
+}
+
+// validate 按数据源类型校验连接参数
+func (d DataSourceService) validate(req reqModel.DataSourceReq) error {
+	driver, err := datasource.Get(req.Type)
+	if err != nil {
+		return ApiReturn.ErrParam.WithData(err.Error())
+	}
+	if err = driver.Validate(d.toConfig(req)); err != nil {
+		return ApiReturn.ErrParam.WithData(err.Error())
+	}
+	return nil
+}
+
+func (d DataSourceService) toConfig(req reqModel.DataSourceReq) datasource.Config {
+	return datasource.Config{
+		Type:     req.Type,
+		Host:     req.Host,
+		Port:     req.Port,
+		Database: req.Database,
+		Username: req.Username,
+		Password: req.Password,
+		Params:   req.Params,
+		Path:     req.Path,
+	}
+}
+
+// config 数据源记录转换为连接参数 解密密码
+func (d DataSourceService) config(source dbModel.SysDataSource) (datasource.Config, error) {
+	password, err := utils.AesDecrypt(source.Password, global.GvaConfig.System.SecretKey)
+	if err != nil {
+		return datasource.Config{}, err
+	}
+	return datasource.Config{
+		Type:     source.Type,
+		Host:     source.Host,
+		Port:     source.Port,
+		Database: source.Database,
+		Username: source.Username,
+		Password: password,
+		Params:   source.Params,
+		Path:     source.Path,
+		ReadOnly: !source.Writable,
+	}, nil
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentService struct{}
+
+// Tree 部门树 按调用者数据权限裁剪 仅返回其可见的子树
+func (d DepartmentService) Tree(userId uint) ([]dbModel.SysDepartment, error) {
+	var list []dbModel.SysDepartment
+	if err := global.GvaDb.Scopes(d.DepartmentScope(userId, "id")).Order("sort, id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	// 可见部门中上级不可见的作为根节点
+	visible := make(map[uint]struct{}, len(list))
+	for _, dept := range list {
+		visible[dept.ID] = struct{}{}
+	}
+	tree := make([]dbModel.SysDepartment, 0)
+	for _, dept := range list {
+		if _, ok := visible[dept.ParentId]; !ok {
+			dept.Children = buildDepartmentTree(list, dept.ID)
+			tree = append(tree, dept)
+		}
+	}
+	return tree, nil
+}
+
+// Get 部门详情
+func (d DepartmentService) Get(id uint) (dept dbModel.SysDepartment, err error) {
+	err = global.GvaDb.First(&dept, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dept, ApiReturn.NoData
+	}
+	return
+}
+
+// Save 新增/修改部门 修改时不变更上级部门,调整层级请使用 Move
+func (d DepartmentService) Save(req reqModel.DepartmentReq) (dept dbModel.SysDepartment, err error) {
+	if req.ID != 0 {
+		if dept, err = d.Get(req.ID); err != nil {
+			return
+		}
+		req.ParentId = dept.ParentId
+	}
+	if err = d.checkName(req.ParentId, req.Name, req.ID); err != nil {
+		return
+	}
+	// 未传状态时 新增默认启用, 修改保留原值
+	if req.ID == 0 {
+		dept.Status = dbModel.DepartmentEnable
+	}
+	if req.Status != 0 {
+		dept.Status = req.Status
+	}
+	dept.Name, dept.Leader, dept.Phone, dept.Sort = req.Name, req.Leader, req.Phone, req.Sort
+	if req.ID != 0 {
+		err = global.GvaDb.Save(&dept).Error
+		return
+	}
+	parentPath := "/"
+	if req.ParentId != 0 {
+		parent, err := d.Get(req.ParentId)
+		if err != nil {
+			return dept, ApiReturn.ErrParam.WithData("上级部门不存在")
+		}
+		parentPath = parent.TreePath
+	}
+	dept.ParentId = req.ParentId
+	err = global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&dept).Error; err != nil {
+			return err
+		}
+		dept.TreePath = fmt.Sprintf("%s%d/", parentPath, dept.ID)
+		return tx.Model(&dept).Update("tree_path", dept.TreePath).Error
+	})
+	return
+}
+
+// Move 调整上级部门 子树层级路径随之更新 不能移动到自身子树下
+func (d DepartmentService) Move(req reqModel.DepartmentMoveReq) error {
+	dept, err := d.Get(req.ID)
+	if err != nil {
+		return err
+	}
+	if dept.ParentId == req.ParentId {
+		return nil
+	}
+	newParentPath := "/"
+	if req.ParentId != 0 {
+		parent, err := d.Get(req.ParentId)
+		if err != nil {
+			return ApiReturn.ErrParam.WithData("上级部门不存在")
+		}
+		if strings.HasPrefix(parent.TreePath, dept.TreePath) {
+			return ApiReturn.ErrParam.WithData("不能移动到自身或下级部门下")
+		}
+		newParentPath = parent.TreePath
+	}
+	if err = d.checkName(req.ParentId, dept.Name, dept.ID); err != nil {
+		return err
+	}
+	oldPath := dept.TreePath
+	newPath := fmt.Sprintf("%s%d/", newParentPath, dept.ID)
+	return global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&dept).Update("parent_id", req.ParentId).Error; err != nil {
+			return err
+		}
+		var subtree []dbModel.SysDepartment
+		if err := tx.Where("tree_path LIKE ?", oldPath+"%").Find(&subtree).Error; err != nil {
+			return err
+		}
+		for _, item := range subtree {
+			path := newPath + strings.TrimPrefix(item.TreePath, oldPath)
+			if err := tx.Model(&item).Update("tree_path", path).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete 删除部门 存在下级部门或成员时不允许删除
+func (d DepartmentService) Delete(id uint) error {
+	if _, err := d.Get(id); err != nil {
+		return err
+	}
+	var count int64
+	if err := global.GvaDb.Model(&dbModel.SysDepartment{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ApiReturn.ErrParam.WithData("请先删除下级部门")
+	}
+	if err := global.GvaDb.Model(&dbModel.SysUser{}).Where("department_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ApiReturn.ErrParam.WithData("部门下仍有成员")
+	}
+	return global.GvaDb.Delete(&dbModel.SysDepartment{}, id).Error
+}
+
+// AssignUser 为用户分配部门 已有归属部门的用户需先移出
+func (d DepartmentService) AssignUser(req reqModel.DepartmentUserReq) error {
+	user, err := ServiceGroupApp.UserService.GetUserInfo(req.UserId)
+	if err != nil {
+		return err
+	}
+	if user.DepartmentId == req.DepartmentId {
+		return nil
+	}
+	if user.DepartmentId != 0 {
+		return ApiReturn.ExistingDepartment
+	}
+	if _, err = d.Get(req.DepartmentId); err != nil {
+		return err
+	}
+	return global.GvaDb.Model(&user).Update("department_id", req.DepartmentId).Error
+}
+
+// RemoveUser 将用户移出所属部门
+func (d DepartmentService) RemoveUser(userId uint) error {
+	user, err := ServiceGroupApp.UserService.GetUserInfo(userId)
+	if err != nil {
+		return err
+	}
+	return global.GvaDb.Model(&user).Update("department_id", 0).Error
+}
+
+// Users 部门成员 包含下级部门 按调用者数据权限过滤
+func (d DepartmentService) Users(userId uint, req reqModel.DepartmentUsersReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysUser{}).Scopes(d.DataScope(userId, "department_id", "id"))
+	if req.DepartmentId != 0 {
+		dept, err := d.Get(req.DepartmentId)
+		if err != nil {
+			return res, err
+		}
+		db = db.Where("department_id IN (?)", global.GvaDb.Model(&dbModel.SysDepartment{}).Select("id").Where("tree_path LIKE ?", dept.TreePath+"%"))
+	}
+	if req.Keyword != "" {
+		like := "%" + req.Keyword + "%"
+		db = db.Where("username LIKE ? OR nick_name LIKE ?", like, like)
+	}
+	var list []dbModel.SysUser
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Order("id").Limit(limit).Offset(offset).Find(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// DataScope 数据权限过滤 deptColumn 为数据所属部门字段, userColumn 为数据所属用户字段(仅本人范围使用)
+// 用于各列表接口: db.Scopes(departmentService.DataScope(userId, "department_id", "created_by"))
+func (d DepartmentService) DataScope(userId uint, deptColumn, userColumn string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userId == 0 { // 开发环境跳过鉴权时无调用者
+			return db
+		}
+		scope := ServiceGroupApp.PermissionService.DataScope(userId)
+		if scope == dbModel.DataScopeAll {
+			return db
+		}
+		if scope == dbModel.DataScopeSelf {
+			return db.Where(userColumn+" = ?", userId)
+		}
+		return d.DepartmentScope(userId, deptColumn)(db)
+	}
+}
+
+// DepartmentScope 按调用者部门过滤 本部门及以下/仅本部门 未分配部门的用户看不到任何部门数据
+func (d DepartmentService) DepartmentScope(userId uint, deptColumn string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userId == 0 {
+			return db
+		}
+		scope := ServiceGroupApp.PermissionService.DataScope(userId)
+		if scope == dbModel.DataScopeAll {
+			return db
+		}
+		var user dbModel.SysUser
+		if err := global.GvaDb.Select("id", "department_id").First(&user, userId).Error; err != nil || user.DepartmentId == 0 {
+			return db.Where("1 = 0")
+		}
+		if scope == dbModel.DataScopeDeptTree {
+			var dept dbModel.SysDepartment
+			if err := global.GvaDb.Select("id", "tree_path").First(&dept, user.DepartmentId).Error; err != nil {
+				return db.Where("1 = 0")
+			}
+			return db.Where(deptColumn+" IN (?)", global.GvaDb.Model(&dbModel.SysDepartment{}).Select("id").Where("tree_path LIKE ?", dept.TreePath+"%"))
+		}
+		return db.Where(deptColumn+" = ?", user.DepartmentId)
+	}
+}
+
+// checkName 同一上级部门下名称不可重复
+func (d DepartmentService) checkName(parentId uint, name string, excludeId uint) error {
+	var count int64
+	if err := global.GvaDb.Model(&dbModel.SysDepartment{}).Where("parent_id = ? AND name = ? AND id <> ?", parentId, name, excludeId).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ApiReturn.ErrParam.WithData("同级部门名称已存在")
+	}
+	return nil
+}
+
+func buildDepartmentTree(list []dbModel.SysDepartment, parentId uint) []dbModel.SysDepartment {
+	tree := make([]dbModel.SysDepartment, 0)
+	for _, dept := range list {
+		if dept.ParentId == parentId {
+			dept.Children = buildDepartmentTree(list, dept.ID)
+			tree = append(tree, dept)
+		}
+	}
+	return tree
+}
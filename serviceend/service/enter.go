@@ -2,7 +2,30 @@ package service
 
 // 所以得service 都要在这里注册
 type ServiceGroup struct {
-	HelloService HelloService
+	HelloService         HelloService
+	JwtService           JwtService
+	UserService          UserService
+	SessionService       SessionService
+	PermissionService    PermissionService
+	RoleService          RoleService
+	MenuService          MenuService
+	DepartmentService    DepartmentService
+	EventService         EventService
+	UserGroupService     UserGroupService
+	BulletinService      BulletinService
+	FileService          FileService
+	VerificationService  VerificationService
+	DataSourceService    DataSourceService
+	QueryService         QueryService
+	SavedQueryService    SavedQueryService
+	DashboardService     DashboardService
+	WidgetRefreshService WidgetRefreshService
+	JobService           JobService
+	AlertService         AlertService
+	NotifyService        NotifyService
+	ExportService        ExportService
+	ImportService        ImportService
+	LogService           LogService
 }
 
 var ServiceGroupApp = new(ServiceGroup)
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"sync/atomic"
+)
+
+// EmitFunc 向桌面窗口推送事件 由 App.Startup 使用 wails 上下文注册
+type EmitFunc func(eventName string, optionalData ...interface{})
+
+type EventService struct{}
+
+var eventEmitter atomic.Pointer[EmitFunc]
+
+// SetEmitter 注册事件推送方法 仅以 http 服务运行(无桌面窗口)时不注册,推送静默忽略
+func (e EventService) SetEmitter(fn EmitFunc) {
+	eventEmitter.Store(&fn)
+}
+
+// Emit 推送事件到前端
+func (e EventService) Emit(eventName string, optionalData ...interface{}) {
+	if fn := eventEmitter.Load(); fn != nil && *fn != nil {
+		(*fn)(eventName, optionalData...)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils/datasource"
+	"dataPanel/serviceend/utils/export"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExportService struct{}
+
+// Export 一次导出 Write 时执行查询并按批写出, 不在内存中保留全部结果
+type Export struct {
+	FileName    string // 含扩展名的文件名
+	ContentType string
+	format      string
+	userId      uint
+	req         reqModel.QueryRunReq
+	opts        queryOptions
+}
+
+// Prepare 解析导出来源并校验 只允许导出查询语句
+func (e ExportService) Prepare(userId uint, req reqModel.ExportReq) (*Export, error) {
+	sources := 0
+	for _, set := range []bool{req.WidgetId != 0, req.SavedQueryId != 0, req.DataSourceId != 0 || req.Sql != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, ApiReturn.ExportSourceInvalid
+	}
+	var (
+		runReq reqModel.QueryRunReq
+		opts   queryOptions
+		name   string
+		err    error
+	)
+	switch {
+	case req.WidgetId != 0:
+		var widget dbModel.SysDashboardWidget
+		err = global.GvaDb.First(&widget, req.WidgetId).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ApiReturn.NoWidget
+		}
+		if err != nil {
+			return nil, err
+		}
+		runReq, opts, err = ServiceGroupApp.SavedQueryService.bind(widget.SavedQueryId, reqModel.SavedQueryExecReq{Params: widget.Params})
+		name = widget.Title
+	case req.SavedQueryId != 0:
+		var query dbModel.SysSavedQuery
+		if query, err = ServiceGroupApp.SavedQueryService.Get(req.SavedQueryId); err != nil {
+			return nil, err
+		}
+		runReq, opts, err = ServiceGroupApp.SavedQueryService.bind(query.ID, reqModel.SavedQueryExecReq{Params: req.Params})
+		name = query.Name
+	default:
+		if req.DataSourceId == 0 || req.Sql == "" {
+			return nil, ApiReturn.ExportSourceInvalid
+		}
+		runReq = reqModel.QueryRunReq{DataSourceId: req.DataSourceId, Sql: req.Sql}
+		name = "export"
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, source, err := ServiceGroupApp.DataSourceService.DB(runReq.DataSourceId)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := datasource.ParseStatement(source.Type, runReq.Sql)
+	if err != nil {
+		return nil, ApiReturn.ErrParam.WithData(err.Error())
+	}
+	if !stmt.ReadOnly {
+		return nil, ApiReturn.ExportNotQuery
+	}
+	if req.FileName != "" {
+		name = req.FileName
+	} else {
+		name += "_" + time.Now().Format("20060102150405")
+	}
+	return &Export{
+		FileName:    e.fileName(name) + "." + req.Format,
+		ContentType: export.ContentType(req.Format),
+		format:      req.Format,
+		userId:      userId,
+		req:         runReq,
+		opts:        opts,
+	}, nil
+}
+
+// Write 执行查询并写出文件内容 返回导出行数
+// 查询失败返回查询的业务错误, 写出失败返回 ExportFailed
+func (x *Export) Write(w io.Writer) (rows int64, err error) {
+	writer, err := export.NewWriter(x.format, w)
+	if err != nil {
+		return 0, ApiReturn.ExportFailed.WithData(err.Error())
+	}
+	header := false
+	_, err = ServiceGroupApp.QueryService.export(x.userId, x.req, x.opts, func(columns []resModel.QueryColumn, list [][]interface{}) error {
+		if !header {
+			names := make([]string, len(columns))
+			for i, c := range columns {
+				names[i] = c.Name
+			}
+			if err := writer.WriteHeader(names); err != nil {
+				return ApiReturn.ExportFailed.WithData(err.Error())
+			}
+			header = true
+		}
+		for _, row := range list {
+			if err := writer.WriteRow(row); err != nil {
+				return ApiReturn.ExportFailed.WithData(err.Error())
+			}
+			rows++
+		}
+		return nil
+	})
+	if err != nil {
+		writer.Abort()
+		return
+	}
+	if err = writer.Close(); err != nil {
+		return rows, ApiReturn.ExportFailed.WithData(err.Error())
+	}
+	return
+}
+
+// fileName 去掉文件名中不能使用的字符
+func (e ExportService) fileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		return "export"
+	}
+	return name
+}
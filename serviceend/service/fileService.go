@@ -0,0 +1,403 @@
+package service
+
+import (
+	"crypto/sha256"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/upload"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	fileKeyPrefix  = "files"  // 文件存储路径前缀
+	chunkKeyPrefix = "chunks" // 分片存储路径前缀
+	fileMaxChunks  = 10000    // 单个文件最大分片数
+)
+
+type FileService struct{}
+
+// CleanChunks 清理超过 file.chunk-expire 仍未合并的分片上传任务
+func (f FileService) CleanChunks() error {
+	expire := 24 * time.Hour
+	if cfg := global.GvaConfig.File; cfg != nil && cfg.ChunkExpire != "" {
+		d, err := utils.ParseDuration(cfg.ChunkExpire)
+		if err != nil {
+			return err
+		}
+		expire = d
+	}
+	var list []dbModel.SysFileUpload
+	if err := global.GvaDb.Where("created_at < ?", time.Now().Add(-expire)).Find(&list).Error; err != nil {
+		return err
+	}
+	for _, item := range list {
+		if err := f.removeUpload(item.UploadId); err != nil {
+			global.GvaLog.Warn("分片上传任务清理失败", zap.String("uploadId", item.UploadId), zap.Error(err))
+		}
+	}
+	if len(list) > 0 {
+		global.GvaLog.Info("过期分片上传任务清理完成", zap.Int("count", len(list)))
+	}
+	return nil
+}
+
+// Upload 普通上传 写入存储的同时计算 SHA-256
+func (f FileService) Upload(userId uint, header *multipart.FileHeader) (file dbModel.SysFile, err error) {
+	if header == nil {
+		return file, ApiReturn.NoFiles
+	}
+	if err = f.checkSize(header.Size); err != nil {
+		return
+	}
+	src, err := header.Open()
+	if err != nil {
+		return file, ApiReturn.NoFiles
+	}
+	defer src.Close()
+	mimeType := header.Header.Get("Content-Type")
+	return f.save(userId, header.Filename, mimeType, src)
+}
+
+// InitChunk 创建分片上传任务 同一用户上传相同文件时返回未完成的任务用于断点续传
+func (f FileService) InitChunk(userId uint, req reqModel.FileChunkInitReq) (res resModel.FileChunkRes, err error) {
+	if err = f.checkSize(req.Size); err != nil {
+		return
+	}
+	chunkTotal := (req.Size + req.ChunkSize - 1) / req.ChunkSize
+	if chunkTotal > fileMaxChunks {
+		return res, ApiReturn.ErrParam.WithData(fmt.Sprintf("分片数量不能超过%d,请调大分片大小", fileMaxChunks))
+	}
+	req.Sha256 = strings.ToLower(req.Sha256)
+	if req.Sha256 != "" {
+		var exist dbModel.SysFileUpload
+		err = global.GvaDb.Where("created_by = ? AND sha256 = ? AND size = ? AND chunk_size = ?", userId, req.Sha256, req.Size, req.ChunkSize).
+			First(&exist).Error
+		if err == nil {
+			return f.chunkStatus(exist)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return
+		}
+	}
+	uploadTask := dbModel.SysFileUpload{
+		UploadId:   uuid.NewString(),
+		FileName:   filepath.Base(req.FileName),
+		Size:       req.Size,
+		Sha256:     req.Sha256,
+		ChunkSize:  req.ChunkSize,
+		ChunkTotal: int(chunkTotal),
+		CreatedBy:  userId,
+	}
+	if err = global.GvaDb.Create(&uploadTask).Error; err != nil {
+		return
+	}
+	return f.chunkStatus(uploadTask)
+}
+
+// UploadChunk 上传单个分片 重复上传的分片覆盖之前的内容 chunkSha256 不为空时校验分片内容
+func (f FileService) UploadChunk(userId uint, uploadId string, index int, header *multipart.FileHeader, chunkSha256 string) error {
+	uploadTask, err := f.getUpload(userId, uploadId)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= uploadTask.ChunkTotal {
+		return ApiReturn.ErrParam.WithData("分片序号超出范围")
+	}
+	if header == nil {
+		return ApiReturn.NoFiles
+	}
+	expectSize := uploadTask.ChunkSize
+	if index == uploadTask.ChunkTotal-1 {
+		expectSize = uploadTask.Size - uploadTask.ChunkSize*int64(uploadTask.ChunkTotal-1)
+	}
+	if header.Size != expectSize {
+		return ApiReturn.ErrParam.WithData(fmt.Sprintf("分片大小不匹配,应为%d字节", expectSize))
+	}
+	src, err := header.Open()
+	if err != nil {
+		return ApiReturn.NoFiles
+	}
+	defer src.Close()
+	hash := sha256.New()
+	key := f.chunkKey(uploadId, index)
+	storage, err := upload.NewStorage()
+	if err != nil {
+		return err
+	}
+	size, err := storage.Put(key, io.TeeReader(src, hash))
+	if err != nil {
+		global.GvaLog.Error("分片写入失败", zap.String("uploadId", uploadId), zap.Int("index", index), zap.Error(err))
+		return ApiReturn.UploadFailed
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if chunkSha256 != "" && !strings.EqualFold(chunkSha256, sum) {
+		_ = storage.Delete(key)
+		return ApiReturn.UploadFailed.WithData("分片校验失败")
+	}
+	chunk := dbModel.SysFileChunk{UploadId: uploadId, ChunkIndex: index, Size: size, Sha256: sum}
+	return global.GvaDb.Where("upload_id = ? AND chunk_index = ?", uploadId, index).
+		Assign(map[string]interface{}{"size": size, "sha256": sum}).FirstOrCreate(&chunk).Error
+}
+
+// ChunkStatus 分片上传任务状态
+func (f FileService) ChunkStatus(userId uint, uploadId string) (resModel.FileChunkRes, error) {
+	uploadTask, err := f.getUpload(userId, uploadId)
+	if err != nil {
+		return resModel.FileChunkRes{}, err
+	}
+	return f.chunkStatus(uploadTask)
+}
+
+// MergeChunk 合并分片生成文件记录 提供了文件校验值时校验合并结果
+func (f FileService) MergeChunk(userId uint, uploadId string) (file dbModel.SysFile, err error) {
+	uploadTask, err := f.getUpload(userId, uploadId)
+	if err != nil {
+		return
+	}
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysFileChunk{}).Where("upload_id = ?", uploadId).Count(&count).Error; err != nil {
+		return
+	}
+	if int(count) != uploadTask.ChunkTotal {
+		return file, ApiReturn.ErrParam.WithData(fmt.Sprintf("分片未上传完成(%d/%d)", count, uploadTask.ChunkTotal))
+	}
+	storage, err := upload.NewStorage()
+	if err != nil {
+		return
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < uploadTask.ChunkTotal; i++ {
+			chunk, err := storage.Open(f.chunkKey(uploadId, i))
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			_, err = io.Copy(pw, chunk)
+			_ = chunk.Close()
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		_ = pw.Close()
+	}()
+	file, err = f.save(userId, uploadTask.FileName, "", pr)
+	_ = pr.Close()
+	if err != nil {
+		return
+	}
+	if file.Size != uploadTask.Size || (uploadTask.Sha256 != "" && file.Sha256 != uploadTask.Sha256) {
+		global.GvaLog.Warn("分片合并校验失败", zap.String("uploadId", uploadId), zap.String("expected", uploadTask.Sha256), zap.String("actual", file.Sha256))
+		_ = global.GvaDb.Unscoped().Delete(&file).Error
+		_ = storage.Delete(file.StorageKey)
+		return dbModel.SysFile{}, ApiReturn.UploadFailed.WithData("文件校验失败,请重新上传")
+	}
+	if err = f.removeUpload(uploadId); err != nil {
+		global.GvaLog.Warn("分片清理失败", zap.String("uploadId", uploadId), zap.Error(err))
+	}
+	return file, nil
+}
+
+// List 文件列表
+func (f FileService) List(req reqModel.FileListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysFile{})
+	if req.Status != 0 {
+		db = db.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysFile
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 文件记录 已删除的记录返回 FileDisabled
+func (f FileService) Get(id uint) (file dbModel.SysFile, err error) {
+	err = global.GvaDb.Unscoped().First(&file, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return file, ApiReturn.NoRecordFiles
+	}
+	if err == nil && file.DeletedAt.Valid {
+		return file, ApiReturn.FileDisabled
+	}
+	return
+}
+
+// SetStatus 启用/禁用文件 禁用后不可下载
+func (f FileService) SetStatus(id uint, status int) error {
+	file, err := f.Get(id)
+	if err != nil {
+		return err
+	}
+	return global.GvaDb.Model(&file).Update("status", status).Error
+}
+
+// Delete 删除文件记录(软删除)
+func (f FileService) Delete(ids []uint) error {
+	return global.GvaDb.Delete(&dbModel.SysFile{}, ids).Error
+}
+
+// Open 打开文件用于下载 调用方负责关闭
+func (f FileService) Open(id uint) (dbModel.SysFile, io.ReadSeekCloser, error) {
+	file, err := f.Get(id)
+	if err != nil {
+		return file, nil, err
+	}
+	if file.Status != dbModel.FileEnable {
+		return file, nil, ApiReturn.FileDisabled
+	}
+	storage, err := upload.NewStorage()
+	if err != nil {
+		return file, nil, err
+	}
+	reader, err := storage.Open(file.StorageKey)
+	if errors.Is(err, upload.ErrNotExist) {
+		return file, nil, ApiReturn.NoFaile
+	}
+	if err != nil {
+		global.GvaLog.Error("文件打开失败", zap.Uint("id", id), zap.Error(err))
+		return file, nil, ApiReturn.DownloadFailed
+	}
+	return file, reader, nil
+}
+
+// Verify 重新计算存储中文件的 SHA-256 与记录比对
+func (f FileService) Verify(id uint) (res resModel.FileVerifyRes, err error) {
+	file, err := f.Get(id)
+	if err != nil {
+		return
+	}
+	storage, err := upload.NewStorage()
+	if err != nil {
+		return
+	}
+	reader, err := storage.Open(file.StorageKey)
+	if errors.Is(err, upload.ErrNotExist) {
+		return res, ApiReturn.NoFaile
+	}
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, reader); err != nil {
+		return
+	}
+	res.ID, res.Expected, res.Actual = file.ID, file.Sha256, hex.EncodeToString(hash.Sum(nil))
+	res.Valid = res.Expected == res.Actual
+	if !res.Valid {
+		global.GvaLog.Warn("文件完整性校验失败", zap.Uint("id", id), zap.String("expected", res.Expected), zap.String("actual", res.Actual))
+	}
+	return
+}
+
+// save 写入存储并创建文件记录
+func (f FileService) save(userId uint, name, mimeType string, src io.Reader) (file dbModel.SysFile, err error) {
+	storage, err := upload.NewStorage()
+	if err != nil {
+		return
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(ext); byExt != "" {
+			mimeType = byExt
+		}
+	}
+	key := path.Join(fileKeyPrefix, time.Now().Format("2006/01/02"), uuid.NewString()+ext)
+	hash := sha256.New()
+	size, err := storage.Put(key, io.TeeReader(src, hash))
+	if err != nil {
+		global.GvaLog.Error("文件写入失败", zap.String("name", name), zap.Error(err))
+		return file, ApiReturn.UploadFailed
+	}
+	file = dbModel.SysFile{
+		Name:        filepath.Base(name),
+		Ext:         ext,
+		MimeType:    mimeType,
+		Size:        size,
+		Sha256:      hex.EncodeToString(hash.Sum(nil)),
+		StorageType: storage.Type(),
+		StorageKey:  key,
+		Status:      dbModel.FileEnable,
+		CreatedBy:   userId,
+	}
+	if err = global.GvaDb.Create(&file).Error; err != nil {
+		_ = storage.Delete(key)
+		global.GvaLog.Error("文件记录保存失败", zap.String("name", name), zap.Error(err))
+		return file, ApiReturn.UploadFailed
+	}
+	return
+}
+
+func (f FileService) checkSize(size int64) error {
+	if cfg := global.GvaConfig.File; cfg != nil && cfg.MaxSize > 0 && size > cfg.MaxSize<<20 {
+		return ApiReturn.UploadFailed.WithData(fmt.Sprintf("文件大小不能超过%dMB", cfg.MaxSize))
+	}
+	return nil
+}
+
+func (f FileService) getUpload(userId uint, uploadId string) (uploadTask dbModel.SysFileUpload, err error) {
+	err = global.GvaDb.Where("upload_id = ?", uploadId).First(&uploadTask).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && uploadTask.CreatedBy != userId) {
+		return uploadTask, ApiReturn.NoRecordFiles
+	}
+	return
+}
+
+func (f FileService) chunkStatus(uploadTask dbModel.SysFileUpload) (res resModel.FileChunkRes, err error) {
+	res = resModel.FileChunkRes{
+		UploadId:   uploadTask.UploadId,
+		FileName:   uploadTask.FileName,
+		Size:       uploadTask.Size,
+		ChunkSize:  uploadTask.ChunkSize,
+		ChunkTotal: uploadTask.ChunkTotal,
+		Uploaded:   make([]int, 0),
+	}
+	err = global.GvaDb.Model(&dbModel.SysFileChunk{}).Where("upload_id = ?", uploadTask.UploadId).Order("chunk_index").Pluck("chunk_index", &res.Uploaded).Error
+	return
+}
+
+// removeUpload 删除分片上传任务及其分片
+func (f FileService) removeUpload(uploadId string) error {
+	storage, err := upload.NewStorage()
+	if err != nil {
+		return err
+	}
+	if err = storage.DeletePrefix(path.Join(chunkKeyPrefix, uploadId)); err != nil {
+		return err
+	}
+	return global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("upload_id = ?", uploadId).Delete(&dbModel.SysFileChunk{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("upload_id = ?", uploadId).Delete(&dbModel.SysFileUpload{}).Error
+	})
+}
+
+func (f FileService) chunkKey(uploadId string, index int) string {
+	return path.Join(chunkKeyPrefix, uploadId, fmt.Sprintf("%05d", index))
+}
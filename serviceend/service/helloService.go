@@ -1,7 +1,25 @@
 package service
 
+import (
+	"context"
+	"dataPanel/serviceend/global"
+	"errors"
+)
+
 type HelloService struct{}
 
 func (h HelloService) Hello() string {
 	return "Hello Go"
 }
+
+// DbHealth 数据库连通性检测
+func (h HelloService) DbHealth(ctx context.Context) error {
+	if global.GvaDb == nil {
+		return errors.New("数据库未初始化")
+	}
+	sqlDB, err := global.GvaDb.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils/importer"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	importPreviewRows = 20   // 默认预览行数
+	importInferRows   = 1000 // 推断列类型的样本行数
+	importBatchSize   = 500  // 默认每批插入行数
+	importMaxErrors   = 200  // 保留的错误明细条数
+)
+
+// importIdentifier 表名与列名 只允许字母、数字和下划线
+var importIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// importColumnTypes 导入列类型在各数据库中的字段类型
+var importColumnTypes = map[string]map[string]string{
+	"mysql": {
+		importer.TypeInteger:  "BIGINT",
+		importer.TypeReal:     "DOUBLE",
+		importer.TypeBoolean:  "BOOLEAN",
+		importer.TypeDatetime: "DATETIME",
+		importer.TypeText:     "TEXT",
+	},
+	"postgres": {
+		importer.TypeInteger:  "BIGINT",
+		importer.TypeReal:     "DOUBLE PRECISION",
+		importer.TypeBoolean:  "BOOLEAN",
+		importer.TypeDatetime: "TIMESTAMP",
+		importer.TypeText:     "TEXT",
+	},
+	"sqlite": {
+		importer.TypeInteger:  "INTEGER",
+		importer.TypeReal:     "REAL",
+		importer.TypeBoolean:  "BOOLEAN",
+		importer.TypeDatetime: "DATETIME",
+		importer.TypeText:     "TEXT",
+	},
+}
+
+// errImportInvalid 存在校验未通过的行且未选择跳过
+var errImportInvalid = errors.New("import rows invalid")
+
+type ImportService struct{}
+
+// importFile 打开的导入文件 无表头时首行放回作为数据
+type importFile struct {
+	importer.Reader
+	src      io.Closer
+	name     string
+	format   string
+	header   []string
+	pending  []string
+	pendLine int
+}
+
+func (f *importFile) Next() ([]string, int, error) {
+	if f.pending != nil {
+		row, line := f.pending, f.pendLine
+		f.pending = nil
+		return row, line, nil
+	}
+	return f.Reader.Next()
+}
+
+func (f *importFile) Close() error {
+	_ = f.Reader.Close()
+	return f.src.Close()
+}
+
+// Preview 预览导入文件 返回前若干行并按样本推断列类型
+func (i ImportService) Preview(req reqModel.ImportPreviewReq) (res resModel.ImportPreview, err error) {
+	file, err := i.open(req.ImportSourceReq)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	res.FileName, res.Format, res.Sheets = file.name, file.format, file.Sheets()
+	if file.format == importer.FormatXlsx {
+		res.Sheet = req.Sheet
+		if res.Sheet == "" && len(res.Sheets) > 0 {
+			res.Sheet = res.Sheets[0]
+		}
+	}
+	samples := make([][]string, 0)
+	width := len(file.header)
+	for len(samples) < importInferRows {
+		row, _, err := file.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return res, ApiReturn.ImportFileInvalid.WithData(err.Error())
+		}
+		samples = append(samples, row)
+		width = max(width, len(row))
+	}
+	res.Columns = make([]resModel.ImportColumn, width)
+	values := make([]string, len(samples))
+	for c := range res.Columns {
+		for r, row := range samples {
+			values[r] = ""
+			if c < len(row) {
+				values[r] = row[c]
+			}
+		}
+		res.Columns[c] = resModel.ImportColumn{Source: c, Name: i.columnName(file.header, c), Type: importer.Infer(values)}
+	}
+	size := importPreviewRows
+	if req.Rows > 0 {
+		size = req.Rows
+	}
+	res.Rows = make([][]string, 0, min(size, len(samples)))
+	for _, row := range samples[:min(size, len(samples))] {
+		padded := make([]string, width)
+		copy(padded, row)
+		res.Rows = append(res.Rows, padded)
+	}
+	return
+}
+
+// Tables 本地数据库中可导入的表 不含系统表
+func (i ImportService) Tables() ([]resModel.ImportTable, error) {
+	names, err := global.GvaDb.Migrator().GetTables()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	list := make([]resModel.ImportTable, 0)
+	for _, name := range names {
+		if !i.allowed(name) {
+			continue
+		}
+		types, err := global.GvaDb.Migrator().ColumnTypes(name)
+		if err != nil {
+			return nil, err
+		}
+		table := resModel.ImportTable{Name: name, Columns: make([]resModel.QueryColumn, len(types))}
+		for c, t := range types {
+			table.Columns[c] = resModel.QueryColumn{Name: t.Name(), Type: t.DatabaseTypeName()}
+		}
+		list = append(list, table)
+	}
+	return list, nil
+}
+
+// Import 按列映射转换、校验并在事务中分批导入
+// 未选择跳过错误行时 有任意行未通过校验则不导入, 返回全部错误明细
+func (i ImportService) Import(userId uint, req reqModel.ImportReq) (res resModel.ImportResult, err error) {
+	if !i.allowed(req.Table) {
+		return res, ApiReturn.ImportTableInvalid
+	}
+	if err = i.check(req.Columns); err != nil {
+		return
+	}
+	file, err := i.open(req.ImportSourceReq)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	exists := global.GvaDb.Migrator().HasTable(req.Table)
+	created := false
+	switch req.Mode {
+	case "create":
+		if exists {
+			return res, ApiReturn.ImportTableExisted
+		}
+		if err = i.create(req.Table, req.Columns); err != nil {
+			return res, ApiReturn.TxErrSystem.WithData(err.Error())
+		}
+		created = true
+	default:
+		if !exists {
+			return res, ApiReturn.NoImportTable
+		}
+		if err = i.checkTable(req.Table, req.Columns); err != nil {
+			return
+		}
+	}
+	size := importBatchSize
+	if req.BatchSize > 0 {
+		size = req.BatchSize
+	}
+	res.Table, res.Errors = req.Table, make([]resModel.ImportRowError, 0)
+	start := time.Now()
+	err = global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		batch := make([]map[string]interface{}, 0, size)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := tx.Table(req.Table).Create(&batch).Error; err != nil {
+				return err
+			}
+			res.Imported += len(batch)
+			batch = make([]map[string]interface{}, 0, size)
+			return nil
+		}
+		for {
+			row, line, err := file.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return ApiReturn.ImportFileInvalid.WithData(err.Error())
+			}
+			res.Total++
+			values, rowErrs := i.row(req.Columns, row, line)
+			if len(rowErrs) > 0 {
+				res.Failed++
+				if room := importMaxErrors - len(res.Errors); room > 0 {
+					res.Errors = append(res.Errors, rowErrs[:min(room, len(rowErrs))]...)
+				}
+				continue
+			}
+			// 有错误行且不跳过时 继续读取只为收集错误
+			if res.Failed > 0 && !req.SkipInvalid {
+				continue
+			}
+			if batch = append(batch, values); len(batch) >= size {
+				if err = flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if res.Failed > 0 && !req.SkipInvalid {
+			return errImportInvalid
+		}
+		return flush()
+	})
+	if err == nil {
+		global.GvaLog.Info("数据导入完成", zap.String("table", req.Table), zap.String("file", file.name), zap.Uint("userId", userId),
+			zap.Int("imported", res.Imported), zap.Int("failed", res.Failed), zap.Duration("duration", time.Since(start)))
+		return res, nil
+	}
+	res.Imported = 0
+	if created {
+		if dropErr := global.GvaDb.Migrator().DropTable(req.Table); dropErr != nil {
+			global.GvaLog.Error("导入失败后删除新建的表失败", zap.String("table", req.Table), zap.Error(dropErr))
+		}
+	}
+	var apiErr ApiReturn.ApiReturnCode
+	switch {
+	case errors.Is(err, errImportInvalid):
+		return res, ApiReturn.ImportValidateFailed.WithData(res)
+	case errors.As(err, &apiErr):
+		return res, err
+	}
+	global.GvaLog.Error("数据导入失败", zap.String("table", req.Table), zap.String("file", file.name), zap.Error(err))
+	return res, ApiReturn.TxErrSystem.WithData(err.Error())
+}
+
+// open 打开上传的文件或本地文件并读取表头
+func (i ImportService) open(req reqModel.ImportSourceReq) (*importFile, error) {
+	var (
+		src  io.ReadCloser
+		name string
+	)
+	switch {
+	case req.Path != "":
+		f, err := os.Open(req.Path)
+		if err != nil {
+			return nil, ApiReturn.ImportFileInvalid.WithData(err.Error())
+		}
+		src, name = f, filepath.Base(req.Path)
+	case req.FileId != 0:
+		file, reader, err := ServiceGroupApp.FileService.Open(req.FileId)
+		if err != nil {
+			return nil, err
+		}
+		src, name = reader, file.Name
+	default:
+		return nil, ApiReturn.ErrParam.WithData("请选择导入文件")
+	}
+	format, err := importer.Format(name)
+	if err != nil {
+		_ = src.Close()
+		return nil, ApiReturn.ImportFileInvalid.WithData(err.Error())
+	}
+	reader, err := importer.Open(format, src, req.Sheet)
+	if err != nil {
+		_ = src.Close()
+		return nil, ApiReturn.ImportFileInvalid.WithData(err.Error())
+	}
+	file := &importFile{Reader: reader, src: src, name: name, format: format}
+	row, line, err := reader.Next()
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = file.Close()
+		return nil, ApiReturn.ImportFileInvalid.WithData(err.Error())
+	}
+	if req.NoHeader {
+		file.header = make([]string, len(row))
+		file.pending, file.pendLine = row, line
+	} else {
+		file.header = row
+	}
+	return file, nil
+}
+
+// row 转换并校验一行 返回以目标列名为键的值
+func (i ImportService) row(columns []reqModel.ImportColumnReq, row []string, line int) (map[string]interface{}, []resModel.ImportRowError) {
+	values := make(map[string]interface{}, len(columns))
+	var errs []resModel.ImportRowError
+	for _, c := range columns {
+		raw := ""
+		if c.Source < len(row) {
+			raw = row[c.Source]
+		}
+		value, err := importer.Convert(c.Type, raw)
+		if err == nil {
+			err = i.validate(c, value)
+		}
+		if err != nil {
+			errs = append(errs, resModel.ImportRowError{Row: line, Column: c.Name, Message: err.Error()})
+			continue
+		}
+		values[c.Name] = value
+	}
+	return values, errs
+}
+
+// validate 使用 InitTrans 注册的校验器校验单元格 错误信息按当前语言翻译
+// 空值只校验必填, 非空值按规则校验
+func (i ImportService) validate(c reqModel.ImportColumnReq, value interface{}) error {
+	var err error
+	switch {
+	case value == nil && c.Required:
+		err = i.validator().Var("", "required")
+	case value != nil && c.Rules != "":
+		err = i.validator().Var(value, c.Rules)
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || global.GvaTrans == nil {
+		return err
+	}
+	msgs := make([]string, len(errs))
+	for n, fe := range errs {
+		msgs[n] = c.Name + fe.Translate(*global.GvaTrans)
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// check 校验列映射 列名唯一且合法, 校验规则可用
+func (i ImportService) check(columns []reqModel.ImportColumnReq) error {
+	seen := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		if !importIdentifier.MatchString(c.Name) {
+			return ApiReturn.ErrParam.WithData("列名只能包含字母、数字和下划线且不能以数字开头: " + c.Name)
+		}
+		if _, ok := seen[strings.ToLower(c.Name)]; ok {
+			return ApiReturn.ErrParam.WithData("目标列重复: " + c.Name)
+		}
+		seen[strings.ToLower(c.Name)] = struct{}{}
+		if err := i.checkRules(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRules 以列类型的零值试校验一次 未定义的规则或参数错误会使校验器 panic
+func (i ImportService) checkRules(c reqModel.ImportColumnReq) (err error) {
+	if c.Rules == "" {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ApiReturn.ErrParam.WithData(fmt.Sprintf("列 %s 的校验规则无效: %v", c.Name, r))
+		}
+	}()
+	zero := map[string]interface{}{
+		importer.TypeInteger:  int64(0),
+		importer.TypeReal:     float64(0),
+		importer.TypeBoolean:  false,
+		importer.TypeDatetime: time.Time{},
+		importer.TypeText:     "",
+	}[c.Type]
+	_ = i.validator().Var(zero, c.Rules)
+	return nil
+}
+
+// checkTable 追加导入时目标列必须已存在
+func (i ImportService) checkTable(table string, columns []reqModel.ImportColumnReq) error {
+	types, err := global.GvaDb.Migrator().ColumnTypes(table)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]struct{}, len(types))
+	for _, t := range types {
+		exists[strings.ToLower(t.Name())] = struct{}{}
+	}
+	for _, c := range columns {
+		if _, ok := exists[strings.ToLower(c.Name)]; !ok {
+			return ApiReturn.ErrParam.WithData(fmt.Sprintf("表 %s 中不存在列 %s", table, c.Name))
+		}
+	}
+	return nil
+}
+
+// create 按列映射建表
+func (i ImportService) create(table string, columns []reqModel.ImportColumnReq) error {
+	types, ok := importColumnTypes[global.GvaDb.Dialector.Name()]
+	if !ok {
+		types = importColumnTypes["sqlite"]
+	}
+	stmt := &gorm.Statement{DB: global.GvaDb}
+	defs := make([]string, len(columns))
+	for n, c := range columns {
+		defs[n] = stmt.Quote(c.Name) + " " + types[c.Type]
+	}
+	return global.GvaDb.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", stmt.Quote(table), strings.Join(defs, ", "))).Error
+}
+
+// allowed 可作为导入目标的表 系统表不可导入
+func (i ImportService) allowed(table string) bool {
+	name := strings.ToLower(table)
+	return importIdentifier.MatchString(table) && !strings.HasPrefix(name, "sys_") && !strings.HasPrefix(name, "sqlite_")
+}
+
+// columnName 文件列名 无表头或表头为空时按序号生成
+func (i ImportService) columnName(header []string, c int) string {
+	if c < len(header) {
+		if name := strings.TrimSpace(header[c]); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("column%d", c+1)
+}
+
+func (i ImportService) validator() *validator.Validate {
+	return binding.Validator.Engine().(*validator.Validate)
+}
@@ -0,0 +1,504 @@
+package service
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/notify"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// 任务上一次执行未结束时再次触发的处理方式
+const (
+	JobPolicySkip     = "skip"     // 跳过本次触发 记录一条 skipped
+	JobPolicyQueue    = "queue"    // 排队 上一次结束后依次执行, 超出排队上限的触发按跳过记录
+	JobPolicyParallel = "parallel" // 并行执行
+)
+
+const (
+	jobDefaultBackoff = time.Minute         // 默认首次重试等待时间
+	jobStopTimeout    = 10 * time.Second    // 退出时等待执行中任务结束的时间
+	jobErrorMaxLen    = 1024                // 执行记录中错误信息的最大长度
+	jobRunKeepTime    = 30 * 24 * time.Hour // 执行记录保留时间
+	jobQueueMax       = 1                   // 排队策略最多等待的触发次数
+)
+
+// jobCronParser 标准五段式 cron 表达式, 支持可选的秒字段与 @daily/@every 1h 等描述符
+var jobCronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Job 定时任务 在代码中实现后通过 JobService.Register 注册
+type Job interface {
+	Spec() JobSpec
+	Run(ctx context.Context) error
+}
+
+// JobSpec 任务定义 Cron 与 At 二选一
+type JobSpec struct {
+	Name    string        // 任务标识 唯一
+	Title   string        // 显示名称
+	Cron    string        // cron 表达式
+	At      time.Time     // 一次性任务的执行时间 已过期则启动后立即执行, 成功后不再执行
+	Policy  string        // 并发策略 默认 skip
+	Retries int           // 失败重试次数
+	Backoff time.Duration // 首次重试等待时间 之后每次加倍
+	Timeout time.Duration // 单次执行超时 0 为不限制
+}
+
+// FuncJob 以函数实现的任务
+type FuncJob struct {
+	JobSpec
+	Fn func(ctx context.Context) error
+}
+
+func (f FuncJob) Spec() JobSpec {
+	return f.JobSpec
+}
+
+func (f FuncJob) Run(ctx context.Context) error {
+	return f.Fn(ctx)
+}
+
+// jobEntry 已注册任务的调度状态
+type jobEntry struct {
+	job      Job
+	spec     JobSpec
+	schedule cron.Schedule // 一次性任务为 nil
+	timer    *time.Timer
+	next     time.Time
+	paused   bool
+	done     bool     // 一次性任务已成功执行
+	running  int      // 执行中的数量
+	pending  []string // 排队等待执行的触发方式
+}
+
+// jobScheduler 任务注册表 任务标识 => 调度状态
+var jobScheduler = struct {
+	sync.Mutex
+	jobs    map[string]*jobEntry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	wg      sync.WaitGroup
+}{jobs: map[string]*jobEntry{}}
+
+type JobService struct{}
+
+// Register 注册任务 调度已启动时立即生效
+func (j JobService) Register(job Job) error {
+	spec := job.Spec()
+	if spec.Name == "" {
+		return errors.New("任务标识不能为空")
+	}
+	if spec.Policy == "" {
+		spec.Policy = JobPolicySkip
+	}
+	if spec.Policy != JobPolicySkip && spec.Policy != JobPolicyQueue && spec.Policy != JobPolicyParallel {
+		return fmt.Errorf("任务 %s 的并发策略 %s 不支持", spec.Name, spec.Policy)
+	}
+	if spec.Backoff <= 0 {
+		spec.Backoff = jobDefaultBackoff
+	}
+	entry := &jobEntry{job: job, spec: spec}
+	switch {
+	case spec.Cron != "" && !spec.At.IsZero():
+		return fmt.Errorf("任务 %s 不能同时设置 cron 表达式与执行时间", spec.Name)
+	case spec.Cron != "":
+		schedule, err := jobCronParser.Parse(spec.Cron)
+		if err != nil {
+			return fmt.Errorf("任务 %s 的 cron 表达式不合法: %w", spec.Name, err)
+		}
+		entry.schedule = schedule
+	case spec.At.IsZero():
+		return fmt.Errorf("任务 %s 未设置 cron 表达式或执行时间", spec.Name)
+	}
+	jobScheduler.Lock()
+	defer jobScheduler.Unlock()
+	if _, ok := jobScheduler.jobs[spec.Name]; ok {
+		return fmt.Errorf("任务 %s 已注册", spec.Name)
+	}
+	jobScheduler.jobs[spec.Name] = entry
+	if jobScheduler.started {
+		if err := j.load(entry); err != nil {
+			return err
+		}
+		j.arm(entry)
+	}
+	return nil
+}
+
+// Unregister 取消注册任务 执行中的任务不中断
+func (j JobService) Unregister(name string) {
+	jobScheduler.Lock()
+	defer jobScheduler.Unlock()
+	if entry, ok := jobScheduler.jobs[name]; ok {
+		j.disarm(entry)
+		entry.pending = nil
+		delete(jobScheduler.jobs, name)
+	}
+}
+
+// Start 注册内置任务并启动调度 上次退出时未结束的执行记录标记为中断
+// Stop 后可再次调用 已注册的任务保留, 不重复注册
+func (j JobService) Start() error {
+	jobScheduler.Lock()
+	started := jobScheduler.started
+	registered := make(map[string]bool, len(jobScheduler.jobs))
+	for name := range jobScheduler.jobs {
+		registered[name] = true
+	}
+	jobScheduler.Unlock()
+	if started {
+		return nil
+	}
+	for _, job := range builtinJobs {
+		if registered[job.Spec().Name] {
+			continue
+		}
+		if err := j.Register(job); err != nil {
+			return err
+		}
+	}
+	now := utils.LocalTime(time.Now())
+	if err := global.GvaDb.Model(&dbModel.SysJobRun{}).Where("status = ?", dbModel.JobRunning).
+		Updates(map[string]interface{}{"status": dbModel.JobCancelled, "error": "程序退出时未结束", "finished_at": now}).Error; err != nil {
+		return err
+	}
+	jobScheduler.Lock()
+	defer jobScheduler.Unlock()
+	for _, entry := range jobScheduler.jobs {
+		if err := j.load(entry); err != nil {
+			return err
+		}
+	}
+	jobScheduler.ctx, jobScheduler.cancel = context.WithCancel(context.Background())
+	jobScheduler.started = true
+	for _, entry := range jobScheduler.jobs {
+		j.arm(entry)
+	}
+	global.GvaLog.Info("定时任务调度已启动", zap.Int("count", len(jobScheduler.jobs)))
+	return nil
+}
+
+// Stop 停止调度并中断执行中的任务 最多等待 jobStopTimeout
+func (j JobService) Stop() {
+	jobScheduler.Lock()
+	if !jobScheduler.started {
+		jobScheduler.Unlock()
+		return
+	}
+	jobScheduler.started = false
+	for _, entry := range jobScheduler.jobs {
+		j.disarm(entry)
+		entry.pending = nil
+	}
+	jobScheduler.cancel()
+	jobScheduler.Unlock()
+	done := make(chan struct{})
+	go func() {
+		jobScheduler.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		global.GvaLog.Info("定时任务调度已停止")
+	case <-time.After(jobStopTimeout):
+		global.GvaLog.Warn("定时任务未在限定时间内结束")
+	}
+}
+
+// List 已注册的任务 按标识排序
+func (j JobService) List() ([]resModel.JobInfo, error) {
+	jobScheduler.Lock()
+	list := make([]resModel.JobInfo, 0, len(jobScheduler.jobs))
+	for _, entry := range jobScheduler.jobs {
+		info := resModel.JobInfo{
+			Name:    entry.spec.Name,
+			Title:   entry.spec.Title,
+			Cron:    entry.spec.Cron,
+			Policy:  entry.spec.Policy,
+			Retries: entry.spec.Retries,
+			Paused:  entry.paused,
+			Running: entry.running,
+			Pending: len(entry.pending),
+		}
+		if !entry.spec.At.IsZero() {
+			info.At = entry.spec.At.Format(utils.TimeFormat)
+		}
+		if entry.timer != nil {
+			info.NextRun = entry.next.Format(utils.TimeFormat)
+		}
+		list = append(list, info)
+	}
+	jobScheduler.Unlock()
+	sort.Slice(list, func(a, b int) bool { return list[a].Name < list[b].Name })
+	for i := range list {
+		var runs []dbModel.SysJobRun
+		if err := global.GvaDb.Where("job_name = ?", list[i].Name).Order("id DESC").Limit(1).Find(&runs).Error; err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			list[i].LastRun = &runs[0]
+		}
+	}
+	return list, nil
+}
+
+// Trigger 手动执行任务 暂停中的任务也可执行
+func (j JobService) Trigger(name string) error {
+	jobScheduler.Lock()
+	entry, ok := jobScheduler.jobs[name]
+	if !ok || !jobScheduler.started {
+		jobScheduler.Unlock()
+		return ApiReturn.NoJob
+	}
+	err := j.dispatch(entry, dbModel.JobTriggerManual)
+	jobScheduler.Unlock()
+	if errors.Is(err, ApiReturn.JobRunning) {
+		if dbErr := j.skipped(name, dbModel.JobTriggerManual); dbErr != nil {
+			return dbErr
+		}
+	}
+	return err
+}
+
+// Pause 暂停任务的计划执行 状态持久化, 重启后保持
+func (j JobService) Pause(name string) error {
+	return j.setPaused(name, true)
+}
+
+// Resume 恢复任务的计划执行
+func (j JobService) Resume(name string) error {
+	return j.setPaused(name, false)
+}
+
+// Logs 任务执行记录
+func (j JobService) Logs(req reqModel.JobRunListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysJobRun{})
+	if req.JobName != "" {
+		db = db.Where("job_name = ?", req.JobName)
+	}
+	if req.Status != "" {
+		db = db.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		db = db.Where("error LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysJobRun
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+func (j JobService) setPaused(name string, paused bool) error {
+	jobScheduler.Lock()
+	entry, ok := jobScheduler.jobs[name]
+	jobScheduler.Unlock()
+	if !ok {
+		return ApiReturn.NoJob
+	}
+	var job dbModel.SysJob
+	if err := global.GvaDb.Where(dbModel.SysJob{Name: name}).Assign(map[string]interface{}{"paused": paused}).FirstOrCreate(&job).Error; err != nil {
+		return err
+	}
+	jobScheduler.Lock()
+	defer jobScheduler.Unlock()
+	entry.paused = paused
+	if paused {
+		j.disarm(entry)
+	} else {
+		j.arm(entry)
+	}
+	global.GvaLog.Info("定时任务状态变更", zap.String("name", name), zap.Bool("paused", paused))
+	return nil
+}
+
+// load 读取持久化的暂停状态 一次性任务已成功执行过则标记完成
+func (j JobService) load(entry *jobEntry) error {
+	var jobs []dbModel.SysJob
+	if err := global.GvaDb.Where("name = ?", entry.spec.Name).Limit(1).Find(&jobs).Error; err != nil {
+		return err
+	}
+	entry.paused = len(jobs) > 0 && jobs[0].Paused
+	if entry.schedule == nil {
+		var count int64
+		if err := global.GvaDb.Model(&dbModel.SysJobRun{}).Where("job_name = ? AND status = ?", entry.spec.Name, dbModel.JobSuccess).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		entry.done = count > 0
+	}
+	return nil
+}
+
+// arm 设置下一次计划执行的定时器 需持有锁
+func (j JobService) arm(entry *jobEntry) {
+	j.disarm(entry)
+	if !jobScheduler.started || entry.paused || entry.done {
+		return
+	}
+	now := time.Now()
+	entry.next = entry.spec.At
+	if entry.schedule != nil {
+		entry.next = entry.schedule.Next(now)
+	}
+	if entry.next.IsZero() {
+		return
+	}
+	entry.timer = time.AfterFunc(max(entry.next.Sub(now), 0), func() { j.fire(entry) })
+}
+
+// disarm 停止定时器 需持有锁
+func (j JobService) disarm(entry *jobEntry) {
+	if entry.timer != nil {
+		entry.timer.Stop()
+		entry.timer = nil
+	}
+}
+
+// fire 计划时间到达 先设置下一次定时器再执行
+func (j JobService) fire(entry *jobEntry) {
+	jobScheduler.Lock()
+	if entry.timer == nil || !jobScheduler.started {
+		jobScheduler.Unlock()
+		return
+	}
+	entry.timer = nil
+	if entry.schedule != nil {
+		j.arm(entry)
+	}
+	err := j.dispatch(entry, dbModel.JobTriggerSchedule)
+	jobScheduler.Unlock()
+	if errors.Is(err, ApiReturn.JobRunning) {
+		err = j.skipped(entry.spec.Name, dbModel.JobTriggerSchedule)
+	}
+	if err != nil {
+		global.GvaLog.Error("定时任务触发失败", zap.String("name", entry.spec.Name), zap.Error(err))
+	}
+}
+
+// dispatch 按并发策略执行任务 需持有锁
+// 按 skip 策略跳过或排队已满时返回 ApiReturn.JobRunning, 由调用方释放锁后调用 skipped 记录
+func (j JobService) dispatch(entry *jobEntry, trigger string) error {
+	if entry.running > 0 {
+		switch entry.spec.Policy {
+		case JobPolicySkip:
+			return ApiReturn.JobRunning
+		case JobPolicyQueue:
+			if len(entry.pending) >= jobQueueMax {
+				return ApiReturn.JobRunning
+			}
+			entry.pending = append(entry.pending, trigger)
+			return nil
+		}
+	}
+	entry.running++
+	jobScheduler.wg.Add(1)
+	go j.execute(jobScheduler.ctx, entry, trigger)
+	return nil
+}
+
+// skipped 记录一条跳过的执行 不持有锁调用, 避免数据库写入阻塞调度
+func (j JobService) skipped(name, trigger string) error {
+	now := utils.LocalTime(time.Now())
+	run := dbModel.SysJobRun{JobName: name, Trigger: trigger, Attempt: 1, Status: dbModel.JobSkipped,
+		Error: "上一次执行未结束", CreatedAt: now, FinishedAt: &now}
+	return global.GvaDb.Create(&run).Error
+}
+
+// execute 执行任务 失败时按退避时间重试, 结束后执行排队的触发
+func (j JobService) execute(ctx context.Context, entry *jobEntry, trigger string) {
+	defer jobScheduler.wg.Done()
+	var err error
+	for attempt := 1; attempt <= entry.spec.Retries+1; attempt++ {
+		if attempt > 1 {
+			wait := entry.spec.Backoff << (attempt - 2)
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+		// 开始前或重试等待中已取消 按未成功处理, 一次性任务不标记完成
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+		if err = j.attempt(ctx, entry, trigger, attempt); err == nil {
+			break
+		}
+	}
+	if err != nil && ctx.Err() == nil {
+		ServiceGroupApp.NotifyService.Send(notify.Message{Event: NotifyJobFailed, Level: "error", Title: "任务执行失败: " + entry.spec.Name,
+			Content: err.Error(), Data: map[string]interface{}{"name": entry.spec.Name, "title": entry.spec.Title, "trigger": trigger}})
+	}
+	jobScheduler.Lock()
+	defer jobScheduler.Unlock()
+	entry.running--
+	if err == nil && entry.schedule == nil {
+		entry.done = true
+	}
+	if len(entry.pending) > 0 && jobScheduler.started {
+		next := entry.pending[0]
+		entry.pending = entry.pending[1:]
+		entry.running++
+		jobScheduler.wg.Add(1)
+		go j.execute(jobScheduler.ctx, entry, next)
+	}
+}
+
+// attempt 单次执行并记录结果 任务 panic 按失败处理
+func (j JobService) attempt(ctx context.Context, entry *jobEntry, trigger string, attempt int) (err error) {
+	run := dbModel.SysJobRun{JobName: entry.spec.Name, Trigger: trigger, Attempt: attempt, Status: dbModel.JobRunning}
+	if err = global.GvaDb.Create(&run).Error; err != nil {
+		global.GvaLog.Error("任务执行记录保存失败", zap.String("name", entry.spec.Name), zap.Error(err))
+		return
+	}
+	if entry.spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, entry.spec.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		status := dbModel.JobSuccess
+		switch {
+		case err == nil:
+		case errors.Is(ctx.Err(), context.Canceled):
+			status = dbModel.JobCancelled
+		default:
+			status = dbModel.JobFailed
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("执行超时: %w", err)
+			}
+		}
+		now := utils.LocalTime(time.Now())
+		updates := map[string]interface{}{"status": status, "duration": time.Since(start).Milliseconds(), "finished_at": now}
+		if err != nil {
+			updates["error"] = utils.Truncate(err.Error(), jobErrorMaxLen)
+			global.GvaLog.Warn("定时任务执行失败", zap.String("name", entry.spec.Name), zap.Int("attempt", attempt), zap.Error(err))
+		}
+		if dbErr := global.GvaDb.Model(&run).Updates(updates).Error; dbErr != nil {
+			global.GvaLog.Error("任务执行记录保存失败", zap.String("name", entry.spec.Name), zap.Error(dbErr))
+		}
+	}()
+	return entry.job.Run(ctx)
+}
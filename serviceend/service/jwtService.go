@@ -0,0 +1,75 @@
+package service
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/utils"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type JwtService struct{}
+
+// blacklistCache 已注销令牌的内存缓存 jti => 过期时间
+var blacklistCache sync.Map
+
+// LoadBlacklist 启动时清理过期记录并将未过期的黑名单加载到内存
+func (j JwtService) LoadBlacklist() error {
+	now := time.Now()
+	if err := global.GvaDb.Where("expires_at < ?", now).Delete(&dbModel.SysJwtBlacklist{}).Error; err != nil {
+		return err
+	}
+	var list []dbModel.SysJwtBlacklist
+	if err := global.GvaDb.Find(&list).Error; err != nil {
+		return err
+	}
+	for _, item := range list {
+		blacklistCache.Store(item.Jti, item.ExpiresAt)
+	}
+	global.GvaLog.Info("令牌黑名单加载完成", zap.Int("count", len(list)))
+	return nil
+}
+
+// JoinBlacklist 注销令牌
+func (j JwtService) JoinBlacklist(claims *utils.CustomClaims) error {
+	if claims == nil || claims.ExpiresAt == nil {
+		return nil
+	}
+	record := dbModel.SysJwtBlacklist{Jti: claims.ID, ExpiresAt: claims.ExpiresAt.Time}
+	if err := global.GvaDb.Create(&record).Error; err != nil {
+		return err
+	}
+	blacklistCache.Store(record.Jti, record.ExpiresAt)
+	return nil
+}
+
+// IsBlacklist 令牌是否已注销
+func (j JwtService) IsBlacklist(jti string) bool {
+	expiresAt, ok := blacklistCache.Load(jti)
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiresAt.(time.Time)) {
+		// 令牌本身已过期,无需继续占用内存
+		blacklistCache.Delete(jti)
+	}
+	return true
+}
+
+// IssueTokens 为指定会话签发一对访问令牌与刷新令牌
+func (j JwtService) IssueTokens(user dbModel.SysUser, sessionId string) (access, refresh utils.CustomClaims, accessToken, refreshToken string, err error) {
+	jwt := utils.NewJWT()
+	if access, err = jwt.CreateClaims(user.ID, user.Username, user.NickName, utils.TokenTypeAccess, sessionId); err != nil {
+		return
+	}
+	if refresh, err = jwt.CreateClaims(user.ID, user.Username, user.NickName, utils.TokenTypeRefresh, sessionId); err != nil {
+		return
+	}
+	if accessToken, err = jwt.CreateToken(access); err != nil {
+		return
+	}
+	refreshToken, err = jwt.CreateToken(refresh)
+	return
+}
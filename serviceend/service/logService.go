@@ -0,0 +1,560 @@
+package service
+
+import (
+	"bufio"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/logview"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// EventLogTail 实时跟踪到新的日志 推送按时间排列的日志条目
+const EventLogTail = "log:tail"
+
+const (
+	logDateLayout   = "2006-01-02" // 日志目录按日期划分 见 internal.fileRotator
+	logSearchLimit  = 200          // 搜索默认返回条数
+	logTailInterval = time.Second  // 实时跟踪读取间隔
+	logTailMaxBatch = 500          // 每次推送的最大条数 超出的丢弃最早的部分
+)
+
+type LogService struct{}
+
+// logLevelRevert 临时修改日志级别后的自动恢复
+var logLevelRevert = struct {
+	sync.Mutex
+	timer *time.Timer
+	at    time.Time
+	gen   uint64 // 每次取消自动恢复时递增 已触发但未执行的恢复据此跳过
+}{}
+
+// logTailer 实时跟踪当天的日志文件 有订阅者时按间隔读取新增内容
+var logTailer = struct {
+	sync.Mutex
+	subs   map[int]logTailSub
+	nextId int
+	stop   chan struct{}
+	emit   func() // 桌面窗口的跟踪 推送 log:tail 事件
+}{subs: map[int]logTailSub{}}
+
+type logTailSub struct {
+	filter logFilter
+	fn     func([]resModel.LogEntry)
+}
+
+// logTailFile 单个级别日志文件的读取位置
+type logTailFile struct {
+	name   string // 相对日志目录
+	info   os.FileInfo
+	offset int64
+}
+
+// Level 当前日志级别
+func (l LogService) Level() resModel.LogLevel {
+	res := resModel.LogLevel{Level: global.GvaLogLevel.Level().String(), ConfigLevel: l.configLevel().String()}
+	logLevelRevert.Lock()
+	if logLevelRevert.timer != nil {
+		res.RevertAt = logLevelRevert.at.Format(utils.TimeFormat)
+	}
+	logLevelRevert.Unlock()
+	return res
+}
+
+// SetLevel 修改日志级别 立即生效, 指定持续时间时到期后恢复为配置文件中的级别
+func (l LogService) SetLevel(req reqModel.LogLevelReq) (resModel.LogLevel, error) {
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil {
+		return resModel.LogLevel{}, ApiReturn.ErrParam.WithData(err.Error())
+	}
+	var duration time.Duration
+	if req.Duration != "" {
+		if duration, err = utils.ParseDuration(req.Duration); err != nil || duration <= 0 {
+			return resModel.LogLevel{}, ApiReturn.ErrParam.WithData("持续时间格式错误: " + req.Duration)
+		}
+	}
+	logLevelRevert.Lock()
+	l.stopRevert()
+	if duration > 0 {
+		gen := logLevelRevert.gen
+		logLevelRevert.at = time.Now().Add(duration)
+		logLevelRevert.timer = time.AfterFunc(duration, func() { l.revert(gen) })
+	}
+	// 先以 warn 记录 保证调高级别前后都能看到
+	global.GvaLog.Warn("日志级别已修改", zap.String("from", global.GvaLogLevel.Level().String()), zap.String("to", level.String()), zap.Duration("duration", duration))
+	global.GvaLogLevel.SetLevel(level)
+	logLevelRevert.Unlock()
+	return l.Level(), nil
+}
+
+// ResetLevel 恢复为配置文件中的级别 并取消自动恢复
+func (l LogService) ResetLevel() {
+	logLevelRevert.Lock()
+	defer logLevelRevert.Unlock()
+	l.resetLevel()
+}
+
+// revert 自动恢复到期 期间级别已被重新修改或恢复时跳过
+func (l LogService) revert(gen uint64) {
+	logLevelRevert.Lock()
+	defer logLevelRevert.Unlock()
+	if logLevelRevert.gen != gen {
+		return
+	}
+	l.resetLevel()
+}
+
+// resetLevel 调用方持有 logLevelRevert 锁
+func (l LogService) resetLevel() {
+	l.stopRevert()
+	level := l.configLevel()
+	if global.GvaLogLevel.Level() == level {
+		return
+	}
+	global.GvaLogLevel.SetLevel(level)
+	global.GvaLog.Warn("日志级别已恢复为配置级别", zap.String("level", level.String()))
+}
+
+// stopRevert 取消自动恢复 Stop 无法拦截已触发的回调, 递增 gen 使其失效 调用方持有 logLevelRevert 锁
+func (l LogService) stopRevert() {
+	logLevelRevert.gen++
+	if logLevelRevert.timer != nil {
+		logLevelRevert.timer.Stop()
+		logLevelRevert.timer = nil
+	}
+}
+
+func (l LogService) configLevel() zapcore.Level {
+	if global.GvaConfig.Zap == nil {
+		return zapcore.InfoLevel
+	}
+	return global.GvaConfig.Zap.TransportLevel()
+}
+
+// Files 日志文件列表 按日期倒序
+func (l LogService) Files(req reqModel.LogFileListReq) ([]resModel.LogFile, error) {
+	dir := l.director()
+	dates, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []resModel.LogFile{}, nil
+		}
+		return nil, err
+	}
+	list := make([]resModel.LogFile, 0)
+	for _, d := range dates {
+		if !d.IsDir() || (req.Date != "" && d.Name() != req.Date) {
+			continue
+		}
+		if _, err := time.Parse(logDateLayout, d.Name()); err != nil {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, d.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !(strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz")) {
+				continue
+			}
+			level, _, _ := strings.Cut(name, ".")
+			if req.Level != "" && level != req.Level {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			list = append(list, resModel.LogFile{Date: d.Name(), Level: level, Name: name, Size: info.Size(),
+				Compressed: strings.HasSuffix(name, ".gz"), ModifiedAt: info.ModTime().Format(utils.TimeFormat)})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Date != list[j].Date {
+			return list[i].Date > list[j].Date
+		}
+		if list[i].Level != list[j].Level {
+			return list[i].Level < list[j].Level
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Search 按级别、关键字与时间范围搜索日志 压缩文件同样搜索, 返回最新的 Limit 条
+func (l LogService) Search(req reqModel.LogSearchReq) (res resModel.LogSearchResult, err error) {
+	filter, err := l.filter(req.LogFilterReq)
+	if err != nil {
+		return
+	}
+	var start, end time.Time
+	if req.Start != "" {
+		start, _ = time.ParseInLocation(utils.TimeFormat, req.Start, time.Local)
+	}
+	if req.End != "" {
+		end, _ = time.ParseInLocation(utils.TimeFormat, req.End, time.Local)
+	}
+	if req.Start == "" && req.End == "" {
+		now := time.Now()
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if !end.IsZero() && end.Before(start) {
+		return res, ApiReturn.ErrParam.WithData("结束时间不能早于开始时间")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = logSearchLimit
+	}
+	files, err := l.Files(reqModel.LogFileListReq{})
+	if err != nil {
+		return
+	}
+	var matches []logMatch
+	// trim 按时间倒序只保留最新的 limit 条
+	trim := func() {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].at.After(matches[j].at) })
+		if len(matches) > limit {
+			matches, res.Truncated = matches[:limit], true
+		}
+	}
+	for _, f := range files {
+		if (!start.IsZero() && f.Date < start.Format(logDateLayout)) || (!end.IsZero() && f.Date > end.Format(logDateLayout)) || !filter.level(f.Level) {
+			continue
+		}
+		name := filepath.Join(f.Date, f.Name)
+		collect := func(entry resModel.LogEntry, at time.Time) {
+			if (!start.IsZero() && at.Before(start)) || (!end.IsZero() && at.After(end)) || !filter.match(entry) {
+				return
+			}
+			matches = append(matches, logMatch{entry: entry, at: at})
+			if len(matches) >= 2*limit {
+				trim()
+			}
+		}
+		_, err = l.read(name, 0, collect)
+		// 列出后被后台清理压缩或删除
+		if os.IsNotExist(err) && strings.HasSuffix(name, ".log") {
+			_, err = l.read(name+".gz", 0, collect)
+		}
+		if os.IsNotExist(err) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return
+		}
+		res.Files++
+	}
+	trim()
+	res.List = make([]resModel.LogEntry, len(matches))
+	for i, m := range matches {
+		res.List[i] = m.entry
+	}
+	return
+}
+
+// logMatch 解析后的日志与时间 用于排序
+type logMatch struct {
+	entry resModel.LogEntry
+	at    time.Time
+}
+
+// logFilter 编译后的过滤条件
+type logFilter struct {
+	levels  map[string]struct{}
+	keyword string
+	pattern *regexp.Regexp
+}
+
+func (l LogService) filter(req reqModel.LogFilterReq) (f logFilter, err error) {
+	if len(req.Levels) > 0 {
+		f.levels = make(map[string]struct{}, len(req.Levels))
+		for _, level := range req.Levels {
+			f.levels[level] = struct{}{}
+		}
+	}
+	if req.Regex && req.Keyword != "" {
+		if f.pattern, err = regexp.Compile(req.Keyword); err != nil {
+			return f, ApiReturn.LogPatternInvalid.WithData(err.Error())
+		}
+		return
+	}
+	f.keyword = strings.ToLower(req.Keyword)
+	return
+}
+
+func (f logFilter) level(level string) bool {
+	if f.levels == nil {
+		return true
+	}
+	_, ok := f.levels[level]
+	return ok
+}
+
+// match 级别与关键字是否匹配 关键字在消息、调用位置、附加字段与堆栈中查找
+func (f logFilter) match(entry resModel.LogEntry) bool {
+	if !f.level(entry.Level) {
+		return false
+	}
+	if f.pattern == nil && f.keyword == "" {
+		return true
+	}
+	text := entry.Message + "\n" + entry.Logger + "\n" + entry.Caller + "\n" + entry.Stack
+	if len(entry.Fields) > 0 {
+		fields, _ := json.Marshal(entry.Fields)
+		text += "\n" + string(fields)
+	}
+	if f.pattern != nil {
+		return f.pattern.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), f.keyword)
+}
+
+// read 从 offset 开始逐条解析日志文件 不是日志开头的行并入上一条的堆栈
+// 返回最后一个完整行的结束位置, 末尾未写完的行留到下次读取
+func (l LogService) read(name string, offset int64, fn func(resModel.LogEntry, time.Time)) (int64, error) {
+	r, err := logview.Open(filepath.Join(l.director(), name))
+	if err != nil {
+		return offset, err
+	}
+	defer r.Close()
+	if offset > 0 {
+		if _, err = io.CopyN(io.Discard, r, offset); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return offset, err
+		}
+	}
+	var (
+		pending *resModel.LogEntry
+		at      time.Time
+	)
+	flush := func() {
+		if pending != nil {
+			fn(*pending, at)
+			pending = nil
+		}
+	}
+	var stackKey string
+	if global.GvaConfig.Zap != nil {
+		stackKey = global.GvaConfig.Zap.StacktraceKey
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// 末尾未写完的行
+			flush()
+			if err == io.EOF {
+				return offset, nil
+			}
+			return offset, err
+		}
+		offset += int64(len(line))
+		entry, t, ok := logview.Parse(line, stackKey)
+		if !ok {
+			if pending != nil {
+				pending.Stack = strings.TrimPrefix(pending.Stack+"\n"+strings.TrimRight(line, "\r\n"), "\n")
+			}
+			continue
+		}
+		flush()
+		entry.File = filepath.ToSlash(name)
+		pending, at = &entry, t
+	}
+}
+
+func (l LogService) director() string {
+	if global.GvaConfig.Zap == nil || global.GvaConfig.Zap.Director == "" {
+		return "log"
+	}
+	return global.GvaConfig.Zap.Director
+}
+
+// Tail 订阅新写入的日志 符合条件的条目按时间排列分批回调, 返回取消订阅的方法
+// 订阅开始前已写入的内容不会推送
+func (l LogService) Tail(req reqModel.LogFilterReq, fn func([]resModel.LogEntry)) (cancel func(), err error) {
+	filter, err := l.filter(req)
+	if err != nil {
+		return nil, err
+	}
+	logTailer.Lock()
+	defer logTailer.Unlock()
+	id := logTailer.nextId
+	logTailer.nextId++
+	logTailer.subs[id] = logTailSub{filter: filter, fn: fn}
+	if logTailer.stop == nil {
+		logTailer.stop = make(chan struct{})
+		go l.tail(logTailer.stop, l.tailFiles())
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			logTailer.Lock()
+			defer logTailer.Unlock()
+			delete(logTailer.subs, id)
+			if len(logTailer.subs) == 0 && logTailer.stop != nil {
+				close(logTailer.stop)
+				logTailer.stop = nil
+			}
+		})
+	}, nil
+}
+
+// StartTail 开始向桌面窗口推送 log:tail 事件 已在跟踪时按新条件重新开始
+func (l LogService) StartTail(req reqModel.LogFilterReq) error {
+	cancel, err := l.Tail(req, func(list []resModel.LogEntry) {
+		ServiceGroupApp.EventService.Emit(EventLogTail, list)
+	})
+	if err != nil {
+		return err
+	}
+	logTailer.Lock()
+	prev := logTailer.emit
+	logTailer.emit = cancel
+	logTailer.Unlock()
+	if prev != nil {
+		prev()
+	}
+	return nil
+}
+
+// StopTail 停止向桌面窗口推送 程序退出时调用
+func (l LogService) StopTail() {
+	logTailer.Lock()
+	cancel := logTailer.emit
+	logTailer.emit = nil
+	logTailer.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (l LogService) tail(stop chan struct{}, files map[string]*logTailFile) {
+	ticker := time.NewTicker(logTailInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			matches := l.poll(now, files)
+			if len(matches) == 0 {
+				continue
+			}
+			sort.SliceStable(matches, func(i, j int) bool { return matches[i].at.Before(matches[j].at) })
+			if len(matches) > logTailMaxBatch {
+				matches = matches[len(matches)-logTailMaxBatch:]
+			}
+			logTailer.Lock()
+			subs := make([]logTailSub, 0, len(logTailer.subs))
+			for _, sub := range logTailer.subs {
+				subs = append(subs, sub)
+			}
+			logTailer.Unlock()
+			for _, sub := range subs {
+				list := make([]resModel.LogEntry, 0, len(matches))
+				for _, m := range matches {
+					if sub.filter.match(m.entry) {
+						list = append(list, m.entry)
+					}
+				}
+				if len(list) > 0 {
+					sub.fn(list)
+				}
+			}
+		}
+	}
+}
+
+// tailFiles 当天已存在的日志文件 从文件末尾开始跟踪
+func (l LogService) tailFiles() map[string]*logTailFile {
+	files := make(map[string]*logTailFile, len(logview.Levels))
+	date := time.Now().Format(logDateLayout)
+	for _, level := range logview.Levels {
+		name := filepath.Join(date, level+".log")
+		if info, err := os.Stat(filepath.Join(l.director(), name)); err == nil {
+			files[level] = &logTailFile{name: name, info: info, offset: info.Size()}
+		}
+	}
+	return files
+}
+
+// poll 读取各级别当天日志文件的新增内容
+// 跨天或按大小切分时先读完原文件剩余的内容, 跟踪开始后新建的文件从头读取
+func (l LogService) poll(now time.Time, files map[string]*logTailFile) (matches []logMatch) {
+	collect := func(entry resModel.LogEntry, at time.Time) {
+		matches = append(matches, logMatch{entry: entry, at: at})
+	}
+	date := now.Format(logDateLayout)
+	for _, level := range logview.Levels {
+		name := filepath.Join(date, level+".log")
+		info, statErr := os.Stat(filepath.Join(l.director(), name))
+		if f := files[level]; f != nil {
+			switch {
+			case f.name != name:
+				l.drain(f.name, f.offset, collect)
+			case statErr != nil || !os.SameFile(f.info, info):
+				if rotated := l.rotated(f.name, level); rotated != "" {
+					l.drain(rotated, f.offset, collect)
+				}
+			case info.Size() >= f.offset:
+				f.info = info
+				if info.Size() > f.offset {
+					f.offset, _ = l.read(name, f.offset, collect)
+				}
+				continue
+			}
+			delete(files, level)
+		}
+		if statErr != nil {
+			continue
+		}
+		f := &logTailFile{name: name, info: info}
+		f.offset, _ = l.read(name, 0, collect)
+		files[level] = f
+	}
+	return
+}
+
+// drain 读完已不再写入的文件 可能已被压缩
+func (l LogService) drain(name string, offset int64, fn func(resModel.LogEntry, time.Time)) {
+	if _, err := os.Stat(filepath.Join(l.director(), name)); err != nil {
+		name += ".gz"
+	}
+	_, _ = l.read(name, offset, fn)
+}
+
+// rotated 按大小切分后最新的文件 即 级别.时分秒[.n].log[.gz] 中修改时间最晚的
+func (l LogService) rotated(name, level string) (found string) {
+	dir := filepath.Dir(name)
+	entries, err := os.ReadDir(filepath.Join(l.director(), dir))
+	if err != nil {
+		return
+	}
+	var latest time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), level+".") || e.Name() == level+".log" {
+			continue
+		}
+		if info, err := e.Info(); err == nil && info.ModTime().After(latest) {
+			found, latest = filepath.Join(dir, strings.TrimSuffix(e.Name(), ".gz")), info.ModTime()
+		}
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"dataPanel/serviceend/model/reqModel"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogLevelRevert(t *testing.T) {
+	global.GvaLog = zap.NewNop()
+	global.GvaConfig.Zap = &configModel.Zap{Level: "info"}
+	l := LogService{}
+	t.Cleanup(l.ResetLevel)
+
+	// 到期后恢复为配置级别
+	if _, err := l.SetLevel(reqModel.LogLevelReq{Level: "debug", Duration: "20ms"}); err != nil {
+		t.Fatal(err)
+	}
+	if res := l.Level(); res.Level != "debug" || res.RevertAt == "" {
+		t.Fatalf("level = %+v", res)
+	}
+	deadline := time.Now().Add(time.Second)
+	for global.GvaLogLevel.Level() != zapcore.InfoLevel && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if res := l.Level(); res.Level != "info" || res.RevertAt != "" {
+		t.Fatalf("level after revert = %+v", res)
+	}
+
+	// 已触发的旧恢复不能覆盖之后的修改
+	if _, err := l.SetLevel(reqModel.LogLevelReq{Level: "debug", Duration: "1h"}); err != nil {
+		t.Fatal(err)
+	}
+	logLevelRevert.Lock()
+	stale := logLevelRevert.gen
+	logLevelRevert.Unlock()
+	if _, err := l.SetLevel(reqModel.LogLevelReq{Level: "error"}); err != nil {
+		t.Fatal(err)
+	}
+	l.revert(stale)
+	if level := global.GvaLogLevel.Level(); level != zapcore.ErrorLevel {
+		t.Errorf("level after stale revert = %s, want error", level)
+	}
+}
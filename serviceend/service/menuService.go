@@ -0,0 +1,127 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type MenuService struct{}
+
+// Tree 全部菜单树
+func (m MenuService) Tree() ([]dbModel.SysMenu, error) {
+	var menus []dbModel.SysMenu
+	if err := global.GvaDb.Order("sort, id").Find(&menus).Error; err != nil {
+		return nil, err
+	}
+	return buildMenuTree(menus, 0), nil
+}
+
+// UserTree 用户可见的菜单树 子菜单有权限时自动带出其上级菜单
+func (m MenuService) UserTree(userId uint) ([]dbModel.SysMenu, error) {
+	ids, all := ServiceGroupApp.PermissionService.UserMenuIds(userId)
+	if all {
+		return m.Tree()
+	}
+	if len(ids) == 0 {
+		return []dbModel.SysMenu{}, nil
+	}
+	var menus []dbModel.SysMenu
+	if err := global.GvaDb.Order("sort, id").Find(&menus).Error; err != nil {
+		return nil, err
+	}
+	byId := make(map[uint]dbModel.SysMenu, len(menus))
+	for _, menu := range menus {
+		byId[menu.ID] = menu
+	}
+	visible := make(map[uint]struct{}, len(ids))
+	for id := range ids {
+		for current, ok := byId[id]; ok; current, ok = byId[current.ParentId] {
+			if _, seen := visible[current.ID]; seen {
+				break
+			}
+			visible[current.ID] = struct{}{}
+		}
+	}
+	filtered := make([]dbModel.SysMenu, 0, len(visible))
+	for _, menu := range menus {
+		if _, ok := visible[menu.ID]; ok {
+			filtered = append(filtered, menu)
+		}
+	}
+	return buildMenuTree(filtered, 0), nil
+}
+
+// Save 新增/修改菜单 菜单name不可重复
+func (m MenuService) Save(req reqModel.MenuReq) (menu dbModel.SysMenu, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysMenu{}).Where("name = ? AND id <> ?", req.Name, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return menu, ApiReturn.ExistingMenuName
+	}
+	if req.ID != 0 {
+		if err = global.GvaDb.First(&menu, req.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return menu, ApiReturn.NoData
+			}
+			return
+		}
+	}
+	// 上级菜单必须存在 且不能移动到自身的子孙菜单下
+	for parentId := req.ParentId; parentId != 0; {
+		var parent dbModel.SysMenu
+		if err = global.GvaDb.First(&parent, parentId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return menu, ApiReturn.ErrParam.WithData("上级菜单不存在")
+			}
+			return
+		}
+		if req.ID != 0 && parent.ID == req.ID {
+			return menu, ApiReturn.ErrParam.WithData("上级菜单不能是自身或子菜单")
+		}
+		parentId = parent.ParentId
+	}
+	menu.ParentId, menu.Name, menu.Title, menu.Path = req.ParentId, req.Name, req.Title, req.Path
+	menu.Component, menu.Icon, menu.Sort, menu.Hidden = req.Component, req.Icon, req.Sort, req.Hidden
+	err = global.GvaDb.Save(&menu).Error
+	return
+}
+
+// Delete 删除菜单 存在子菜单时不允许删除
+func (m MenuService) Delete(id uint) error {
+	var count int64
+	if err := global.GvaDb.Model(&dbModel.SysMenu{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ApiReturn.ErrParam.WithData("请先删除子菜单")
+	}
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM sys_role_menus WHERE sys_menu_id = ?", id).Error; err != nil {
+			return err
+		}
+		// 物理删除 菜单name唯一索引包含已软删除的行
+		return tx.Unscoped().Delete(&dbModel.SysMenu{}, id).Error
+	})
+	if err != nil {
+		return err
+	}
+	return ServiceGroupApp.PermissionService.ReloadPolicy()
+}
+
+func buildMenuTree(menus []dbModel.SysMenu, parentId uint) []dbModel.SysMenu {
+	tree := make([]dbModel.SysMenu, 0)
+	for _, menu := range menus {
+		if menu.ParentId == parentId {
+			menu.Children = buildMenuTree(menus, menu.ID)
+			tree = append(tree, menu)
+		}
+	}
+	return tree
+}
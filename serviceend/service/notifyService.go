@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/notify"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 通知事件 渠道按前缀订阅, 如 alert 订阅 alert.fire 与 alert.resolve
+const (
+	NotifyAlertFire    = "alert.fire"    // 告警触发
+	NotifyAlertResolve = "alert.resolve" // 告警恢复
+	NotifyJobFailed    = "job.failed"    // 任务重试后仍失败
+	NotifyTest         = "test"          // 测试渠道
+)
+
+const (
+	notifyCloseTimeout = 5 * time.Second // 退出时等待发送中通知的时间
+	notifyErrorMaxLen  = 1024            // 投递记录中错误信息的最大长度
+)
+
+// notifySender 发送中的通知 退出时等待完成, 超时后中断重试等待
+var notifySender = struct {
+	sync.Mutex
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed bool // 已关闭 不再接受新的通知
+}{}
+
+func init() {
+	notifySender.ctx, notifySender.cancel = context.WithCancel(context.Background())
+}
+
+type NotifyService struct{}
+
+// notifyConfig 通知配置 未配置的项使用默认值
+type notifyConfig struct {
+	retries  int
+	backoff  time.Duration
+	timeout  time.Duration
+	channels []configModel.NotifyChannel
+}
+
+// Send 推送到订阅了该事件的已启用渠道 异步发送, 失败按配置重试
+func (n NotifyService) Send(msg notify.Message) {
+	cfg, err := n.config()
+	if err != nil {
+		global.GvaLog.Error("通知配置错误", zap.Error(err))
+		return
+	}
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
+	}
+	for _, c := range cfg.channels {
+		if !c.Enable || !n.subscribed(c, msg.Event) {
+			continue
+		}
+		channel, err := notify.NewChannel(c)
+		if err != nil {
+			global.GvaLog.Error("通知渠道配置错误", zap.String("channel", c.Name), zap.Error(err))
+			continue
+		}
+		if !n.add() {
+			global.GvaLog.Warn("通知服务已关闭 忽略通知", zap.String("event", msg.Event), zap.String("title", msg.Title))
+			return
+		}
+		go func(c configModel.NotifyChannel) {
+			defer notifySender.wg.Done()
+			_ = n.deliver(cfg, c, channel, msg, cfg.retries)
+		}(c)
+	}
+}
+
+// add 登记一个发送中的通知 已关闭时返回 false
+func (n NotifyService) add() bool {
+	notifySender.Lock()
+	defer notifySender.Unlock()
+	if notifySender.closed {
+		return false
+	}
+	notifySender.wg.Add(1)
+	return true
+}
+
+// Test 向指定渠道发送测试通知 未启用的渠道也可测试, 不重试
+func (n NotifyService) Test(name string) error {
+	cfg, err := n.config()
+	if err != nil {
+		return err
+	}
+	for _, c := range cfg.channels {
+		if c.Name != name {
+			continue
+		}
+		channel, err := notify.NewChannel(c)
+		if err != nil {
+			return ApiReturn.NotifyFailed.WithData(err.Error())
+		}
+		msg := notify.Message{Event: NotifyTest, Level: "info", Title: global.GvaConfig.System.ApplicationName + " 测试通知",
+			Content: "收到这条消息说明通知渠道 " + name + " 配置正确", Time: time.Now()}
+		if err = n.deliver(cfg, c, channel, msg, 0); err != nil {
+			return ApiReturn.NotifyFailed.WithData(err.Error())
+		}
+		return nil
+	}
+	return ApiReturn.NoNotifyChannel
+}
+
+// Channels 已配置的通知渠道 不含密钥与密码
+func (n NotifyService) Channels() ([]configModel.NotifyChannel, error) {
+	cfg, err := n.config()
+	return cfg.channels, err
+}
+
+// Logs 通知投递记录
+func (n NotifyService) Logs(req reqModel.NotifyLogListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysNotifyLog{})
+	if req.Channel != "" {
+		db = db.Where("channel = ?", req.Channel)
+	}
+	if req.Event != "" {
+		db = db.Where("event = ?", req.Event)
+	}
+	if req.Status != "" {
+		db = db.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		db = db.Where("title LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysNotifyLog
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Close 等待发送中的通知 超时后中断, 程序退出时调用 之后的 Send 不再发送
+func (n NotifyService) Close() {
+	notifySender.Lock()
+	notifySender.closed = true
+	notifySender.Unlock()
+	done := make(chan struct{})
+	go func() {
+		notifySender.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(notifyCloseTimeout):
+		global.GvaLog.Warn("通知未在限定时间内发送完成")
+	}
+	notifySender.cancel()
+}
+
+// deliver 发送并记录投递结果 失败时按退避时间重试
+func (n NotifyService) deliver(cfg notifyConfig, c configModel.NotifyChannel, channel notify.Channel, msg notify.Message, retries int) (err error) {
+	record := dbModel.SysNotifyLog{Channel: c.Name, Type: c.Type, Event: msg.Event, Title: msg.Title, Status: dbModel.NotifySending}
+	if err = global.GvaDb.Create(&record).Error; err != nil {
+		global.GvaLog.Error("通知投递记录保存失败", zap.String("channel", c.Name), zap.Error(err))
+	}
+	start := time.Now()
+	for attempt := 1; attempt <= retries+1; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-notifySender.ctx.Done():
+			case <-time.After(cfg.backoff << (attempt - 2)):
+			}
+		}
+		record.Attempts = attempt
+		ctx, cancel := context.WithTimeout(notifySender.ctx, cfg.timeout)
+		err = channel.Send(ctx, msg)
+		cancel()
+		if err == nil || notifySender.ctx.Err() != nil {
+			break
+		}
+		global.GvaLog.Warn("通知发送失败", zap.String("channel", c.Name), zap.String("event", msg.Event), zap.Int("attempt", attempt), zap.Error(err))
+	}
+	record.Status, record.Error = dbModel.NotifySuccess, ""
+	if err != nil {
+		record.Status, record.Error = dbModel.NotifyFailed, utils.Truncate(err.Error(), notifyErrorMaxLen)
+	}
+	record.Duration = time.Since(start).Milliseconds()
+	if record.ID != 0 {
+		if dbErr := global.GvaDb.Model(&record).Updates(map[string]interface{}{
+			"status":   record.Status,
+			"attempts": record.Attempts,
+			"duration": record.Duration,
+			"error":    record.Error,
+		}).Error; dbErr != nil {
+			global.GvaLog.Error("通知投递记录保存失败", zap.String("channel", c.Name), zap.Error(dbErr))
+		}
+	}
+	return
+}
+
+// subscribed 渠道是否订阅了事件 事件与订阅项相同或以 订阅项+"." 开头
+func (n NotifyService) subscribed(c configModel.NotifyChannel, event string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if event == e || strings.HasPrefix(event, e+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (n NotifyService) config() (cfg notifyConfig, err error) {
+	cfg = notifyConfig{retries: 3, backoff: 5 * time.Second, timeout: 10 * time.Second}
+	c := global.GvaConfig.Notify
+	if c == nil {
+		return
+	}
+	if c.Retries > 0 {
+		cfg.retries = c.Retries
+	}
+	if c.Backoff != "" {
+		if cfg.backoff, err = utils.ParseDuration(c.Backoff); err != nil {
+			return
+		}
+	}
+	if c.Timeout != "" {
+		if cfg.timeout, err = utils.ParseDuration(c.Timeout); err != nil {
+			return
+		}
+	}
+	cfg.channels = c.Channels
+	return
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/configModel"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/utils/notify"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setupNotify(t *testing.T, channels ...configModel.NotifyChannel) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "notify.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&dbModel.SysNotifyLog{}); err != nil {
+		t.Fatal(err)
+	}
+	global.GvaDb, global.GvaLog = db, zap.NewNop()
+	global.GvaConfig.System = &configModel.System{ApplicationName: "dataPanel"}
+	global.GvaConfig.Notify = &configModel.Notify{Retries: 2, Backoff: "10ms", Timeout: "1s", Channels: channels}
+}
+
+// newFlakyWebhook 前 failures 次请求返回 500 之后返回 200
+func newFlakyWebhook(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(notify.HeaderSignature) == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if hits.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestNotifyDeliver(t *testing.T) {
+	recovering, recoveringHits := newFlakyWebhook(t, 2)
+	broken, brokenHits := newFlakyWebhook(t, 100)
+	unsubscribed, unsubscribedHits := newFlakyWebhook(t, 0)
+	setupNotify(t,
+		configModel.NotifyChannel{Name: "recovering", Type: notify.TypeWebhook, Enable: true, Url: recovering.URL, Secret: "k"},
+		configModel.NotifyChannel{Name: "broken", Type: notify.TypeWebhook, Enable: true, Url: broken.URL, Secret: "k", Events: []string{"alert"}},
+		configModel.NotifyChannel{Name: "unsubscribed", Type: notify.TypeWebhook, Enable: true, Url: unsubscribed.URL, Secret: "k", Events: []string{"job"}},
+	)
+	n := NotifyService{}
+	n.Send(notify.Message{Event: NotifyAlertFire, Level: "error", Title: "告警"})
+	notifySender.wg.Wait()
+
+	if got := recoveringHits.Load(); got != 3 {
+		t.Errorf("recovering hits = %d, want 3", got)
+	}
+	if got := brokenHits.Load(); got != 3 {
+		t.Errorf("broken hits = %d, want 3", got)
+	}
+	if got := unsubscribedHits.Load(); got != 0 {
+		t.Errorf("unsubscribed hits = %d, want 0", got)
+	}
+	var logs []dbModel.SysNotifyLog
+	if err := global.GvaDb.Order("channel").Find(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		status   string
+		attempts int
+	}{
+		"broken":     {dbModel.NotifyFailed, 3},
+		"recovering": {dbModel.NotifySuccess, 3},
+	}
+	if len(logs) != len(want) {
+		t.Fatalf("logs = %+v", logs)
+	}
+	for _, log := range logs {
+		w := want[log.Channel]
+		if log.Status != w.status || log.Attempts != w.attempts || log.Event != NotifyAlertFire || log.Title != "告警" {
+			t.Errorf("log %s = %+v, want %+v", log.Channel, log, w)
+		}
+		if (log.Error == "") != (w.status == dbModel.NotifySuccess) {
+			t.Errorf("log %s error = %q", log.Channel, log.Error)
+		}
+	}
+
+	// 测试通知不重试
+	if err := n.Test("broken"); err == nil {
+		t.Error("test on broken channel succeeded")
+	}
+	if got := brokenHits.Load(); got != 4 {
+		t.Errorf("broken hits after test = %d, want 4", got)
+	}
+
+	// 关闭后不再发送
+	n.Close()
+	n.Send(notify.Message{Event: NotifyAlertFire, Title: "关闭后"})
+	notifySender.wg.Wait()
+	if got := recoveringHits.Load(); got != 3 {
+		t.Errorf("hits after close = %d, want 3", got)
+	}
+	var count int64
+	global.GvaDb.Model(&dbModel.SysNotifyLog{}).Where("title = ?", "关闭后").Count(&count)
+	if count != 0 {
+		t.Errorf("logs after close = %d, want 0", count)
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"errors"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PermissionService struct{}
+
+// policy 权限策略快照 角色/权限/用户角色变更后整体重建
+type policy struct {
+	userRoles  map[uint][]uint              // 用户ID => 可用角色ID
+	superRoles map[uint]struct{}            // 超级管理员角色ID
+	rolePerms  map[uint]map[string]struct{} // 角色ID => METHOD path
+	roleMenus  map[uint][]uint              // 角色ID => 菜单ID
+	roleScope  map[uint]int                 // 角色ID => 数据权限范围
+}
+
+var policyHolder atomic.Pointer[policy]
+
+func policyKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// ReloadPolicy 从数据库重建权限策略 相关数据变更后调用即时生效
+func (p PermissionService) ReloadPolicy() error {
+	pl := &policy{
+		userRoles:  map[uint][]uint{},
+		superRoles: map[uint]struct{}{},
+		rolePerms:  map[uint]map[string]struct{}{},
+		roleMenus:  map[uint][]uint{},
+		roleScope:  map[uint]int{},
+	}
+	var roles []dbModel.SysRole
+	if err := global.GvaDb.Where("status = ?", dbModel.RoleEnable).Find(&roles).Error; err != nil {
+		return err
+	}
+	enabled := make(map[uint]struct{}, len(roles))
+	for _, role := range roles {
+		enabled[role.ID] = struct{}{}
+		pl.roleScope[role.ID] = role.DataScope
+		if role.RoleKey == dbModel.RoleKeySuperAdmin {
+			pl.superRoles[role.ID] = struct{}{}
+		}
+	}
+
+	var userRoles []struct{ SysUserId, SysRoleId uint }
+	if err := global.GvaDb.Table("sys_user_roles").Find(&userRoles).Error; err != nil {
+		return err
+	}
+	for _, ur := range userRoles {
+		if _, ok := enabled[ur.SysRoleId]; ok {
+			pl.userRoles[ur.SysUserId] = append(pl.userRoles[ur.SysUserId], ur.SysRoleId)
+		}
+	}
+
+	var rolePerms []struct {
+		RoleId uint
+		Path   string
+		Method string
+	}
+	if err := global.GvaDb.Table("sys_role_permissions rp").
+		Select("rp.sys_role_id AS role_id, p.path, p.method").
+		Joins("JOIN sys_permissions p ON p.id = rp.sys_permission_id AND p.deleted_at IS NULL").
+		Find(&rolePerms).Error; err != nil {
+		return err
+	}
+	for _, rp := range rolePerms {
+		if pl.rolePerms[rp.RoleId] == nil {
+			pl.rolePerms[rp.RoleId] = map[string]struct{}{}
+		}
+		pl.rolePerms[rp.RoleId][policyKey(rp.Method, rp.Path)] = struct{}{}
+	}
+
+	var roleMenus []struct{ SysRoleId, SysMenuId uint }
+	if err := global.GvaDb.Table("sys_role_menus").Find(&roleMenus).Error; err != nil {
+		return err
+	}
+	for _, rm := range roleMenus {
+		pl.roleMenus[rm.SysRoleId] = append(pl.roleMenus[rm.SysRoleId], rm.SysMenuId)
+	}
+
+	policyHolder.Store(pl)
+	global.GvaLog.Info("权限策略加载完成", zap.Int("roles", len(roles)), zap.Int("policies", len(rolePerms)))
+	return nil
+}
+
+func (p PermissionService) current() *policy {
+	pl := policyHolder.Load()
+	if pl == nil {
+		if err := p.ReloadPolicy(); err != nil {
+			global.GvaLog.Error("权限策略加载失败", zap.Error(err))
+			return &policy{}
+		}
+		pl = policyHolder.Load()
+	}
+	return pl
+}
+
+// IsSuperAdmin 用户是否拥有超级管理员角色
+func (p PermissionService) IsSuperAdmin(userId uint) bool {
+	pl := p.current()
+	for _, roleId := range pl.userRoles[userId] {
+		if _, ok := pl.superRoles[roleId]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Enforce 校验用户是否拥有 路由+请求方法 的访问权限
+func (p PermissionService) Enforce(userId uint, method, path string) bool {
+	pl := p.current()
+	key := policyKey(method, path)
+	for _, roleId := range pl.userRoles[userId] {
+		if _, ok := pl.superRoles[roleId]; ok {
+			return true
+		}
+		if _, ok := pl.rolePerms[roleId][key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DataScope 用户的数据权限范围 多个角色取范围最大者 无角色时仅本人
+func (p PermissionService) DataScope(userId uint) int {
+	pl := p.current()
+	scope := dbModel.DataScopeSelf
+	for _, roleId := range pl.userRoles[userId] {
+		if _, ok := pl.superRoles[roleId]; ok {
+			return dbModel.DataScopeAll
+		}
+		if s := pl.roleScope[roleId]; s > 0 && s < scope {
+			scope = s
+		}
+	}
+	return scope
+}
+
+// UserMenuIds 用户可见的菜单ID 超级管理员返回 all=true
+func (p PermissionService) UserMenuIds(userId uint) (ids map[uint]struct{}, all bool) {
+	pl := p.current()
+	ids = map[uint]struct{}{}
+	for _, roleId := range pl.userRoles[userId] {
+		if _, ok := pl.superRoles[roleId]; ok {
+			return nil, true
+		}
+		for _, menuId := range pl.roleMenus[roleId] {
+			ids[menuId] = struct{}{}
+		}
+	}
+	return ids, false
+}
+
+var handlerGroupReg = regexp.MustCompile(`\(\*(\w+)Controller\)`)
+
+// SyncRoutes 将已注册的路由同步到接口权限表 只新增不覆盖已有描述
+// 已软删除的同名接口恢复而不是新增, 唯一索引包含已删除的行
+func (p PermissionService) SyncRoutes(routes gin.RoutesInfo, prefix string) error {
+	var exists []dbModel.SysPermission
+	if err := global.GvaDb.Unscoped().Find(&exists).Error; err != nil {
+		return err
+	}
+	known := make(map[string]dbModel.SysPermission, len(exists))
+	for _, e := range exists {
+		known[policyKey(e.Method, e.Path)] = e
+	}
+	var added []dbModel.SysPermission
+	var restored []uint
+	for _, r := range routes {
+		path := strings.TrimPrefix(r.Path, prefix)
+		if e, ok := known[policyKey(r.Method, path)]; ok {
+			if e.DeletedAt.Valid {
+				restored = append(restored, e.ID)
+				e.DeletedAt.Valid = false
+				known[policyKey(r.Method, path)] = e
+			}
+			continue
+		}
+		group := ""
+		if match := handlerGroupReg.FindStringSubmatch(r.Handler); match != nil {
+			group = strings.ToLower(match[1])
+		}
+		added = append(added, dbModel.SysPermission{Path: path, Method: r.Method, ApiGroup: group})
+		known[policyKey(r.Method, path)] = dbModel.SysPermission{}
+	}
+	if len(added) == 0 && len(restored) == 0 {
+		return nil
+	}
+	if len(restored) > 0 {
+		if err := global.GvaDb.Unscoped().Model(&dbModel.SysPermission{}).Where("id IN ?", restored).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+	}
+	if len(added) > 0 {
+		if err := global.GvaDb.Create(&added).Error; err != nil {
+			return err
+		}
+	}
+	global.GvaLog.Info("接口权限同步完成", zap.Int("added", len(added)), zap.Int("restored", len(restored)))
+	return nil
+}
+
+// List 分页查询接口权限
+func (p PermissionService) List(info reqModel.PageInfo) (res resModel.PageResult, err error) {
+	limit, offset := info.Limit()
+	db := global.GvaDb.Model(&dbModel.SysPermission{})
+	if info.Keyword != "" {
+		like := "%" + info.Keyword + "%"
+		db = db.Where("path LIKE ? OR description LIKE ? OR api_group LIKE ?", like, like, like)
+	}
+	var list []dbModel.SysPermission
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Order("api_group, path").Limit(limit).Offset(offset).Find(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Save 新增/修改接口权限
+func (p PermissionService) Save(req reqModel.PermissionReq) (perm dbModel.SysPermission, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysPermission{}).Where("path = ? AND method = ? AND id <> ?", req.Path, req.Method, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return perm, ApiReturn.ErrParam.WithData("接口权限已存在")
+	}
+	if req.ID != 0 {
+		if err = global.GvaDb.First(&perm, req.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return perm, ApiReturn.NoData
+			}
+			return
+		}
+	}
+	perm.Path, perm.Method, perm.ApiGroup, perm.Description = req.Path, req.Method, req.ApiGroup, req.Description
+	if err = global.GvaDb.Save(&perm).Error; err != nil {
+		return
+	}
+	return perm, p.ReloadPolicy()
+}
+
+// Delete 删除接口权限 同时解除角色授权
+func (p PermissionService) Delete(ids []uint) error {
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM sys_role_permissions WHERE sys_permission_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		// 物理删除 路由+请求方法唯一索引包含已软删除的行, 否则重启后无法重新同步
+		return tx.Unscoped().Delete(&dbModel.SysPermission{}, ids).Error
+	})
+	if err != nil {
+		return err
+	}
+	return p.ReloadPolicy()
+}
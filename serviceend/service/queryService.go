@@ -0,0 +1,545 @@
+package service
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/datasource"
+	"database/sql"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 查询推送事件
+const (
+	EventQueryChunk = "query:chunk" // 流式查询的一批结果
+	EventQueryDone  = "query:done"  // 查询结束
+)
+
+// queryErrorMaxLen 查询记录中错误信息的最大长度
+const queryErrorMaxLen = 1024
+
+// errQueryClosed 查询已结束(完成/取消/超时)后继续读取
+var errQueryClosed = errors.New("query closed")
+
+type QueryService struct{}
+
+// queryConfig 查询配置 未配置的项使用默认值
+type queryConfig struct {
+	timeout       time.Duration
+	pageSize      int
+	maxRows       int
+	idleExpire    time.Duration
+	exportTimeout time.Duration
+	exportMaxRows int
+}
+
+// queryOptions 执行保存的查询时的附加参数
+type queryOptions struct {
+	args         []interface{} // 绑定参数
+	savedQueryId uint          // 保存的查询ID
+	maxRows      int           // 行数上限 小于配置时生效
+}
+
+// queryTasks 执行中的查询 按查询ID索引
+var queryTasks = struct {
+	sync.Mutex
+	tasks map[string]*queryTask
+}{tasks: map[string]*queryTask{}}
+
+// queryTask 一次查询的执行状态 结果集的读取与关闭通过 mu 串行
+type queryTask struct {
+	mu        sync.Mutex
+	id        string
+	userId    uint
+	logId     uint
+	stream    bool // 流式查询 结果通过事件推送
+	readOnly  bool // 数据源不可写 使用只读事务
+	db        *sql.DB
+	stmt      datasource.Statement
+	args      []interface{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+	idle      *time.Timer
+	idleTime  time.Duration
+	tx        *sql.Tx
+	rows      *sql.Rows
+	columns   []resModel.QueryColumn
+	maxRows   int
+	count     int64
+	start     time.Time
+	done      bool
+	result    resModel.QueryDone
+}
+
+// abort 取消查询 由结束回调关闭结果集并记录
+func (t *queryTask) abort() {
+	t.cancelled.Store(true)
+	t.cancel()
+}
+
+// Run 执行查询并返回首页结果 未读完的结果通过 Next 继续获取 写语句返回影响行数
+func (q QueryService) Run(userId uint, req reqModel.QueryRunReq) (page resModel.QueryPage, err error) {
+	return q.run(userId, req, queryOptions{})
+}
+
+// RunAsync 后台执行查询 结果按批推送 query:chunk 事件, 结束后推送 query:done 事件
+func (q QueryService) RunAsync(userId uint, req reqModel.QueryRunReq) (queryId string, err error) {
+	return q.runAsync(userId, req, queryOptions{})
+}
+
+func (q QueryService) run(userId uint, req reqModel.QueryRunReq, opts queryOptions) (page resModel.QueryPage, err error) {
+	cfg, err := q.config()
+	if err != nil {
+		return
+	}
+	task, err := q.prepare(userId, req, opts, cfg, false)
+	if err != nil {
+		return
+	}
+	if err = q.start(task); err != nil {
+		return page, q.failure(q.finish(task, err))
+	}
+	page, err = q.page(task, q.pageSize(cfg, req.PageSize))
+	page.Columns = task.columns
+	return
+}
+
+func (q QueryService) runAsync(userId uint, req reqModel.QueryRunReq, opts queryOptions) (queryId string, err error) {
+	cfg, err := q.config()
+	if err != nil {
+		return
+	}
+	task, err := q.prepare(userId, req, opts, cfg, true)
+	if err != nil {
+		return
+	}
+	go q.stream(task, q.pageSize(cfg, req.PageSize))
+	return task.id, nil
+}
+
+// export 导出查询结果 按批读取并交给 fn 写出, 首批携带列信息
+// 使用导出的超时与行数上限, fn 返回错误时结束查询并返回该错误
+func (q QueryService) export(userId uint, req reqModel.QueryRunReq, opts queryOptions, fn func(columns []resModel.QueryColumn, rows [][]interface{}) error) (result resModel.QueryDone, err error) {
+	cfg, err := q.config()
+	if err != nil {
+		return
+	}
+	cfg.timeout, cfg.maxRows = cfg.exportTimeout, cfg.exportMaxRows
+	task, err := q.prepare(userId, req, opts, cfg, true)
+	if err != nil {
+		return
+	}
+	var writeErr error
+	err = q.start(task)
+	for first := true; err == nil; first = false {
+		var page resModel.QueryPage
+		if page, err = q.fetch(task, q.pageSize(cfg, req.PageSize)); err != nil {
+			break
+		}
+		if first || len(page.Rows) > 0 {
+			if writeErr = fn(task.columns, page.Rows); writeErr != nil {
+				err = writeErr
+				break
+			}
+		}
+		if page.Done {
+			break
+		}
+	}
+	result = q.finish(task, err)
+	if writeErr != nil {
+		return result, writeErr
+	}
+	if result.Status != dbModel.QuerySuccess {
+		return result, q.failure(result)
+	}
+	return result, nil
+}
+
+// Next 获取下一页结果 结果读完后查询自动结束
+func (q QueryService) Next(userId uint, queryId string, req reqModel.QueryNextReq) (page resModel.QueryPage, err error) {
+	cfg, err := q.config()
+	if err != nil {
+		return
+	}
+	task, err := q.task(userId, queryId)
+	if err != nil {
+		return
+	}
+	if task.stream {
+		return page, ApiReturn.ErrParam.WithData("流式查询的结果通过事件推送")
+	}
+	task.idle.Reset(task.idleTime)
+	return q.page(task, q.pageSize(cfg, req.PageSize))
+}
+
+// Cancel 取消执行中的查询
+func (q QueryService) Cancel(userId uint, queryId string) error {
+	task, err := q.task(userId, queryId)
+	if err != nil {
+		return err
+	}
+	task.abort()
+	return nil
+}
+
+// CancelAll 取消全部执行中的查询 程序退出时调用
+func (q QueryService) CancelAll() {
+	queryTasks.Lock()
+	tasks := make([]*queryTask, 0, len(queryTasks.tasks))
+	for _, task := range queryTasks.tasks {
+		tasks = append(tasks, task)
+	}
+	queryTasks.Unlock()
+	for _, task := range tasks {
+		task.abort()
+		q.finish(task, nil)
+	}
+}
+
+// Logs 查询执行记录
+func (q QueryService) Logs(req reqModel.QueryLogListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysQueryLog{})
+	if req.DataSourceId != 0 {
+		db = db.Where("data_source_id = ?", req.DataSourceId)
+	}
+	if req.SavedQueryId != 0 {
+		db = db.Where("saved_query_id = ?", req.SavedQueryId)
+	}
+	if req.Status != "" {
+		db = db.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		db = db.Where("sql LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysQueryLog
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// prepare 校验语句、获取连接并登记查询 超时或取消后自动结束
+func (q QueryService) prepare(userId uint, req reqModel.QueryRunReq, opts queryOptions, cfg queryConfig, stream bool) (*queryTask, error) {
+	db, source, err := ServiceGroupApp.DataSourceService.DB(req.DataSourceId)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := datasource.ParseStatement(source.Type, req.Sql)
+	if err != nil {
+		return nil, ApiReturn.ErrParam.WithData(err.Error())
+	}
+	if !stmt.ReadOnly && !source.Writable {
+		return nil, ApiReturn.QueryReadOnly
+	}
+	log := dbModel.SysQueryLog{
+		QueryId:      uuid.NewString(),
+		DataSourceId: source.ID,
+		SavedQueryId: opts.savedQueryId,
+		Sql:          req.Sql,
+		Status:       dbModel.QueryRunning,
+		CreatedBy:    userId,
+	}
+	if err = global.GvaDb.Create(&log).Error; err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	task := &queryTask{
+		id:       log.QueryId,
+		userId:   userId,
+		logId:    log.ID,
+		stream:   stream,
+		readOnly: !source.Writable,
+		db:       db,
+		stmt:     stmt,
+		args:     opts.args,
+		ctx:      ctx,
+		cancel:   cancel,
+		idleTime: cfg.idleExpire,
+		maxRows:  cfg.maxRows,
+		start:    time.Now(),
+	}
+	if opts.maxRows > 0 && (task.maxRows == 0 || opts.maxRows < task.maxRows) {
+		task.maxRows = opts.maxRows
+	}
+	if !stream {
+		// 分页查询闲置超时后释放连接
+		task.idle = time.AfterFunc(cfg.idleExpire, task.abort)
+	}
+	queryTasks.Lock()
+	queryTasks.tasks[task.id] = task
+	queryTasks.Unlock()
+	context.AfterFunc(ctx, func() {
+		q.finish(task, nil)
+	})
+	return task, nil
+}
+
+// start 开始执行 查询语句在事务中打开结果集, 写语句直接执行
+func (q QueryService) start(task *queryTask) error {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	if task.done {
+		return errQueryClosed
+	}
+	if !task.stmt.ReadOnly {
+		res, err := task.db.ExecContext(task.ctx, task.stmt.Sql, task.args...)
+		if err != nil {
+			return err
+		}
+		task.count, _ = res.RowsAffected()
+		return nil
+	}
+	tx, err := task.db.BeginTx(task.ctx, &sql.TxOptions{ReadOnly: task.readOnly})
+	if err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(task.ctx, task.stmt.Sql, task.args...)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		_ = rows.Close()
+		_ = tx.Rollback()
+		return err
+	}
+	task.columns = make([]resModel.QueryColumn, len(types))
+	for i, t := range types {
+		task.columns[i] = resModel.QueryColumn{Name: t.Name(), Type: t.DatabaseTypeName()}
+	}
+	task.tx, task.rows = tx, rows
+	return nil
+}
+
+// fetch 读取至多 size 行 结果集读完或达到行数上限时 Done 为 true
+func (q QueryService) fetch(task *queryTask, size int) (page resModel.QueryPage, err error) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	if task.done {
+		return page, errQueryClosed
+	}
+	page.QueryId, page.Rows = task.id, make([][]interface{}, 0)
+	defer func() {
+		page.RowCount, page.Duration = task.count, time.Since(task.start).Milliseconds()
+	}()
+	if task.rows == nil {
+		page.Done = true
+		return
+	}
+	for len(page.Rows) < size {
+		// 已取消/超时的查询不再读取 结果集由结束回调关闭
+		if err = task.ctx.Err(); err != nil {
+			return
+		}
+		if task.maxRows > 0 && task.count >= int64(task.maxRows) {
+			page.Done = true
+			return
+		}
+		if !task.rows.Next() {
+			page.Done = true
+			return page, task.rows.Err()
+		}
+		values := make([]interface{}, len(task.columns))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = task.rows.Scan(dest...); err != nil {
+			return
+		}
+		for i, v := range values {
+			values[i] = q.convert(v)
+		}
+		page.Rows = append(page.Rows, values)
+		task.count++
+	}
+	// 恰好读满行数上限时结束 无需再读取一次
+	page.Done = task.maxRows > 0 && task.count >= int64(task.maxRows)
+	return
+}
+
+// page 读取一页 读完或出错时结束查询
+func (q QueryService) page(task *queryTask, size int) (page resModel.QueryPage, err error) {
+	page, err = q.fetch(task, size)
+	if errors.Is(err, errQueryClosed) {
+		return page, ApiReturn.QueryNotFound
+	}
+	if err == nil && !page.Done {
+		return
+	}
+	result := q.finish(task, err)
+	if result.Status != dbModel.QuerySuccess {
+		return page, q.failure(result)
+	}
+	page.Done, page.Duration = true, result.Duration
+	return page, nil
+}
+
+// stream 流式查询 按批推送结果
+func (q QueryService) stream(task *queryTask, size int) {
+	err := q.start(task)
+	for seq := 0; err == nil; seq++ {
+		var page resModel.QueryPage
+		if page, err = q.fetch(task, size); err != nil {
+			break
+		}
+		if len(page.Rows) > 0 || (seq == 0 && len(task.columns) > 0) {
+			chunk := resModel.QueryChunk{QueryId: task.id, Seq: seq, Rows: page.Rows}
+			if seq == 0 {
+				chunk.Columns = task.columns
+			}
+			ServiceGroupApp.EventService.Emit(EventQueryChunk, chunk)
+		}
+		if page.Done {
+			break
+		}
+	}
+	ServiceGroupApp.EventService.Emit(EventQueryDone, q.finish(task, err))
+}
+
+// finish 关闭结果集、释放连接并更新执行记录 重复调用返回首次的结果
+func (q QueryService) finish(task *queryTask, err error) resModel.QueryDone {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	if task.done {
+		return task.result
+	}
+	task.done = true
+	if task.idle != nil {
+		task.idle.Stop()
+	}
+	if task.rows != nil {
+		_ = task.rows.Close()
+	}
+	if task.tx != nil {
+		_ = task.tx.Rollback()
+	}
+	status := dbModel.QuerySuccess
+	switch {
+	case errors.Is(task.ctx.Err(), context.DeadlineExceeded):
+		status = dbModel.QueryTimeout
+	case task.cancelled.Load() || errors.Is(task.ctx.Err(), context.Canceled):
+		status = dbModel.QueryCancelled
+	case err != nil:
+		status = dbModel.QueryFailed
+	}
+	task.cancel()
+	queryTasks.Lock()
+	delete(queryTasks.tasks, task.id)
+	queryTasks.Unlock()
+	task.result = resModel.QueryDone{
+		QueryId:  task.id,
+		Status:   status,
+		RowCount: task.count,
+		Duration: time.Since(task.start).Milliseconds(),
+	}
+	if err != nil && status == dbModel.QueryFailed {
+		task.result.Error = utils.Truncate(err.Error(), queryErrorMaxLen)
+	}
+	if err := global.GvaDb.Model(&dbModel.SysQueryLog{}).Where("id = ?", task.logId).Updates(map[string]interface{}{
+		"status":    task.result.Status,
+		"row_count": task.result.RowCount,
+		"duration":  task.result.Duration,
+		"error":     task.result.Error,
+	}).Error; err != nil {
+		global.GvaLog.Warn("查询记录更新失败", zap.String("queryId", task.id), zap.Error(err))
+	}
+	return task.result
+}
+
+// task 获取执行中的查询 只能操作自己发起的查询
+func (q QueryService) task(userId uint, queryId string) (*queryTask, error) {
+	queryTasks.Lock()
+	defer queryTasks.Unlock()
+	task, ok := queryTasks.tasks[queryId]
+	if !ok || task.userId != userId {
+		return nil, ApiReturn.QueryNotFound
+	}
+	return task, nil
+}
+
+// failure 查询结束状态转换为业务错误
+func (q QueryService) failure(result resModel.QueryDone) error {
+	switch result.Status {
+	case dbModel.QueryTimeout:
+		return ApiReturn.QueryTimeout
+	case dbModel.QueryCancelled:
+		return ApiReturn.QueryFailed.WithData("查询已取消")
+	}
+	return ApiReturn.QueryFailed.WithData(result.Error)
+}
+
+// convert 驱动返回值转换为可序列化的值
+func (q QueryService) convert(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(utils.TimeFormat)
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return strconv.FormatFloat(val, 'g', -1, 64)
+		}
+	}
+	return v
+}
+
+func (q QueryService) pageSize(cfg queryConfig, size int) int {
+	if size > 0 {
+		return size
+	}
+	return cfg.pageSize
+}
+
+func (q QueryService) config() (cfg queryConfig, err error) {
+	cfg = queryConfig{timeout: time.Minute, pageSize: 500, idleExpire: 10 * time.Minute, exportTimeout: 10 * time.Minute}
+	c := global.GvaConfig.Query
+	if c == nil {
+		return
+	}
+	if c.Timeout != "" {
+		if cfg.timeout, err = utils.ParseDuration(c.Timeout); err != nil {
+			return
+		}
+	}
+	if c.IdleExpire != "" {
+		if cfg.idleExpire, err = utils.ParseDuration(c.IdleExpire); err != nil {
+			return
+		}
+	}
+	if c.PageSize > 0 {
+		cfg.pageSize = c.PageSize
+	}
+	if c.MaxRows > 0 {
+		cfg.maxRows = c.MaxRows
+	}
+	if c.ExportTimeout != "" {
+		if cfg.exportTimeout, err = utils.ParseDuration(c.ExportTimeout); err != nil {
+			return
+		}
+	}
+	if c.ExportMaxRows > 0 {
+		cfg.exportMaxRows = c.ExportMaxRows
+	}
+	return
+}
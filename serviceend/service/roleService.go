@@ -0,0 +1,161 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type RoleService struct{}
+
+// List 分页查询角色
+func (r RoleService) List(info reqModel.PageInfo) (res resModel.PageResult, err error) {
+	limit, offset := info.Limit()
+	db := global.GvaDb.Model(&dbModel.SysRole{})
+	if info.Keyword != "" {
+		db = db.Where("role_name LIKE ?", "%"+info.Keyword+"%")
+	}
+	var list []dbModel.SysRole
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Order("sort, id").Limit(limit).Offset(offset).Find(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 角色详情 包含已授权的接口与菜单
+func (r RoleService) Get(id uint) (role dbModel.SysRole, err error) {
+	err = global.GvaDb.Preload("Permissions").Preload("Menus").First(&role, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return role, ApiReturn.NoData
+	}
+	return
+}
+
+// Save 新增/修改角色 角色名不可重复
+func (r RoleService) Save(req reqModel.RoleReq) (role dbModel.SysRole, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysRole{}).Where("role_name = ? AND id <> ?", req.RoleName, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return role, ApiReturn.RoleNameRepeat
+	}
+	if req.ID != 0 {
+		if err = global.GvaDb.First(&role, req.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return role, ApiReturn.NoData
+			}
+			return
+		}
+	}
+	// 未传状态与数据权限时 新增使用默认值, 修改保留原值
+	if req.ID == 0 {
+		role.Status, role.DataScope = dbModel.RoleEnable, dbModel.DataScopeAll
+	}
+	if req.Status != 0 {
+		role.Status = req.Status
+	}
+	if req.DataScope != 0 {
+		role.DataScope = req.DataScope
+	}
+	role.RoleName, role.RoleKey, role.Sort, role.Remark = req.RoleName, req.RoleKey, req.Sort, req.Remark
+	if err = global.GvaDb.Omit("Permissions", "Menus").Save(&role).Error; err != nil {
+		return
+	}
+	return role, ServiceGroupApp.PermissionService.ReloadPolicy()
+}
+
+// Delete 删除角色 同时解除用户、接口、菜单关联
+func (r RoleService) Delete(ids []uint) error {
+	err := global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"sys_user_roles", "sys_role_permissions", "sys_role_menus"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE sys_role_id IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		// 物理删除 角色名唯一索引包含已软删除的行
+		return tx.Unscoped().Delete(&dbModel.SysRole{}, ids).Error
+	})
+	if err != nil {
+		return err
+	}
+	return ServiceGroupApp.PermissionService.ReloadPolicy()
+}
+
+// SetPermissions 覆盖设置角色的接口权限
+func (r RoleService) SetPermissions(req reqModel.RoleAuthReq) error {
+	role, err := r.Get(req.RoleId)
+	if err != nil {
+		return err
+	}
+	var perms []dbModel.SysPermission
+	if len(req.Ids) > 0 {
+		if err = global.GvaDb.Find(&perms, req.Ids).Error; err != nil {
+			return err
+		}
+	}
+	if err = global.GvaDb.Model(&role).Association("Permissions").Replace(perms); err != nil {
+		return err
+	}
+	return ServiceGroupApp.PermissionService.ReloadPolicy()
+}
+
+// SetMenus 覆盖设置角色可见的菜单
+func (r RoleService) SetMenus(req reqModel.RoleAuthReq) error {
+	role, err := r.Get(req.RoleId)
+	if err != nil {
+		return err
+	}
+	var menus []dbModel.SysMenu
+	if len(req.Ids) > 0 {
+		if err = global.GvaDb.Find(&menus, req.Ids).Error; err != nil {
+			return err
+		}
+	}
+	if err = global.GvaDb.Model(&role).Association("Menus").Replace(menus); err != nil {
+		return err
+	}
+	return ServiceGroupApp.PermissionService.ReloadPolicy()
+}
+
+// SetUserRoles 覆盖设置用户角色 角色必须全部存在且可用
+func (r RoleService) SetUserRoles(req reqModel.UserRoleReq) error {
+	user, err := ServiceGroupApp.UserService.GetUserInfo(req.UserId)
+	if err != nil {
+		return err
+	}
+	var roles []dbModel.SysRole
+	if len(req.RoleIds) > 0 {
+		if err = global.GvaDb.Where("id IN ? AND status = ?", req.RoleIds, dbModel.RoleEnable).Find(&roles).Error; err != nil {
+			return err
+		}
+		if len(roles) != len(uniqueIds(req.RoleIds)) {
+			return ApiReturn.RoleSatatusNo
+		}
+	}
+	if err = global.GvaDb.Model(&user).Association("Roles").Replace(roles); err != nil {
+		return err
+	}
+	return ServiceGroupApp.PermissionService.ReloadPolicy()
+}
+
+// uniqueIds ID去重
+func uniqueIds(ids []uint) []uint {
+	seen := make(map[uint]struct{}, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils/datasource"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+)
+
+// queryParamDateLayout 日期参数格式
+const queryParamDateLayout = "2006-01-02"
+
+// queryParamNamePattern 参数名 与 SQL 中的 :name 一致
+var queryParamNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type SavedQueryService struct{}
+
+// List 保存的查询列表
+func (s SavedQueryService) List(req reqModel.SavedQueryListReq) (res resModel.PageResult, err error) {
+	limit, offset := req.Limit()
+	db := global.GvaDb.Model(&dbModel.SysSavedQuery{})
+	if req.DataSourceId != 0 {
+		db = db.Where("data_source_id = ?", req.DataSourceId)
+	}
+	if req.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+req.Keyword+"%")
+	}
+	var list []dbModel.SysSavedQuery
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	if err = db.Order("id").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return
+	}
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 保存的查询详情
+func (s SavedQueryService) Get(id uint) (query dbModel.SysSavedQuery, err error) {
+	err = global.GvaDb.First(&query, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return query, ApiReturn.NoSavedQuery
+	}
+	return
+}
+
+// Save 新增/修改保存的查询 校验参数定义与 SQL 中引用的参数一致
+func (s SavedQueryService) Save(userId uint, req reqModel.SavedQueryReq) (query dbModel.SysSavedQuery, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysSavedQuery{}).Where("name = ? AND id <> ?", req.Name, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return query, ApiReturn.SavedQueryNameExisted
+	}
+	params := make([]dbModel.QueryParam, len(req.Params))
+	for i, p := range req.Params {
+		params[i] = dbModel.QueryParam{
+			Name:     p.Name,
+			Label:    p.Label,
+			Type:     p.Type,
+			Required: p.Required,
+			Default:  p.Default,
+			Options:  p.Options,
+		}
+		if p.Type != dbModel.QueryParamEnum {
+			params[i].Options = nil
+		}
+	}
+	if err = s.check(req.DataSourceId, req.Sql, params); err != nil {
+		return
+	}
+	if req.ID != 0 {
+		if query, err = s.Get(req.ID); err != nil {
+			return
+		}
+	} else {
+		query.CreatedBy = userId
+	}
+	query.Name, query.DataSourceId, query.Sql, query.Params, query.Remark = req.Name, req.DataSourceId, req.Sql, params, req.Remark
+	err = global.GvaDb.Save(&query).Error
+	return
+}
+
+// Delete 删除保存的查询
+func (s SavedQueryService) Delete(ids []uint) error {
+	// 物理删除 名称唯一索引包含已软删除的行
+	return global.GvaDb.Unscoped().Delete(&dbModel.SysSavedQuery{}, ids).Error
+}
+
+// Execute 按参数执行保存的查询 返回首页结果 参数校验失败返回翻译后的校验错误
+func (s SavedQueryService) Execute(userId, id uint, req reqModel.SavedQueryExecReq) (page resModel.QueryPage, err error) {
+	runReq, opts, err := s.bind(id, req)
+	if err != nil {
+		return
+	}
+	return ServiceGroupApp.QueryService.run(userId, runReq, opts)
+}
+
+// ExecuteAsync 后台执行保存的查询 结果通过 query:chunk/query:done 事件推送
+func (s SavedQueryService) ExecuteAsync(userId, id uint, req reqModel.SavedQueryExecReq) (queryId string, err error) {
+	runReq, opts, err := s.bind(id, req)
+	if err != nil {
+		return
+	}
+	return ServiceGroupApp.QueryService.runAsync(userId, runReq, opts)
+}
+
+// bind 校验参数值并替换 SQL 中的命名参数
+func (s SavedQueryService) bind(id uint, req reqModel.SavedQueryExecReq) (runReq reqModel.QueryRunReq, opts queryOptions, err error) {
+	query, err := s.Get(id)
+	if err != nil {
+		return
+	}
+	source, err := ServiceGroupApp.DataSourceService.Get(query.DataSourceId)
+	if err != nil {
+		return
+	}
+	values, err := s.values(query.Params, req.Params)
+	if err != nil {
+		return
+	}
+	bound, args, err := datasource.BindNamed(source.Type, query.Sql, values)
+	if err != nil {
+		return runReq, opts, ApiReturn.ErrParam.WithData(err.Error())
+	}
+	runReq = reqModel.QueryRunReq{DataSourceId: query.DataSourceId, Sql: bound, PageSize: req.PageSize}
+	opts = queryOptions{args: args, savedQueryId: query.ID}
+	return
+}
+
+// check 校验参数定义、默认值以及 SQL 是否符合数据源的读写限制
+func (s SavedQueryService) check(dataSourceId uint, sql string, params []dbModel.QueryParam) error {
+	source, err := ServiceGroupApp.DataSourceService.Get(dataSourceId)
+	if err != nil {
+		return err
+	}
+	defined := make(map[string]interface{}, len(params))
+	for _, p := range params {
+		if !queryParamNamePattern.MatchString(p.Name) {
+			return ApiReturn.ErrParam.WithData(fmt.Sprintf("参数名 %s 只能包含字母、数字和下划线且不能以数字开头", p.Name))
+		}
+		if _, ok := defined[p.Name]; ok {
+			return ApiReturn.ErrParam.WithData(fmt.Sprintf("参数 %s 重复", p.Name))
+		}
+		defined[p.Name] = nil
+		for _, option := range p.Options {
+			if strings.ContainsAny(option, `'",|`) {
+				return ApiReturn.ErrParam.WithData(fmt.Sprintf("参数 %s 的可选值不能包含 ' \" , |", p.Name))
+			}
+		}
+		if p.Default != "" {
+			if err := binding.Validator.ValidateStruct(s.holder([]dbModel.QueryParam{p}, []string{p.Default}).Addr().Interface()); err != nil {
+				return ApiReturn.ErrParam.WithData(fmt.Sprintf("参数 %s 的默认值不合法", p.Name))
+			}
+		}
+	}
+	used := datasource.NamedParams(sql)
+	for _, name := range used {
+		if _, ok := defined[name]; !ok {
+			return ApiReturn.ErrParam.WithData(fmt.Sprintf("SQL 中的参数 %s 未定义", name))
+		}
+	}
+	if len(used) != len(defined) {
+		return ApiReturn.ErrParam.WithData("存在未在 SQL 中使用的参数")
+	}
+	bound, _, err := datasource.BindNamed(source.Type, sql, defined)
+	if err != nil {
+		return ApiReturn.ErrParam.WithData(err.Error())
+	}
+	stmt, err := datasource.ParseStatement(source.Type, bound)
+	if err != nil {
+		return ApiReturn.ErrParam.WithData(err.Error())
+	}
+	if !stmt.ReadOnly && !source.Writable {
+		return ApiReturn.QueryReadOnly
+	}
+	return nil
+}
+
+// values 按参数定义校验并转换参数值 未传值时使用默认值
+// 校验使用 gin 的校验器, 失败时返回 validator.ValidationErrors 由响应层按参数显示名翻译
+func (s SavedQueryService) values(params []dbModel.QueryParam, input map[string]interface{}) (map[string]interface{}, error) {
+	raw := make([]string, len(params))
+	for i, p := range params {
+		raw[i] = s.text(input[p.Name])
+		if raw[i] == "" {
+			raw[i] = p.Default
+		}
+	}
+	if err := binding.Validator.ValidateStruct(s.holder(params, raw).Addr().Interface()); err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(params))
+	for i, p := range params {
+		values[p.Name] = s.convert(p, raw[i])
+	}
+	return values, nil
+}
+
+// holder 按参数定义构造校验用的结构体 字段的 label/binding 标签与请求结构体一致
+func (s SavedQueryService) holder(params []dbModel.QueryParam, raw []string) reflect.Value {
+	fields := make([]reflect.StructField, len(params))
+	for i, p := range params {
+		label := p.Label
+		if label == "" {
+			label = p.Name
+		}
+		label = strings.NewReplacer(`"`, "", "`", "", ",", " ").Replace(label)
+		fields[i] = reflect.StructField{
+			Name: "P" + strconv.Itoa(i),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s" label:"%s" binding:"%s"`, p.Name, label, s.rule(p))),
+		}
+	}
+	holder := reflect.New(reflect.StructOf(fields)).Elem()
+	for i := range params {
+		holder.Field(i).SetString(raw[i])
+	}
+	return holder
+}
+
+// rule 参数类型对应的校验规则
+func (s SavedQueryService) rule(p dbModel.QueryParam) string {
+	rule := "omitempty"
+	if p.Required {
+		rule = "required"
+	}
+	switch p.Type {
+	case dbModel.QueryParamDate:
+		rule += ",datetime=" + queryParamDateLayout
+	case dbModel.QueryParamNumber:
+		rule += ",numeric"
+	case dbModel.QueryParamEnum:
+		options := make([]string, len(p.Options))
+		for i, option := range p.Options {
+			options[i] = "'" + option + "'"
+		}
+		rule += ",oneof=" + strings.Join(options, " ")
+	default:
+		rule += ",max=1000"
+	}
+	return rule
+}
+
+// text 请求中的参数值统一转为字符串后校验
+func (s SavedQueryService) text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// convert 校验通过的参数值转换为绑定值 空值按 NULL 处理
+func (s SavedQueryService) convert(p dbModel.QueryParam, raw string) interface{} {
+	if raw == "" {
+		return nil
+	}
+	if p.Type == dbModel.QueryParamNumber {
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return v
+		}
+		v, _ := strconv.ParseFloat(raw, 64)
+		return v
+	}
+	return raw
+}
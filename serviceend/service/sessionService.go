@@ -0,0 +1,184 @@
+package service
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// sessionTouchInterval 最后活跃时间写库的最小间隔 内存中实时更新
+const sessionTouchInterval = time.Minute
+
+type SessionService struct{}
+
+// sessionStore 会话内存缓存 sessionId => 会话 与 sys_user_sessions 表保持同步
+var sessionStore = struct {
+	sync.RWMutex
+	sessions map[string]*dbModel.SysUserSession
+	touched  map[string]time.Time // 最后一次写库的活跃时间
+}{
+	sessions: map[string]*dbModel.SysUserSession{},
+	touched:  map[string]time.Time{},
+}
+
+// LoadSessions 启动时清理过期会话并加载有效会话
+func (s SessionService) LoadSessions() error {
+	if err := global.GvaDb.Where("expires_at < ?", time.Now()).Delete(&dbModel.SysUserSession{}).Error; err != nil {
+		return err
+	}
+	var list []dbModel.SysUserSession
+	if err := global.GvaDb.Find(&list).Error; err != nil {
+		return err
+	}
+	sessionStore.Lock()
+	defer sessionStore.Unlock()
+	for i := range list {
+		session := list[i]
+		sessionStore.sessions[session.SessionId] = &session
+		sessionStore.touched[session.SessionId] = session.LastActiveAt
+	}
+	global.GvaLog.Info("登录会话加载完成", zap.Int("count", len(list)))
+	return nil
+}
+
+// NewSessionId 生成会话ID
+func (s SessionService) NewSessionId() string {
+	return uuid.NewString()
+}
+
+// Create 新建登录会话 未开启多点登录时注销该用户的其它会话
+func (s SessionService) Create(sessionId string, user dbModel.SysUser, clientIp, userAgent string, expiresAt time.Time) (*dbModel.SysUserSession, error) {
+	now := time.Now()
+	session := &dbModel.SysUserSession{
+		SessionId:    sessionId,
+		UserId:       user.ID,
+		Username:     user.Username,
+		ClientIp:     clientIp,
+		UserAgent:    userAgent,
+		ExpiresAt:    expiresAt,
+		LastActiveAt: now,
+	}
+	if !global.GvaConfig.System.UseMultipoint {
+		if _, err := s.RemoveByUser(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := global.GvaDb.Create(session).Error; err != nil {
+		return nil, err
+	}
+	sessionStore.Lock()
+	sessionStore.sessions[session.SessionId] = session
+	sessionStore.touched[session.SessionId] = now
+	sessionStore.Unlock()
+	return session, nil
+}
+
+// IsActive 会话是否有效 同时刷新最后活跃时间
+func (s SessionService) IsActive(sessionId string) bool {
+	if sessionId == "" {
+		return false
+	}
+	now := time.Now()
+	sessionStore.Lock()
+	session, ok := sessionStore.sessions[sessionId]
+	if !ok {
+		sessionStore.Unlock()
+		return false
+	}
+	if now.After(session.ExpiresAt) {
+		sessionStore.Unlock()
+		_ = s.Remove(sessionId)
+		return false
+	}
+	session.LastActiveAt = now
+	persist := now.Sub(sessionStore.touched[sessionId]) >= sessionTouchInterval
+	if persist {
+		sessionStore.touched[sessionId] = now
+	}
+	sessionStore.Unlock()
+	if persist {
+		if err := global.GvaDb.Model(&dbModel.SysUserSession{}).Where("session_id = ?", sessionId).Update("last_active_at", now).Error; err != nil {
+			global.GvaLog.Warn("会话活跃时间更新失败", zap.String("sessionId", sessionId), zap.Error(err))
+		}
+	}
+	return true
+}
+
+// Renew 刷新令牌后延长会话有效期
+func (s SessionService) Renew(sessionId string, expiresAt time.Time) error {
+	if err := global.GvaDb.Model(&dbModel.SysUserSession{}).Where("session_id = ?", sessionId).
+		Updates(map[string]interface{}{"expires_at": expiresAt, "last_active_at": time.Now()}).Error; err != nil {
+		return err
+	}
+	sessionStore.Lock()
+	if session, ok := sessionStore.sessions[sessionId]; ok {
+		session.ExpiresAt = expiresAt
+	}
+	sessionStore.Unlock()
+	return nil
+}
+
+// Remove 注销会话 持有该会话令牌的客户端下次请求将返回登录失效
+func (s SessionService) Remove(sessionId string) error {
+	if err := global.GvaDb.Where("session_id = ?", sessionId).Delete(&dbModel.SysUserSession{}).Error; err != nil {
+		return err
+	}
+	sessionStore.Lock()
+	delete(sessionStore.sessions, sessionId)
+	delete(sessionStore.touched, sessionId)
+	sessionStore.Unlock()
+	return nil
+}
+
+// RemoveByUser 注销用户的全部会话 返回注销数量
+func (s SessionService) RemoveByUser(userId uint) (int, error) {
+	if err := global.GvaDb.Where("user_id = ?", userId).Delete(&dbModel.SysUserSession{}).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	sessionStore.Lock()
+	for id, session := range sessionStore.sessions {
+		if session.UserId == userId {
+			delete(sessionStore.sessions, id)
+			delete(sessionStore.touched, id)
+			count++
+		}
+	}
+	sessionStore.Unlock()
+	if count > 0 {
+		global.GvaLog.Info("用户会话已注销", zap.Uint("userId", userId), zap.Int("count", count))
+	}
+	return count, nil
+}
+
+// Get 获取有效会话
+func (s SessionService) Get(sessionId string) (dbModel.SysUserSession, bool) {
+	sessionStore.RLock()
+	defer sessionStore.RUnlock()
+	session, ok := sessionStore.sessions[sessionId]
+	if !ok || time.Now().After(session.ExpiresAt) {
+		return dbModel.SysUserSession{}, false
+	}
+	return *session, true
+}
+
+// List 有效会话列表 userId 为 0 时返回全部 按最后活跃时间倒序
+func (s SessionService) List(userId uint) []dbModel.SysUserSession {
+	now := time.Now()
+	sessionStore.RLock()
+	list := make([]dbModel.SysUserSession, 0, len(sessionStore.sessions))
+	for _, session := range sessionStore.sessions {
+		if now.After(session.ExpiresAt) || (userId != 0 && session.UserId != userId) {
+			continue
+		}
+		list = append(list, *session)
+	}
+	sessionStore.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].LastActiveAt.After(list[j].LastActiveAt) })
+	return list
+}
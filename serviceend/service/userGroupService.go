@@ -0,0 +1,106 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type UserGroupService struct{}
+
+// List 分页查询用户群
+func (u UserGroupService) List(info reqModel.PageInfo) (res resModel.PageResult, err error) {
+	limit, offset := info.Limit()
+	db := global.GvaDb.Model(&dbModel.SysUserGroup{})
+	if info.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+info.Keyword+"%")
+	}
+	var list []dbModel.SysUserGroup
+	if err = db.Count(&res.Total).Error; err != nil {
+		return
+	}
+	err = db.Order("id").Limit(limit).Offset(offset).Find(&list).Error
+	res.List, res.Page, res.PageSize = list, offset/limit+1, limit
+	return
+}
+
+// Get 用户群详情 包含成员
+func (u UserGroupService) Get(id uint) (group dbModel.SysUserGroup, err error) {
+	err = global.GvaDb.Preload("Users").First(&group, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return group, ApiReturn.NoGroupInfo
+	}
+	return
+}
+
+// Save 新增/修改用户群
+func (u UserGroupService) Save(req reqModel.UserGroupReq) (group dbModel.SysUserGroup, err error) {
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysUserGroup{}).Where("name = ? AND id <> ?", req.Name, req.ID).Count(&count).Error; err != nil {
+		return
+	}
+	if count > 0 {
+		return group, ApiReturn.ErrParam.WithData("用户群名称已存在")
+	}
+	if req.ID != 0 {
+		if err = global.GvaDb.First(&group, req.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return group, ApiReturn.NoGroupInfo
+			}
+			return
+		}
+	}
+	// 未传状态时 新增默认启用, 修改保留原值
+	if req.ID == 0 {
+		group.Status = dbModel.GroupEnable
+	}
+	if req.Status != 0 {
+		group.Status = req.Status
+	}
+	group.Name, group.Remark = req.Name, req.Remark
+	err = global.GvaDb.Omit("Users").Save(&group).Error
+	return
+}
+
+// Delete 删除用户群 同时解除成员与公告关联
+func (u UserGroupService) Delete(ids []uint) error {
+	return global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"sys_user_group_members", "sys_bulletin_groups"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE sys_user_group_id IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		// 物理删除 名称唯一索引包含已软删除的行
+		return tx.Unscoped().Delete(&dbModel.SysUserGroup{}, ids).Error
+	})
+}
+
+// SetMembers 覆盖设置用户群成员
+func (u UserGroupService) SetMembers(req reqModel.UserGroupMemberReq) error {
+	group, err := u.Get(req.GroupId)
+	if err != nil {
+		return err
+	}
+	var users []dbModel.SysUser
+	if len(req.UserIds) > 0 {
+		if err = global.GvaDb.Find(&users, req.UserIds).Error; err != nil {
+			return err
+		}
+	}
+	return global.GvaDb.Model(&group).Association("Users").Replace(users)
+}
+
+// UserGroupIds 用户所在的可用用户群
+func (u UserGroupService) UserGroupIds(userId uint) ([]uint, error) {
+	var ids []uint
+	err := global.GvaDb.Table("sys_user_group_members m").
+		Joins("JOIN sys_user_groups g ON g.id = m.sys_user_group_id AND g.deleted_at IS NULL").
+		Where("m.sys_user_id = ? AND g.status = ?", userId, dbModel.GroupEnable).
+		Pluck("g.id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserService struct{}
+
+// Login 用户名密码登录 成功后创建会话并签发令牌
+func (u UserService) Login(req reqModel.LoginReq, clientIp, userAgent string) (res resModel.LoginRes, err error) {
+	var user dbModel.SysUser
+	if err = global.GvaDb.Where("username = ?", req.Username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res, ApiReturn.NoUserInfo
+		}
+		return res, err
+	}
+	if !utils.BcryptCheck(req.Password, user.Password) {
+		return res, ApiReturn.ErrPwd
+	}
+	if user.Enable != dbModel.UserEnable {
+		return res, ApiReturn.UnauthorizedAccess
+	}
+	sessionId := ServiceGroupApp.SessionService.NewSessionId()
+	if res, err = u.issue(user, sessionId); err != nil {
+		return res, err
+	}
+	if _, err = ServiceGroupApp.SessionService.Create(sessionId, user, clientIp, userAgent, time.UnixMilli(res.RefreshExpiresAt)); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对 旧的刷新令牌随即失效
+func (u UserService) RefreshToken(refreshToken string) (res resModel.LoginRes, err error) {
+	claims, err := utils.NewJWT().ParseToken(refreshToken)
+	if err != nil {
+		if errors.Is(err, utils.TokenExpired) {
+			return res, ApiReturn.LoginExpired
+		}
+		return res, ApiReturn.UnauthorizedAccess
+	}
+	if claims.TokenType != utils.TokenTypeRefresh {
+		return res, ApiReturn.UnauthorizedAccess
+	}
+	if ServiceGroupApp.JwtService.IsBlacklist(claims.ID) || !ServiceGroupApp.SessionService.IsActive(claims.SessionId) {
+		return res, ApiReturn.LoginExpired
+	}
+	user, err := u.GetUserInfo(claims.UserId)
+	if err != nil {
+		return res, err
+	}
+	if user.Enable != dbModel.UserEnable {
+		return res, ApiReturn.UnauthorizedAccess
+	}
+	if err = ServiceGroupApp.JwtService.JoinBlacklist(claims); err != nil {
+		return res, err
+	}
+	if res, err = u.issue(user, claims.SessionId); err != nil {
+		return res, err
+	}
+	if err = ServiceGroupApp.SessionService.Renew(claims.SessionId, time.UnixMilli(res.RefreshExpiresAt)); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// Logout 注销会话与访问令牌 传入刷新令牌时一并注销
+func (u UserService) Logout(claims *utils.CustomClaims, refreshToken string) error {
+	if err := ServiceGroupApp.SessionService.Remove(claims.SessionId); err != nil {
+		return err
+	}
+	if err := ServiceGroupApp.JwtService.JoinBlacklist(claims); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	refreshClaims, err := utils.NewJWT().ParseToken(refreshToken)
+	if err != nil || refreshClaims.TokenType != utils.TokenTypeRefresh || refreshClaims.UserId != claims.UserId {
+		// 刷新令牌无效时不影响本次退出
+		return nil
+	}
+	return ServiceGroupApp.JwtService.JoinBlacklist(refreshClaims)
+}
+
+// GetUserInfo 根据ID获取用户信息
+func (u UserService) GetUserInfo(id uint) (user dbModel.SysUser, err error) {
+	if err = global.GvaDb.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, ApiReturn.NoUserInfo
+		}
+		return user, err
+	}
+	return user, nil
+}
+
+func (u UserService) issue(user dbModel.SysUser, sessionId string) (res resModel.LoginRes, err error) {
+	access, refresh, accessToken, refreshToken, err := ServiceGroupApp.JwtService.IssueTokens(user, sessionId)
+	if err != nil {
+		global.GvaLog.Error("令牌签发失败", zap.String("username", user.Username), zap.Error(err))
+		return res, ApiReturn.ErrCreateToken
+	}
+	return resModel.LoginRes{
+		User:             user,
+		Token:            accessToken,
+		ExpiresAt:        access.ExpiresAt.UnixMilli(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt.UnixMilli(),
+	}, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"dataPanel/serviceend/utils/sender"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// verificationKeepTime 验证码记录保留时长 用于统计发送次数与排查
+const verificationKeepTime = 24 * time.Hour
+
+type VerificationService struct{}
+
+// verificationConfig 验证码配置 未配置的项使用默认值
+type verificationConfig struct {
+	length      int
+	expires     time.Duration
+	hourlyLimit int
+	maxAttempts int
+}
+
+// CleanExpired 清理超过保留时长的验证码记录
+func (v VerificationService) CleanExpired() error {
+	return global.GvaDb.Where("created_at < ?", time.Now().Add(-verificationKeepTime)).Delete(&dbModel.SysVerificationCode{}).Error
+}
+
+// Send 生成并发送验证码 同一接收方每小时发送次数超出限制返回 VerificationNoCount
+func (v VerificationService) Send(req reqModel.SendCodeReq, clientIp string) (res resModel.SendCodeRes, err error) {
+	cfg, err := v.config()
+	if err != nil {
+		return
+	}
+	channel, target := v.target(req.Phone, req.Email)
+	var count int64
+	if err = global.GvaDb.Model(&dbModel.SysVerificationCode{}).Where("target = ? AND created_at > ?", target, time.Now().Add(-time.Hour)).
+		Count(&count).Error; err != nil {
+		return
+	}
+	if int(count) >= cfg.hourlyLimit {
+		return res, ApiReturn.VerificationNoCount
+	}
+	code, err := v.generate(cfg.length)
+	if err != nil {
+		return
+	}
+	s, err := sender.NewSender()
+	if err != nil {
+		return
+	}
+	msg := sender.Message{
+		Channel: channel,
+		Target:  target,
+		Subject: fmt.Sprintf("%s 验证码", global.GvaConfig.System.ApplicationName),
+		Content: fmt.Sprintf("【%s】您的验证码为 %s, %d 分钟内有效, 请勿泄露给他人。", global.GvaConfig.System.ApplicationName, code, int(cfg.expires.Minutes())),
+	}
+	// 发送成功后再保存 发送失败时之前的验证码仍有效, 且不占用发送次数
+	if err = s.Send(msg); err != nil {
+		global.GvaLog.Error("验证码发送失败", zap.String("channel", channel), zap.String("target", target), zap.Error(err))
+		return
+	}
+	record := dbModel.SysVerificationCode{
+		Channel:   channel,
+		Target:    target,
+		Scene:     req.Scene,
+		CodeHash:  v.hash(target, req.Scene, code),
+		ClientIp:  clientIp,
+		ExpiresAt: time.Now().Add(cfg.expires),
+	}
+	err = global.GvaDb.Transaction(func(tx *gorm.DB) error {
+		// 重新获取后之前的验证码失效
+		if err := tx.Model(&dbModel.SysVerificationCode{}).Where("target = ? AND scene = ? AND used = ?", target, req.Scene, false).
+			Update("used", true).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return
+	}
+	res.ExpiresIn = int64(cfg.expires.Seconds())
+	res.Remaining = cfg.hourlyLimit - int(count) - 1
+	return
+}
+
+// Verify 校验验证码 校验成功后验证码失效 失败次数超出限制后需重新获取
+func (v VerificationService) Verify(req reqModel.VerifyCodeReq) error {
+	cfg, err := v.config()
+	if err != nil {
+		return err
+	}
+	_, target := v.target(req.Phone, req.Email)
+	var record dbModel.SysVerificationCode
+	err = global.GvaDb.Where("target = ? AND scene = ? AND used = ? AND expires_at > ?", target, req.Scene, false, time.Now()).
+		Order("id DESC").First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ApiReturn.ErrVerificationCode
+	}
+	if err != nil {
+		return err
+	}
+	if record.Attempts >= cfg.maxAttempts {
+		return ApiReturn.ErrVerificationCode
+	}
+	if subtle.ConstantTimeCompare([]byte(record.CodeHash), []byte(v.hash(target, req.Scene, req.Code))) != 1 {
+		attempts := record.Attempts + 1
+		updates := map[string]interface{}{"attempts": attempts}
+		if attempts >= cfg.maxAttempts {
+			updates["used"] = true
+		}
+		if err = global.GvaDb.Model(&record).Updates(updates).Error; err != nil {
+			return err
+		}
+		return ApiReturn.ErrVerificationCode
+	}
+	return global.GvaDb.Model(&record).Updates(map[string]interface{}{"used": true, "attempts": record.Attempts + 1}).Error
+}
+
+func (v VerificationService) config() (cfg verificationConfig, err error) {
+	cfg = verificationConfig{length: 6, expires: 5 * time.Minute, hourlyLimit: 5, maxAttempts: 5}
+	c := global.GvaConfig.Verification
+	if c == nil {
+		return
+	}
+	if c.Length >= 4 && c.Length <= 10 {
+		cfg.length = c.Length
+	}
+	if c.ExpiresTime != "" {
+		if cfg.expires, err = utils.ParseDuration(c.ExpiresTime); err != nil {
+			return
+		}
+	}
+	if c.HourlyLimit > 0 {
+		cfg.hourlyLimit = c.HourlyLimit
+	}
+	if c.MaxAttempts > 0 {
+		cfg.maxAttempts = c.MaxAttempts
+	}
+	return
+}
+
+// target 发送渠道与接收方 邮箱统一小写
+func (v VerificationService) target(phone, email string) (channel, target string) {
+	if phone != "" {
+		return sender.ChannelSms, phone
+	}
+	return sender.ChannelEmail, strings.ToLower(strings.TrimSpace(email))
+}
+
+func (v VerificationService) generate(length int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+	return sb.String(), nil
+}
+
+func (v VerificationService) hash(target, scene, code string) string {
+	sum := sha256.Sum256([]byte(target + "|" + scene + "|" + code))
+	return hex.EncodeToString(sum[:])
+}
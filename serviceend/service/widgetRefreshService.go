@@ -0,0 +1,220 @@
+package service
+
+import (
+	"crypto/sha256"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/model/dbModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/utils"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EventWidgetUpdate 组件数据变化 推送新的组件数据
+const EventWidgetUpdate = "widget:update"
+
+const (
+	widgetRefreshTick        = time.Second // 调度检查间隔
+	widgetRefreshConcurrency = 4           // 同时刷新的组件数
+)
+
+type WidgetRefreshService struct{}
+
+// widgetRefresh 单个组件的刷新状态与最近一次结果
+type widgetRefresh struct {
+	interval  time.Duration
+	next      time.Time
+	running   bool
+	hash      string
+	data      resModel.WidgetData
+	hasResult bool
+}
+
+// widgetRefresher 组件自动刷新调度 按秒检查到期的组件 暂停期间不刷新
+var widgetRefresher = struct {
+	sync.Mutex
+	widgets map[uint]*widgetRefresh
+	stop    chan struct{}
+	paused  bool
+	sem     chan struct{}
+}{widgets: map[uint]*widgetRefresh{}, sem: make(chan struct{}, widgetRefreshConcurrency)}
+
+// Start 加载需要自动刷新的组件并启动调度
+func (w WidgetRefreshService) Start() error {
+	if err := w.Sync(); err != nil {
+		return err
+	}
+	widgetRefresher.Lock()
+	defer widgetRefresher.Unlock()
+	if widgetRefresher.stop != nil {
+		return nil
+	}
+	widgetRefresher.stop = make(chan struct{})
+	go w.run(widgetRefresher.stop)
+	return nil
+}
+
+// Stop 停止调度 程序退出时调用
+func (w WidgetRefreshService) Stop() {
+	widgetRefresher.Lock()
+	defer widgetRefresher.Unlock()
+	if widgetRefresher.stop != nil {
+		close(widgetRefresher.stop)
+		widgetRefresher.stop = nil
+	}
+}
+
+// Pause 暂停自动刷新 窗口隐藏时调用
+func (w WidgetRefreshService) Pause() {
+	widgetRefresher.Lock()
+	defer widgetRefresher.Unlock()
+	if !widgetRefresher.paused {
+		widgetRefresher.paused = true
+		global.GvaLog.Info("组件自动刷新已暂停")
+	}
+}
+
+// Resume 恢复自动刷新 暂停期间到期的组件在下一次检查时刷新
+func (w WidgetRefreshService) Resume() {
+	widgetRefresher.Lock()
+	defer widgetRefresher.Unlock()
+	if widgetRefresher.paused {
+		widgetRefresher.paused = false
+		global.GvaLog.Info("组件自动刷新已恢复")
+	}
+}
+
+// Sync 按数据库中的组件配置更新调度 仪表盘保存/删除后调用
+// dashboardIds 为配置已变化的仪表盘 其组件丢弃缓存并立即刷新
+func (w WidgetRefreshService) Sync(dashboardIds ...uint) error {
+	var widgets []dbModel.SysDashboardWidget
+	if err := global.GvaDb.Select("id", "dashboard_id", "refresh_interval").Where("refresh_interval > 0").Find(&widgets).Error; err != nil {
+		return err
+	}
+	changed := make(map[uint]struct{}, len(dashboardIds))
+	for _, id := range dashboardIds {
+		changed[id] = struct{}{}
+	}
+	now := time.Now()
+	widgetRefresher.Lock()
+	defer widgetRefresher.Unlock()
+	seen := make(map[uint]struct{}, len(widgets))
+	for _, widget := range widgets {
+		seen[widget.ID] = struct{}{}
+		// 配置变化的组件重建状态 正在进行的刷新按旧配置查询, 结果丢弃
+		if _, ok := widgetRefresher.widgets[widget.ID]; ok {
+			if _, ok := changed[widget.DashboardId]; !ok {
+				continue
+			}
+		}
+		widgetRefresher.widgets[widget.ID] = &widgetRefresh{interval: time.Duration(widget.RefreshInterval) * time.Second, next: now}
+	}
+	for id := range widgetRefresher.widgets {
+		if _, ok := seen[id]; !ok {
+			delete(widgetRefresher.widgets, id)
+		}
+	}
+	return nil
+}
+
+// Latest 仪表盘各组件的最近结果 自动刷新的组件返回缓存, 其它组件即时查询
+func (w WidgetRefreshService) Latest(userId, dashboardId uint) ([]resModel.WidgetData, error) {
+	dashboard, err := ServiceGroupApp.DashboardService.Get(dashboardId)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]resModel.WidgetData, len(dashboard.Widgets))
+	for i, widget := range dashboard.Widgets {
+		widgetRefresher.Lock()
+		r, ok := widgetRefresher.widgets[widget.ID]
+		if ok && r.hasResult {
+			list[i] = r.data
+		}
+		widgetRefresher.Unlock()
+		if ok && r.hasResult {
+			continue
+		}
+		list[i] = w.load(userId, widget.ID)
+	}
+	return list, nil
+}
+
+func (w WidgetRefreshService) run(stop chan struct{}) {
+	ticker := time.NewTicker(widgetRefreshTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			w.dispatch(now)
+		}
+	}
+}
+
+// dispatch 启动到期组件的刷新 同一组件上一次刷新未结束时跳过
+func (w WidgetRefreshService) dispatch(now time.Time) {
+	widgetRefresher.Lock()
+	defer widgetRefresher.Unlock()
+	if widgetRefresher.paused {
+		return
+	}
+	for id, r := range widgetRefresher.widgets {
+		if r.running || now.Before(r.next) {
+			continue
+		}
+		r.running = true
+		go w.refresh(id, r)
+	}
+}
+
+// refresh 刷新组件 结果变化时推送 widget:update 事件
+func (w WidgetRefreshService) refresh(id uint, r *widgetRefresh) {
+	widgetRefresher.sem <- struct{}{}
+	data := w.load(0, id)
+	<-widgetRefresher.sem
+	hash := w.hash(data)
+	widgetRefresher.Lock()
+	r.running, r.next = false, time.Now().Add(r.interval)
+	// 刷新期间组件已删除或配置已变化
+	if widgetRefresher.widgets[id] != r {
+		widgetRefresher.Unlock()
+		return
+	}
+	changed := !r.hasResult || r.hash != hash
+	r.hash, r.data, r.hasResult = hash, data, true
+	widgetRefresher.Unlock()
+	if changed {
+		ServiceGroupApp.EventService.Emit(EventWidgetUpdate, data)
+	}
+}
+
+// load 查询组件数据 失败时返回带错误信息的结果
+func (w WidgetRefreshService) load(userId, id uint) resModel.WidgetData {
+	data, err := ServiceGroupApp.DashboardService.WidgetData(userId, id)
+	if err == nil {
+		return data
+	}
+	global.GvaLog.Warn("组件数据刷新失败", zap.Uint("widgetId", id), zap.Error(err))
+	data = resModel.WidgetData{WidgetId: id, UpdatedAt: time.Now().Format(utils.TimeFormat), Error: err.Error()}
+	var apiErr ApiReturn.ApiReturnCode
+	if errors.As(err, &apiErr) && apiErr.Data != nil {
+		data.Error = fmt.Sprintf("%s: %v", apiErr.Msg, apiErr.Data)
+	}
+	return data
+}
+
+// hash 结果摘要 不含数据时间 用于判断结果是否变化
+func (w WidgetRefreshService) hash(data resModel.WidgetData) string {
+	data.UpdatedAt = ""
+	content, _ := json.Marshal(data)
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
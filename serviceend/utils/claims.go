@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ClaimsKey = "claims"
+
+// GetToken 从请求头获取令牌 支持 x-token 与 Authorization: Bearer
+func GetToken(c *gin.Context) string {
+	if token := c.GetHeader("x-token"); token != "" {
+		return token
+	}
+	auth := c.GetHeader("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return auth[7:]
+	}
+	return ""
+}
+
+// GetClaims 获取鉴权中间件写入的令牌载荷 未登录(或开发环境跳过鉴权)时返回 nil
+func GetClaims(c *gin.Context) *CustomClaims {
+	if claims, exists := c.Get(ClaimsKey); exists {
+		if waitUse, ok := claims.(*CustomClaims); ok {
+			return waitUse
+		}
+	}
+	return nil
+}
+
+// GetUserID 从Gin的Context中获取从jwt解析出来的用户ID
+func GetUserID(c *gin.Context) uint {
+	if claims := GetClaims(c); claims != nil {
+		return claims.UserId
+	}
+	return 0
+}
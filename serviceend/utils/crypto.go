@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+// AesEncrypt AES-256-GCM 加密 密钥为任意字符串,经 SHA-256 派生 返回 base64(nonce+密文)
+func AesEncrypt(plain, key string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	gcm, err := newGcm(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plain), nil)), nil
+}
+
+// AesDecrypt 解密 AesEncrypt 的结果
+func AesDecrypt(encrypted, key string) (string, error) {
+	if encrypted == "" {
+		return "", nil
+	}
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGcm(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("密文格式错误")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("解密失败,请检查 system.secret-key 是否被修改")
+	}
+	return string(plain), nil
+}
+
+func newGcm(key string) (cipher.AEAD, error) {
+	if key == "" {
+		return nil, errors.New("未配置加密密钥 system.secret-key")
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,255 @@
+package datasource
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	csvColumnInteger = "INTEGER"
+	csvColumnReal    = "REAL"
+	csvColumnText    = "TEXT"
+)
+
+// csvDriver CSV 目录数据源 目录下每个 .csv 文件(首行为表头)加载为同名表,列类型按内容推断
+// 数据在打开时读取,文件变更后需重新打开数据源
+type csvDriver struct{}
+
+type csvTable struct {
+	name    string
+	columns []string
+	types   []string
+	rows    [][]driver.Value
+}
+
+func (c csvDriver) Validate(cfg Config) error {
+	if cfg.Path == "" {
+		return errors.New("CSV 目录不能为空")
+	}
+	return nil
+}
+
+func (c csvDriver) Open(cfg Config) (*sql.DB, error) {
+	fi, err := os.Stat(cfg.Path)
+	if err != nil || !fi.IsDir() {
+		return nil, fmt.Errorf("CSV 目录不存在: %s", cfg.Path)
+	}
+	files, err := filepath.Glob(filepath.Join(cfg.Path, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]csvTable, 0, len(files))
+	for _, file := range files {
+		table, err := readCsvTable(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		tables = append(tables, table)
+	}
+	// 借用已注册的 sqlite 驱动创建内存库
+	tmp, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	drv := tmp.Driver()
+	_ = tmp.Close()
+	db := sql.OpenDB(&csvConnector{drv: drv, tables: tables, readOnly: cfg.ReadOnly})
+	db.SetMaxOpenConns(defaultMaxIdleConns)
+	db.SetMaxIdleConns(defaultMaxIdleConns)
+	return db, nil
+}
+
+// csvConnector 每个新连接都是独立的内存库,创建时载入全部表
+type csvConnector struct {
+	drv      driver.Driver
+	tables   []csvTable
+	readOnly bool
+}
+
+func (c *csvConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.load(ctx, conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *csvConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+func (c *csvConnector) load(ctx context.Context, conn driver.Conn) error {
+	execer, ok1 := conn.(driver.ExecerContext)
+	preparer, ok2 := conn.(driver.ConnPrepareContext)
+	beginner, ok3 := conn.(driver.ConnBeginTx)
+	if !ok1 || !ok2 || !ok3 {
+		return errors.New("sqlite 驱动不支持批量载入")
+	}
+	tx, err := beginner.BeginTx(ctx, driver.TxOptions{})
+	if err != nil {
+		return err
+	}
+	for _, table := range c.tables {
+		if err = loadCsvTable(ctx, execer, preparer, table); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("载入表 %s 失败: %w", table.name, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	// 载入完成后再禁止写入 只读数据源的内存表不可修改
+	if c.readOnly {
+		_, err = execer.ExecContext(ctx, "PRAGMA query_only = 1", nil)
+	}
+	return err
+}
+
+func loadCsvTable(ctx context.Context, execer driver.ExecerContext, preparer driver.ConnPrepareContext, table csvTable) error {
+	defs := make([]string, len(table.columns))
+	marks := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		defs[i] = quoteIdent(column) + " " + table.types[i]
+		marks[i] = "?"
+	}
+	createSql := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(table.name), strings.Join(defs, ", "))
+	if _, err := execer.ExecContext(ctx, createSql, nil); err != nil {
+		return err
+	}
+	stmt, err := preparer.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoteIdent(table.name), strings.Join(marks, ", ")))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	stmtExec, ok := stmt.(driver.StmtExecContext)
+	if !ok {
+		return errors.New("sqlite 驱动不支持批量载入")
+	}
+	args := make([]driver.NamedValue, len(table.columns))
+	for _, row := range table.rows {
+		for i := range args {
+			args[i] = driver.NamedValue{Ordinal: i + 1, Value: row[i]}
+		}
+		if _, err = stmtExec.ExecContext(ctx, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCsvTable 读取 CSV 文件 首行为列名 空列名/重复列名自动补齐
+func readCsvTable(file string) (table csvTable, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return table, errors.New("文件为空")
+	}
+	if err != nil {
+		return
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	table.name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	seen := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		if n := seen[strings.ToLower(name)]; n > 0 {
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[strings.ToLower(name)]++
+		table.columns = append(table.columns, name)
+	}
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return table, err
+		}
+		records = append(records, record)
+	}
+	table.types = make([]string, len(table.columns))
+	for i := range table.columns {
+		table.types[i] = inferCsvType(records, i)
+	}
+	table.rows = make([][]driver.Value, 0, len(records))
+	for _, record := range records {
+		row := make([]driver.Value, len(table.columns))
+		for i := range table.columns {
+			if i >= len(record) || strings.TrimSpace(record[i]) == "" {
+				continue
+			}
+			row[i] = convertCsvValue(strings.TrimSpace(record[i]), table.types[i])
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// inferCsvType 全部非空值均为整数时为 INTEGER, 均为数字时为 REAL, 否则为 TEXT
+func inferCsvType(records [][]string, index int) string {
+	typ := csvColumnInteger
+	for _, record := range records {
+		if index >= len(record) {
+			continue
+		}
+		value := strings.TrimSpace(record[index])
+		if value == "" {
+			continue
+		}
+		if typ == csvColumnInteger {
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+				continue
+			}
+			typ = csvColumnReal
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return csvColumnText
+		}
+	}
+	return typ
+}
+
+func convertCsvValue(value, typ string) driver.Value {
+	switch typ {
+	case csvColumnInteger:
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case csvColumnReal:
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,108 @@
+package datasource
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	TypeMysql    = "mysql"
+	TypePostgres = "postgres"
+	TypeSqlite   = "sqlite"
+	TypeCsv      = "csv" // CSV 目录 目录下每个 .csv 文件作为一张表
+)
+
+// ErrUnsupported 未注册的数据源类型
+var ErrUnsupported = errors.New("不支持的数据源类型")
+
+// Config 数据源连接参数 密码为明文,仅在内存中使用
+type Config struct {
+	Type     string
+	Host     string
+	Port     int
+	Database string
+	Username string
+	Password string
+	Params   string // 额外连接参数 如 charset=utf8mb4&parseTime=True
+	Path     string // sqlite 文件路径/csv 目录
+	ReadOnly bool   // 只读数据源 支持的驱动在连接层面禁止写入
+}
+
+// Driver 数据源驱动 新增数据源类型时实现该接口并通过 Register 注册
+type Driver interface {
+	// Validate 校验连接参数是否完整
+	Validate(cfg Config) error
+	// Open 打开连接池 调用方负责关闭
+	Open(cfg Config) (*sql.DB, error)
+}
+
+var drivers = struct {
+	sync.RWMutex
+	m map[string]Driver
+}{m: map[string]Driver{}}
+
+// Register 注册数据源驱动 同名覆盖
+func Register(typ string, driver Driver) {
+	drivers.Lock()
+	drivers.m[typ] = driver
+	drivers.Unlock()
+}
+
+// Get 获取数据源驱动
+func Get(typ string) (Driver, error) {
+	drivers.RLock()
+	defer drivers.RUnlock()
+	if driver, ok := drivers.m[typ]; ok {
+		return driver, nil
+	}
+	return nil, ErrUnsupported
+}
+
+// Types 已注册的数据源类型
+func Types() []string {
+	drivers.RLock()
+	defer drivers.RUnlock()
+	types := make([]string, 0, len(drivers.m))
+	for typ := range drivers.m {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Open 校验参数并打开连接池
+func Open(cfg Config) (*sql.DB, error) {
+	driver, err := Get(cfg.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err = driver.Validate(cfg); err != nil {
+		return nil, err
+	}
+	return driver.Open(cfg)
+}
+
+// Test 测试连接 返回连接耗时
+func Test(ctx context.Context, cfg Config) (time.Duration, error) {
+	start := time.Now()
+	db, err := Open(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+	if err = db.PingContext(ctx); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+func init() {
+	Register(TypeMysql, mysqlDriver{})
+	Register(TypePostgres, postgresDriver{})
+	Register(TypeSqlite, sqliteDriver{})
+	Register(TypeCsv, csvDriver{})
+}
@@ -0,0 +1,219 @@
+package datasource
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// readOnlyKeywords 只读语句允许的起始关键字
+var readOnlyKeywords = map[string]struct{}{
+	"SELECT": {}, "WITH": {}, "SHOW": {}, "DESC": {}, "DESCRIBE": {}, "EXPLAIN": {}, "VALUES": {}, "TABLE": {},
+}
+
+// writeKeywords 出现在只读语句中即视为写操作 如 WITH ... DELETE/SELECT ... INTO
+var writeKeywords = map[string]struct{}{
+	"INSERT": {}, "UPDATE": {}, "DELETE": {}, "MERGE": {}, "UPSERT": {}, "INTO": {},
+	"CREATE": {}, "ALTER": {}, "DROP": {}, "TRUNCATE": {}, "RENAME": {}, "GRANT": {}, "REVOKE": {},
+	"LOCK": {}, "CALL": {}, "EXEC": {}, "EXECUTE": {}, "COPY": {}, "ATTACH": {}, "DETACH": {}, "VACUUM": {}, "PRAGMA": {},
+}
+
+var (
+	ErrEmptySql       = errors.New("SQL 不能为空")
+	ErrMultiStatement = errors.New("不允许一次执行多条语句")
+	ErrWriteStatement = errors.New("只读模式下仅允许执行查询语句")
+)
+
+// Statement 语句分析结果
+type Statement struct {
+	Sql      string // 去掉末尾分号的语句
+	Keyword  string // 起始关键字(大写)
+	ReadOnly bool   // 是否为只读语句
+}
+
+// ParseStatement 按数据源类型分析单条 SQL 忽略注释与字符串内容 多条语句返回 ErrMultiStatement
+// 字符串转义规则受服务端配置影响时(如 mysql 的 NO_BACKSLASH_ESCAPES), 按每种规则分别分析,
+// 任一规则下为多条语句或含写操作即按多条语句或写操作处理
+func ParseStatement(dialect, sql string) (Statement, error) {
+	var words []string
+	statements, readOnly := 0, true
+	for i, rules := range dialectRules(dialect) {
+		w, n := scanSql(sql, rules)
+		if i == 0 {
+			words = w
+		}
+		statements = max(statements, n)
+		readOnly = readOnly && isReadOnly(w)
+	}
+	if statements == 0 || len(words) == 0 {
+		return Statement{}, ErrEmptySql
+	}
+	if statements > 1 {
+		return Statement{}, ErrMultiStatement
+	}
+	return Statement{Sql: strings.TrimRight(strings.TrimSpace(sql), "; \t\r\n"), Keyword: words[0], ReadOnly: readOnly}, nil
+}
+
+// isReadOnly 以只读关键字开头且不含写操作关键字
+func isReadOnly(words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	if _, ok := readOnlyKeywords[words[0]]; !ok {
+		return false
+	}
+	for _, word := range words[1:] {
+		if _, ok := writeKeywords[word]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckReadOnly 校验 SQL 为单条只读语句
+func CheckReadOnly(dialect, sql string) (Statement, error) {
+	stmt, err := ParseStatement(dialect, sql)
+	if err != nil {
+		return stmt, err
+	}
+	if !stmt.ReadOnly {
+		return stmt, ErrWriteStatement
+	}
+	return stmt, nil
+}
+
+// scanRules 词法规则 与数据库解析 SQL 的方式保持一致, 否则可借助字符串或注释隐藏语句
+type scanRules struct {
+	backslash  bool // 字符串内反斜杠转义 mysql 默认开启, postgres 关闭 standard_conforming_strings 时开启
+	ansiQuotes bool // mysql 开启 ANSI_QUOTES 时双引号为标识符 不做反斜杠转义
+	hash       bool // # 开始的单行注释 仅 mysql
+	mysql      bool // -- 后须有空白才是注释; /*! */ 与 /*+ */ 中的内容会被 mysql 执行, 按语句内容分析
+	postgres   bool // E'' 字符串反斜杠转义 与 $tag$ 字符串
+	brackets   bool // [] 引号标识符 仅 sqlite
+}
+
+// dialectRules 数据源类型对应的词法规则 受服务端配置影响的返回多组
+func dialectRules(dialect string) []scanRules {
+	switch dialect {
+	case TypeMysql:
+		return []scanRules{
+			{backslash: true, hash: true, mysql: true},
+			{backslash: true, ansiQuotes: true, hash: true, mysql: true},
+			{hash: true, mysql: true},
+		}
+	case TypePostgres:
+		return []scanRules{{postgres: true}, {backslash: true, postgres: true}}
+	default:
+		return []scanRules{{brackets: true}}
+	}
+}
+
+// scanSql 提取语句中的关键字(大写) 跳过注释、字符串与引号标识符 返回非空语句数量
+func scanSql(sql string, rules scanRules) (words []string, statements int) {
+	runes := []rune(sql)
+	n := len(runes)
+	hasContent := false
+	for i := 0; i < n; i++ {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < n && runes[i+1] == '-' && (!rules.mysql || i+2 == n || unicode.IsSpace(runes[i+2])), r == '#' && rules.hash:
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < n && runes[i+1] == '*':
+			// mysql 可执行注释 跳过标记后按语句内容继续分析
+			if rules.mysql && i+2 < n && (runes[i+2] == '!' || runes[i+2] == '+') {
+				i += 2
+				continue
+			}
+			i += 2
+			for i+1 < n && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i++
+		case r == '$' && rules.postgres && (i == 0 || !isWordRune(runes[i-1])):
+			tag, ok := dollarTag(runes, i)
+			if !ok {
+				hasContent = true
+				continue
+			}
+			hasContent = true
+			end := indexRunes(runes, i+len(tag), tag)
+			if end < 0 {
+				return words, statements + 1
+			}
+			i = end + len(tag) - 1
+		case r == '\'' || r == '"' || r == '`':
+			hasContent = true
+			// 反斜杠转义只作用于字符串 postgres 与 ANSI_QUOTES 下的双引号为标识符
+			escape := rules.backslash && (r == '\'' || (r == '"' && rules.mysql && !rules.ansiQuotes))
+			// postgres E'' 字符串
+			if rules.postgres && r == '\'' && i > 0 && (runes[i-1] == 'E' || runes[i-1] == 'e') && (i == 1 || !isWordRune(runes[i-2])) {
+				escape = true
+			}
+			for i++; i < n; i++ {
+				if runes[i] == '\\' && escape {
+					i++
+					continue
+				}
+				if runes[i] == r {
+					if i+1 < n && runes[i+1] == r {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case r == '[' && rules.brackets:
+			hasContent = true
+			for i < n && runes[i] != ']' {
+				i++
+			}
+		case r == ';':
+			if hasContent {
+				statements++
+			}
+			hasContent = false
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i+1 < n && isWordRune(runes[i+1]) {
+				i++
+			}
+			hasContent = true
+			words = append(words, strings.ToUpper(string(runes[start:i+1])))
+		case !unicode.IsSpace(r):
+			hasContent = true
+		}
+	}
+	if hasContent {
+		statements++
+	}
+	return
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+// dollarTag postgres 的 $tag$ 字符串起始标记 $1 等参数占位符不是
+func dollarTag(runes []rune, i int) ([]rune, bool) {
+	for j := i + 1; j < len(runes); j++ {
+		switch r := runes[j]; {
+		case r == '$':
+			return runes[i : j+1], true
+		case unicode.IsLetter(r) || r == '_' || (unicode.IsDigit(r) && j > i+1):
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func indexRunes(runes []rune, from int, sub []rune) int {
+	for i := from; i+len(sub) <= len(runes); i++ {
+		if string(runes[i:i+len(sub)]) == string(sub) {
+			return i
+		}
+	}
+	return -1
+}
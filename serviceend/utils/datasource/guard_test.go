@@ -0,0 +1,142 @@
+package datasource
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseStatement(t *testing.T) {
+	cases := []struct {
+		name     string
+		dialect  string
+		sql      string
+		err      error
+		readOnly bool
+	}{
+		{"select", TypeMysql, "SELECT * FROM t;", nil, true},
+		{"with select", TypePostgres, "WITH a AS (SELECT 1) SELECT * FROM a", nil, true},
+		{"delete", TypeSqlite, "DELETE FROM t", nil, false},
+		{"with delete", TypePostgres, "WITH a AS (DELETE FROM t RETURNING *) SELECT * FROM a", nil, false},
+		{"select into", TypeMysql, "SELECT * INTO OUTFILE '/tmp/x' FROM t", nil, false},
+		{"empty", TypeMysql, " ; -- x\n", ErrEmptySql, false},
+		{"keyword in string", TypeSqlite, "SELECT 'delete; drop' FROM t", nil, true},
+		{"trailing comment", TypeSqlite, "SELECT 1; -- done", nil, true},
+		{"two statements", TypeSqlite, "SELECT 1; SELECT 2", ErrMultiStatement, false},
+
+		// 反斜杠仅在 mysql 字符串中为转义
+		{"backslash mysql", TypeMysql, `SELECT '\'; DELETE FROM t; COMMIT; SELECT '`, ErrMultiStatement, false},
+		{"backslash postgres", TypePostgres, `SELECT '\'; DELETE FROM t; COMMIT; SELECT '`, ErrMultiStatement, false},
+		{"backslash sqlite", TypeSqlite, `SELECT '\'; DELETE FROM t; COMMIT; SELECT '`, ErrMultiStatement, false},
+		{"backslash csv", TypeCsv, `SELECT '\'; DELETE FROM t; COMMIT; SELECT '`, ErrMultiStatement, false},
+		{"backslash escaped quote", TypeMysql, `SELECT 'it\'s' FROM t`, nil, true},
+		{"ansi quotes mysql", TypeMysql, `SELECT "\", '\' ; DELETE FROM t; -- ' "`, ErrMultiStatement, false},
+		{"identifier postgres", TypePostgres, `SELECT "\"; DELETE FROM t; -- "`, ErrMultiStatement, false},
+		{"e string postgres", TypePostgres, `SELECT E'\'' ; DELETE FROM t; -- '`, ErrMultiStatement, false},
+		{"e string hides nothing", TypePostgres, `SELECT E'\'; DELETE FROM t; -- '`, nil, true},
+
+		// # 仅在 mysql 中为注释
+		{"hash mysql", TypeMysql, "SELECT 1 # ; DELETE FROM t", nil, true},
+		{"hash postgres", TypePostgres, "SELECT 1 # 2; DELETE FROM t", ErrMultiStatement, false},
+		{"hash sqlite", TypeSqlite, "SELECT 1 #; DELETE FROM t", ErrMultiStatement, false},
+
+		// mysql 注释差异
+		{"mysql dash without space", TypeMysql, "SELECT 1 --1 INTO OUTFILE '/tmp/x'", nil, false},
+		{"mysql executable comment", TypeMysql, "SELECT 1 /*! INTO OUTFILE '/tmp/x' */", nil, false},
+		{"mysql executable multi", TypeMysql, "SELECT 1 /*!50000 ; DELETE FROM t */", ErrMultiStatement, false},
+		{"block comment", TypePostgres, "SELECT 1 /* ; DELETE FROM t */", nil, true},
+
+		// postgres $tag$ 字符串
+		{"dollar string", TypePostgres, "SELECT $$'$$; DELETE FROM t; -- '", ErrMultiStatement, false},
+		{"dollar tag", TypePostgres, "SELECT $a$ ; DELETE $a$ FROM t", nil, true},
+		{"dollar nested tag", TypePostgres, "SELECT $a$ $b$ ; $a$; DELETE FROM t", ErrMultiStatement, false},
+		{"dollar param", TypePostgres, "SELECT * FROM t WHERE id = $1", nil, true},
+
+		// sqlite [] 标识符
+		{"brackets sqlite", TypeSqlite, "SELECT [a']; DELETE FROM t; -- '", ErrMultiStatement, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt, err := ParseStatement(tc.dialect, tc.sql)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err == nil && stmt.ReadOnly != tc.readOnly {
+				t.Fatalf("readOnly = %v, want %v", stmt.ReadOnly, tc.readOnly)
+			}
+		})
+	}
+}
+
+func TestCheckParams(t *testing.T) {
+	cases := []struct {
+		typ    string
+		params string
+		ok     bool
+	}{
+		{TypeMysql, "charset=utf8mb4&parseTime=True", true},
+		{TypeMysql, "multiStatements=true", false},
+		{TypeMysql, "MULTISTATEMENTS=false", false},
+		{TypeMysql, "charset=utf8mb4&allowMultiQueries=true", false},
+		{TypePostgres, "sslmode=disable&default_query_exec_mode=exec", true},
+		{TypePostgres, "default_query_exec_mode=simple_protocol", false},
+		{TypePostgres, "default_query_exec_mode=exec&default_query_exec_mode=SIMPLE_PROTOCOL", false},
+		{TypeMysql, "a=%zz", false},
+	}
+	for _, tc := range cases {
+		_, err := checkParams(tc.typ, tc.params)
+		if (err == nil) != tc.ok {
+			t.Errorf("checkParams(%s, %q) err = %v, want ok %v", tc.typ, tc.params, err, tc.ok)
+		}
+	}
+}
+
+// TestReadOnlyConnection 只读数据源在连接层面拒绝写入 即使 SQL 校验被绕过
+func TestReadOnlyConnection(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data.db")
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Exec("CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Close()
+	if err = os.WriteFile(filepath.Join(dir, "t.csv"), []byte("id\n1\n2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		cfg  Config
+	}{
+		{"sqlite", Config{Type: TypeSqlite, Path: file, Params: "_pragma=query_only(0)"}},
+		{"csv", Config{Type: TypeCsv, Path: dir}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, readOnly := range []bool{true, false} {
+				tc.cfg.ReadOnly = readOnly
+				db, err := Open(tc.cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var count int
+				if err = db.QueryRow("SELECT COUNT(*) FROM t").Scan(&count); err != nil {
+					t.Fatal(err)
+				}
+				_, err = db.Exec("INSERT INTO t (id) VALUES (3)")
+				if readOnly && err == nil {
+					t.Error("write succeeded on read-only source")
+				}
+				if !readOnly && err != nil {
+					t.Errorf("write failed on writable source: %v", err)
+				}
+				_ = db.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package datasource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NamedParams SQL 中的命名参数(:name) 按首次出现顺序去重 忽略注释、字符串与 postgres 的 :: 类型转换
+func NamedParams(sql string) []string {
+	var names []string
+	seen := map[string]struct{}{}
+	replaceNamed(sql, func(name string) string {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+		return ""
+	})
+	return names
+}
+
+// BindNamed 将命名参数替换为驱动占位符 postgres 使用 $n, 其它使用 ?
+// 未提供值的参数返回错误 值为 nil 时按 NULL 处理
+func BindNamed(typ, sql string, values map[string]interface{}) (string, []interface{}, error) {
+	var (
+		args    []interface{}
+		missing []string
+		index   = map[string]int{}
+	)
+	bound := replaceNamed(sql, func(name string) string {
+		value, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+			return "?"
+		}
+		if typ == TypePostgres {
+			if i, ok := index[name]; ok {
+				return "$" + strconv.Itoa(i)
+			}
+			args = append(args, value)
+			index[name] = len(args)
+			return "$" + strconv.Itoa(len(args))
+		}
+		args = append(args, value)
+		return "?"
+	})
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("缺少参数: %s", strings.Join(missing, ", "))
+	}
+	return bound, args, nil
+}
+
+// replaceNamed 遍历 SQL 中的命名参数 用 fn 的返回值替换 注释与字符串原样保留
+func replaceNamed(sql string, fn func(name string) string) string {
+	runes := []rune(sql)
+	n := len(runes)
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		r := runes[i]
+		start := i
+		switch {
+		case r == '-' && i+1 < n && runes[i+1] == '-', r == '#':
+			for i+1 < n && runes[i+1] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < n && runes[i+1] == '*':
+			i += 2
+			for i+1 < n && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i = min(i+1, n-1)
+		case r == '\'' || r == '"' || r == '`':
+			for i++; i < n; i++ {
+				if runes[i] == '\\' && r != '"' {
+					i++
+					continue
+				}
+				if runes[i] == r {
+					if i+1 < n && runes[i+1] == r {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			i = min(i, n-1)
+		case r == ':' && i+1 < n && runes[i+1] == ':':
+			// postgres 类型转换 ::type
+			i++
+		case r == ':' && i+1 < n && (unicode.IsLetter(runes[i+1]) || runes[i+1] == '_'):
+			i++
+			for i+1 < n && (unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1]) || runes[i+1] == '_') {
+				i++
+			}
+			sb.WriteString(fn(string(runes[start+1 : i+1])))
+			continue
+		}
+		sb.WriteString(string(runes[start : i+1]))
+	}
+	return sb.String()
+}
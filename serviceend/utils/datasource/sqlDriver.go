@@ -0,0 +1,170 @@
+package datasource
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	defaultMaxOpenConns = 5
+	defaultMaxIdleConns = 2
+	defaultConnLifetime = 30 * time.Minute
+)
+
+func setupPool(db *sql.DB) *sql.DB {
+	db.SetMaxOpenConns(defaultMaxOpenConns)
+	db.SetMaxIdleConns(defaultMaxIdleConns)
+	db.SetConnMaxLifetime(defaultConnLifetime)
+	return db
+}
+
+func validateServer(cfg Config) error {
+	if cfg.Host == "" {
+		return errors.New("主机地址不能为空")
+	}
+	if cfg.Database == "" {
+		return errors.New("数据库名不能为空")
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return errors.New("端口号不正确")
+	}
+	return nil
+}
+
+// multiStatementParams 开启后一次可执行多条语句 会绕过只读校验 不允许在连接参数中设置
+// 键为小写参数名 值为禁止的取值 为空时禁止任意取值
+var multiStatementParams = map[string]map[string]string{
+	TypeMysql:    {"multistatements": "", "allowmultiqueries": ""},
+	TypePostgres: {"default_query_exec_mode": "simple_protocol"},
+}
+
+// checkParams 解析连接参数 拒绝开启多语句执行的参数
+func checkParams(typ, params string) (url.Values, error) {
+	values, err := url.ParseQuery(params)
+	if err != nil {
+		return nil, fmt.Errorf("连接参数格式错误: %w", err)
+	}
+	for key, list := range values {
+		forbidden, ok := multiStatementParams[typ][strings.ToLower(key)]
+		if !ok {
+			continue
+		}
+		for _, value := range list {
+			if forbidden == "" || strings.EqualFold(value, forbidden) {
+				return nil, fmt.Errorf("不允许设置连接参数 %s", key)
+			}
+		}
+	}
+	return values, nil
+}
+
+type mysqlDriver struct{}
+
+func (m mysqlDriver) Validate(cfg Config) error {
+	if _, err := checkParams(TypeMysql, cfg.Params); err != nil {
+		return err
+	}
+	return validateServer(cfg)
+}
+
+func (m mysqlDriver) Open(cfg Config) (*sql.DB, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = 3306
+	}
+	c := mysql.NewConfig()
+	c.User, c.Passwd, c.Net, c.DBName = cfg.Username, cfg.Password, "tcp", cfg.Database
+	c.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	c.ParseTime, c.Loc = true, time.Local
+	c.Timeout = 10 * time.Second
+	if cfg.Params != "" {
+		values, err := checkParams(TypeMysql, cfg.Params)
+		if err != nil {
+			return nil, err
+		}
+		c.Params = map[string]string{}
+		for key := range values {
+			c.Params[key] = values.Get(key)
+		}
+	}
+	db, err := sql.Open("mysql", c.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	return setupPool(db), nil
+}
+
+type postgresDriver struct{}
+
+func (p postgresDriver) Validate(cfg Config) error {
+	if _, err := checkParams(TypePostgres, cfg.Params); err != nil {
+		return err
+	}
+	return validateServer(cfg)
+}
+
+func (p postgresDriver) Open(cfg Config) (*sql.DB, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = 5432
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		Path:     "/" + cfg.Database,
+		RawQuery: cfg.Params,
+	}
+	if _, err := checkParams(TypePostgres, cfg.Params); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("pgx", u.String())
+	if err != nil {
+		return nil, err
+	}
+	return setupPool(db), nil
+}
+
+type sqliteDriver struct{}
+
+func (s sqliteDriver) Validate(cfg Config) error {
+	if cfg.Path == "" {
+		return errors.New("数据库文件路径不能为空")
+	}
+	return nil
+}
+
+// Open 仅打开已存在的数据库文件 避免路径填写错误时创建空库
+func (s sqliteDriver) Open(cfg Config) (*sql.DB, error) {
+	fi, err := os.Stat(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("数据库文件不存在: %s", cfg.Path)
+	}
+	if fi.IsDir() {
+		return nil, fmt.Errorf("数据库文件路径为目录: %s", cfg.Path)
+	}
+	dsn := cfg.Path + "?_pragma=busy_timeout(5000)"
+	if cfg.Params != "" {
+		dsn += "&" + cfg.Params
+	}
+	// 只读数据源追加在用户参数之后 按顺序执行的 pragma 以此为准
+	if cfg.ReadOnly {
+		dsn += "&_pragma=query_only(1)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return setupPool(db), nil
+}
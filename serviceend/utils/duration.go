@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration 在 time.ParseDuration 的基础上支持天(d) 如 7d、1d12h
+func ParseDuration(d string) (time.Duration, error) {
+	d = strings.TrimSpace(d)
+	dr, err := time.ParseDuration(d)
+	if err == nil {
+		return dr, nil
+	}
+	if strings.Contains(d, "d") {
+		index := strings.Index(d, "d")
+		hour, _ := strconv.Atoi(d[:index])
+		dr = time.Hour * 24 * time.Duration(hour)
+		if rest := d[index+1:]; rest != "" {
+			ndr, err := time.ParseDuration(rest)
+			if err != nil {
+				return dr, err
+			}
+			dr += ndr
+		}
+		return dr, nil
+	}
+
+	dv, err := strconv.ParseInt(d, 10, 64)
+	return time.Duration(dv), err
+}
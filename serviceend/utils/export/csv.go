@@ -0,0 +1,41 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// utf8Bom 使 Excel 以 UTF-8 打开 csv
+var utf8Bom = []byte{0xEF, 0xBB, 0xBF}
+
+type csvWriter struct {
+	w      io.Writer
+	csv    *csv.Writer
+	record []string
+}
+
+func newCsvWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	if _, err := c.w.Write(utf8Bom); err != nil {
+		return err
+	}
+	c.record = make([]string, len(columns))
+	return c.csv.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(row []interface{}) error {
+	for i, v := range row {
+		c.record[i] = text(v)
+	}
+	return c.csv.Write(c.record[:len(row)])
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+func (c *csvWriter) Abort() {}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	FormatCsv  = "csv"
+	FormatXlsx = "xlsx"
+	FormatJson = "json"
+)
+
+// Writer 逐行写出导出文件 Close 时写出剩余内容, 导出失败时调用 Abort 释放资源
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(row []interface{}) error
+	Close() error
+	Abort()
+}
+
+// NewWriter 按格式创建写出器
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCsv:
+		return newCsvWriter(w), nil
+	case FormatXlsx:
+		return newXlsxWriter(w)
+	case FormatJson:
+		return newJsonWriter(w), nil
+	default:
+		return nil, errors.New("不支持的导出格式: " + format)
+	}
+}
+
+// ContentType 导出格式对应的 MIME 类型
+func ContentType(format string) string {
+	switch format {
+	case FormatCsv:
+		return "text/csv; charset=utf-8"
+	case FormatXlsx:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// text 单元格文本 查询结果中的时间已格式化为字符串
+func text(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter 写出对象数组 每行一个对象, 字段顺序与列顺序一致
+type jsonWriter struct {
+	w     *bufio.Writer
+	keys  [][]byte
+	count int
+}
+
+func newJsonWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) WriteHeader(columns []string) error {
+	j.keys = make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = key
+	}
+	_, err := j.w.WriteString("[")
+	return err
+}
+
+func (j *jsonWriter) WriteRow(row []interface{}) error {
+	if j.count > 0 {
+		j.w.WriteString(",")
+	}
+	j.count++
+	j.w.WriteString("\n{")
+	for i, v := range row {
+		if i > 0 {
+			j.w.WriteString(",")
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		j.w.Write(j.keys[i])
+		j.w.WriteString(":")
+		if _, err = j.w.Write(value); err != nil {
+			return err
+		}
+	}
+	_, err := j.w.WriteString("}")
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if j.keys == nil {
+		j.w.WriteString("[")
+	}
+	if _, err := j.w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) Abort() {}
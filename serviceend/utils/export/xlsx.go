@@ -0,0 +1,79 @@
+package export
+
+import (
+	"errors"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxMaxRows Excel 单个工作表的行数上限(含表头)
+const xlsxMaxRows = 1048576
+
+// xlsxWriter 使用 excelize 流式写入 行数据超出内存阈值后暂存于临时文件, Close 时写出
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+	cells  []interface{}
+}
+
+func newXlsxWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{w: w, file: file, stream: stream}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(columns []string) error {
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	x.cells = make([]interface{}, len(columns))
+	return x.write(header)
+}
+
+func (x *xlsxWriter) WriteRow(row []interface{}) error {
+	for i, v := range row {
+		switch v.(type) {
+		case nil:
+			x.cells[i] = nil
+		case int64, int32, int, float64, float32, bool:
+			x.cells[i] = v
+		default:
+			x.cells[i] = text(v)
+		}
+	}
+	return x.write(x.cells[:len(row)])
+}
+
+func (x *xlsxWriter) write(cells []interface{}) error {
+	if x.row >= xlsxMaxRows {
+		return errors.New("超出 Excel 单个工作表的行数上限 1048576")
+	}
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, cells)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.w)
+	return err
+}
+
+// Abort 丢弃已写入的内容并删除临时文件
+func (x *xlsxWriter) Abort() {
+	_ = x.file.Close()
+}
@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// utf8Bom Excel 另存为 UTF-8 csv 时写入的 BOM
+var utf8Bom = []byte{0xEF, 0xBB, 0xBF}
+
+type csvReader struct {
+	csv *csv.Reader
+}
+
+// newCsvReader 去掉 BOM 并按首行识别分隔符(逗号、分号或制表符)
+func newCsvReader(r io.Reader) (*csvReader, error) {
+	buf := bufio.NewReader(r)
+	if head, _ := buf.Peek(len(utf8Bom)); bytes.Equal(head, utf8Bom) {
+		_, _ = buf.Discard(len(utf8Bom))
+	}
+	first, _ := buf.Peek(4096)
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+	reader := csv.NewReader(buf)
+	reader.Comma = ','
+	for _, sep := range []rune{';', '\t'} {
+		if bytes.Count(first, []byte(string(sep))) > bytes.Count(first, []byte(string(reader.Comma))) {
+			reader.Comma = sep
+		}
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return &csvReader{csv: reader}, nil
+}
+
+func (c *csvReader) Next() ([]string, int, error) {
+	for {
+		row, err := c.csv.Read()
+		if err != nil {
+			return nil, 0, err
+		}
+		if !blank(row) {
+			line, _ := c.csv.FieldPos(0)
+			return row, line, nil
+		}
+	}
+}
+
+func (c *csvReader) Sheets() []string {
+	return nil
+}
+
+func (c *csvReader) Close() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCsv  = "csv"
+	FormatXlsx = "xlsx"
+)
+
+// Reader 逐行读取导入文件 读完返回 io.EOF, 空行跳过
+type Reader interface {
+	// Next 下一行 line 为该行在文件中的行号(从 1 开始)
+	Next() (row []string, line int, err error)
+	// Sheets 工作表名称 csv 为空
+	Sheets() []string
+	Close() error
+}
+
+// Format 按扩展名判断文件格式
+func Format(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".txt":
+		return FormatCsv, nil
+	case ".xlsx", ".xlsm":
+		return FormatXlsx, nil
+	default:
+		return "", errors.New("只支持 csv 与 xlsx 文件")
+	}
+}
+
+// Open 打开导入文件 xlsx 读取指定工作表, 未指定时读取第一个
+func Open(format string, r io.Reader, sheet string) (Reader, error) {
+	switch format {
+	case FormatCsv:
+		return newCsvReader(r)
+	case FormatXlsx:
+		return newXlsxReader(r, sheet)
+	default:
+		return nil, errors.New("不支持的导入格式: " + format)
+	}
+}
+
+// blank 是否为空行
+func blank(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导入列的类型
+const (
+	TypeInteger  = "integer"
+	TypeReal     = "real"
+	TypeBoolean  = "boolean"
+	TypeDatetime = "datetime"
+	TypeText     = "text"
+)
+
+// timeLayouts 可识别的日期时间格式
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// Infer 按样本推断列类型 空值不参与判断, 没有非空值时为 text
+// 依次尝试 integer、real、boolean、datetime, 均不满足时为 text
+func Infer(values []string) string {
+	for _, typ := range []string{TypeInteger, TypeReal, TypeBoolean, TypeDatetime} {
+		matched, empty := true, true
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			empty = false
+			if _, err := Convert(typ, v); err != nil {
+				matched = false
+				break
+			}
+		}
+		if empty {
+			return TypeText
+		}
+		if matched {
+			return typ
+		}
+	}
+	return TypeText
+}
+
+// Convert 按列类型转换单元格 空值返回 nil
+func Convert(typ, v string) (interface{}, error) {
+	if typ != TypeText {
+		v = strings.TrimSpace(v)
+	}
+	if v == "" {
+		return nil, nil
+	}
+	switch typ {
+	case TypeInteger:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || leadingZero(v) {
+			return nil, errors.New("不是有效的整数")
+		}
+		return n, nil
+	case TypeReal:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || leadingZero(v) || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.New("不是有效的数字")
+		}
+		return f, nil
+	case TypeBoolean:
+		switch strings.ToLower(v) {
+		case "true", "是":
+			return true, nil
+		case "false", "否":
+			return false, nil
+		}
+		return nil, errors.New("不是有效的布尔值")
+	case TypeDatetime:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return nil, errors.New("不是有效的日期时间")
+	case TypeText:
+		return v, nil
+	}
+	return nil, errors.New("不支持的类型: " + typ)
+}
+
+// leadingZero 以 0 开头的编号(如 007) 按文本处理 避免丢失前导零
+func leadingZero(v string) bool {
+	v = strings.TrimPrefix(v, "-")
+	return len(v) > 1 && v[0] == '0' && v[1] != '.'
+}
@@ -0,0 +1,55 @@
+package importer
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+	line int
+}
+
+func newXlsxReader(r io.Reader, sheet string) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if sheet == "" {
+		sheet = file.GetSheetName(0)
+	}
+	rows, err := file.Rows(sheet)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &xlsxReader{file: file, rows: rows}, nil
+}
+
+func (x *xlsxReader) Sheets() []string {
+	return x.file.GetSheetList()
+}
+
+func (x *xlsxReader) Next() ([]string, int, error) {
+	for x.rows.Next() {
+		x.line++
+		row, err := x.rows.Columns()
+		if err != nil {
+			return nil, 0, err
+		}
+		if !blank(row) {
+			return row, x.line, nil
+		}
+	}
+	if err := x.rows.Error(); err != nil {
+		return nil, 0, err
+	}
+	return nil, 0, io.EOF
+}
+
+func (x *xlsxReader) Close() error {
+	_ = x.rows.Close()
+	return x.file.Close()
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"dataPanel/serviceend/global"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	TokenTypeAccess  = "access"  // 访问令牌
+	TokenTypeRefresh = "refresh" // 刷新令牌
+)
+
+var (
+	TokenExpired = errors.New("token is expired")
+	TokenInvalid = errors.New("couldn't handle this token")
+	NoSigningKey = errors.New("未配置令牌签名密钥 jwt.signing-key")
+)
+
+// CustomClaims 令牌载荷
+type CustomClaims struct {
+	UserId    uint   `json:"userId"`
+	Username  string `json:"username"`
+	NickName  string `json:"nickName"`
+	TokenType string `json:"tokenType"`
+	SessionId string `json:"sid"` // 登录会话ID
+	jwt.RegisteredClaims
+}
+
+type JWT struct {
+	SigningKey []byte
+}
+
+func NewJWT() *JWT {
+	return &JWT{
+		[]byte(global.GvaConfig.Jwt.SigningKey),
+	}
+}
+
+// CreateClaims 生成指定类型令牌的载荷 过期时间取自 jwt 配置
+func (j *JWT) CreateClaims(userId uint, username, nickName, tokenType, sessionId string) (CustomClaims, error) {
+	expires := global.GvaConfig.Jwt.ExpiresTime
+	if tokenType == TokenTypeRefresh {
+		expires = global.GvaConfig.Jwt.RefreshExpiresTime
+	}
+	ep, err := ParseDuration(expires)
+	if err != nil {
+		return CustomClaims{}, err
+	}
+	now := time.Now()
+	return CustomClaims{
+		UserId:    userId,
+		Username:  username,
+		NickName:  nickName,
+		TokenType: tokenType,
+		SessionId: sessionId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    global.GvaConfig.Jwt.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now.Add(-time.Second)), // 签名生效时间 容忍时钟误差
+			ExpiresAt: jwt.NewNumericDate(now.Add(ep)),
+		},
+	}, nil
+}
+
+// CreateToken 创建一个token
+func (j *JWT) CreateToken(claims CustomClaims) (string, error) {
+	if len(j.SigningKey) == 0 {
+		return "", NoSigningKey
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.SigningKey)
+}
+
+// ParseToken 解析 token
+func (j *JWT) ParseToken(tokenString string) (*CustomClaims, error) {
+	if len(j.SigningKey) == 0 {
+		return nil, TokenInvalid
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (i interface{}, e error) {
+		return j.SigningKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(global.GvaConfig.Jwt.Issuer))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, TokenExpired
+		}
+		return nil, TokenInvalid
+	}
+	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
+		return claims, nil
+	}
+	return nil, TokenInvalid
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"database/sql/driver"
+	"fmt"
 	"time"
 )
 
@@ -29,17 +30,48 @@ func (t LocalTime) MarshalJSON() ([]byte, error) {
 	return b, nil
 }
 
+// Value 以 time.Time 写入数据库, mysql/postgresql/sqlite 驱动均可原生处理
 func (t LocalTime) Value() (driver.Value, error) {
 	if t.String() == "0001-01-01 00:00:00" {
 		return nil, nil
 	}
-	return []byte(time.Time(t).Format(TimeFormat)), nil
+	return time.Time(t), nil
 }
 
+// scanLayouts 驱动以字符串返回时间时可能的格式
+var scanLayouts = []string{
+	TimeFormat,
+	"2006-01-02 15:04:05.999999999-07:00", // sqlite 默认存储格式
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+// Scan 兼容各驱动返回的 time.Time/[]byte/string
 func (t *LocalTime) Scan(v interface{}) error {
-	tTime, _ := time.Parse("2006-01-02 15:04:05 +0800 CST", v.(time.Time).String())
-	*t = LocalTime(tTime)
-	return nil
+	switch value := v.(type) {
+	case nil:
+		*t = LocalTime(time.Time{})
+		return nil
+	case time.Time:
+		*t = LocalTime(value.In(time.Local))
+		return nil
+	case []byte:
+		return t.parse(string(value))
+	case string:
+		return t.parse(value)
+	default:
+		return fmt.Errorf("LocalTime 不支持的类型: %T", v)
+	}
+}
+
+func (t *LocalTime) parse(value string) error {
+	for _, layout := range scanLayouts {
+		if tTime, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			*t = LocalTime(tTime.In(time.Local))
+			return nil
+		}
+	}
+	return fmt.Errorf("LocalTime 无法解析时间: %s", value)
 }
 
 func (t LocalTime) String() string {
@@ -0,0 +1,149 @@
+package logview
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dataPanel/serviceend/model/resModel"
+)
+
+// Levels 日志级别 与日志文件名前缀一致
+var Levels = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+
+// TimeLayout 日志中的时间格式 见 internal.Zap.CustomTimeEncoder, 旧版本写入的时间前可能带有配置的前缀
+const TimeLayout = "2006/01/02 - 15:04:05.000"
+
+var (
+	timePattern   = regexp.MustCompile(`\d{4}/\d{2}/\d{2} - \d{2}:\d{2}:\d{2}\.\d{3}`)
+	colorPattern  = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	callerPattern = regexp.MustCompile(`^\S+\.go:\d+$`)
+	logfmtPattern = regexp.MustCompile(`([^\s=]+)=("(?:[^"\\]|\\.)*"|\S*)`)
+)
+
+// Open 打开日志文件 .gz 文件解压读取
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return gzReader{Reader: gz, file: file}, nil
+}
+
+type gzReader struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzReader) Close() error {
+	_ = g.Reader.Close()
+	return g.file.Close()
+}
+
+// Parse 解析一行日志 支持 console、json 与 logfmt 格式, stackKey 为配置的堆栈字段名
+// 不是日志条目开头的行(如 console 格式的堆栈)返回 false
+func Parse(line, stackKey string) (entry resModel.LogEntry, at time.Time, ok bool) {
+	line = strings.TrimRight(colorPattern.ReplaceAllString(line, ""), "\r\n")
+	switch {
+	case strings.HasPrefix(line, "{"):
+		return parseJson(line, stackKey)
+	case strings.HasPrefix(line, "time="):
+		return parseLogfmt(line, stackKey)
+	default:
+		return parseConsole(line)
+	}
+}
+
+// parseConsole 时间\t级别\t[名称\t][调用位置\t]消息[\t字段JSON]
+func parseConsole(line string) (entry resModel.LogEntry, at time.Time, ok bool) {
+	parts := strings.Split(line, "\t")
+	if len(parts) < 3 {
+		return
+	}
+	if at, ok = parseTime(parts[0]); !ok {
+		return
+	}
+	entry.Time, entry.Level = at.Format(TimeLayout), strings.ToLower(parts[1])
+	rest := parts[2:]
+	if last := rest[len(rest)-1]; len(rest) > 1 && strings.HasPrefix(last, "{") {
+		var fields map[string]interface{}
+		if json.Unmarshal([]byte(last), &fields) == nil {
+			entry.Fields, rest = fields, rest[:len(rest)-1]
+		}
+	}
+	switch {
+	case len(rest) > 1 && callerPattern.MatchString(rest[0]):
+		entry.Caller, rest = rest[0], rest[1:]
+	case len(rest) > 2 && callerPattern.MatchString(rest[1]):
+		entry.Logger, entry.Caller, rest = rest[0], rest[1], rest[2:]
+	}
+	entry.Message = strings.Join(rest, "\t")
+	return entry, at, true
+}
+
+func parseJson(line, stackKey string) (entry resModel.LogEntry, at time.Time, ok bool) {
+	var fields map[string]interface{}
+	if json.Unmarshal([]byte(line), &fields) != nil {
+		return
+	}
+	return fromFields(fields, stackKey)
+}
+
+func parseLogfmt(line, stackKey string) (entry resModel.LogEntry, at time.Time, ok bool) {
+	fields := make(map[string]interface{})
+	for _, m := range logfmtPattern.FindAllStringSubmatch(line, -1) {
+		value := m[2]
+		if strings.HasPrefix(value, `"`) {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+		}
+		fields[m[1]] = value
+	}
+	return fromFields(fields, stackKey)
+}
+
+// fromFields 按 internal.Zap.GetEncoderConfig 的键名取出固定字段 其余作为附加字段
+func fromFields(fields map[string]interface{}, stackKey string) (entry resModel.LogEntry, at time.Time, ok bool) {
+	text := func(key string) string {
+		v, _ := fields[key].(string)
+		delete(fields, key)
+		return v
+	}
+	if at, ok = parseTime(text("time")); !ok {
+		return
+	}
+	entry.Time, entry.Level = at.Format(TimeLayout), strings.ToLower(text("level"))
+	entry.Logger, entry.Caller, entry.Message = text("logger"), text("caller"), text("message")
+	delete(fields, "prefix") // 配置的日志前缀 见 internal.PrefixKey
+	if stackKey != "" {
+		entry.Stack = text(stackKey)
+	}
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+	return entry, at, true
+}
+
+// parseTime 取出时间 忽略配置的前缀
+func parseTime(s string) (time.Time, bool) {
+	match := timePattern.FindString(s)
+	if match == "" {
+		return time.Time{}, false
+	}
+	at, err := time.ParseInLocation(TimeLayout, match, time.Local)
+	return at, err == nil
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"dataPanel/serviceend/utils"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File 每条通知以一行 JSON 追加写入本地文件
+type File struct {
+	path string
+}
+
+var fileLock sync.Mutex
+
+func NewFile(path string) File {
+	if path == "" {
+		path = "log/notify.log"
+	}
+	return File{path: path}
+}
+
+func (f File) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err = utils.CreateDir(filepath.Dir(f.path)); err != nil {
+		return err
+	}
+	fileLock.Lock()
+	defer fileLock.Unlock()
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"dataPanel/serviceend/model/configModel"
+	"errors"
+	"time"
+)
+
+const (
+	TypeWebhook = "webhook" // JSON POST 携带 HMAC 签名
+	TypeSmtp    = "smtp"    // 邮件
+	TypeFile    = "file"    // 追加写入本地文件
+)
+
+// Message 通知内容
+type Message struct {
+	Event   string      `json:"event"`   // 事件 如 alert.fire、job.failed
+	Level   string      `json:"level"`   // 级别 info|warning|error
+	Title   string      `json:"title"`   // 标题
+	Content string      `json:"content"` // 内容
+	Data    interface{} `json:"data"`    // 附加数据 如告警记录、任务执行记录
+	Time    time.Time   `json:"time"`    // 产生时间
+}
+
+// Channel 通知渠道 新增渠道时实现该接口并在 NewChannel 中注册
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewChannel 按配置创建通知渠道
+func NewChannel(cfg configModel.NotifyChannel) (Channel, error) {
+	switch cfg.Type {
+	case TypeWebhook:
+		if cfg.Url == "" {
+			return nil, errors.New("webhook 地址不能为空")
+		}
+		return NewWebhook(cfg.Url, cfg.Secret, cfg.Headers), nil
+	case TypeSmtp:
+		if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, errors.New("邮件服务器、发件人与收件人不能为空")
+		}
+		return NewSmtp(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From, cfg.To, cfg.Tls), nil
+	case TypeFile:
+		return NewFile(cfg.Path), nil
+	default:
+		return nil, errors.New("不支持的通知渠道类型: " + cfg.Type)
+	}
+}
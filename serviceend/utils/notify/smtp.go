@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"dataPanel/serviceend/utils"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TlsNone     = "none"     // 明文
+	TlsStartTls = "starttls" // 连接后升级 服务器不支持时报错
+	TlsSsl      = "ssl"      // 直接使用 TLS 连接 通常为 465 端口
+)
+
+// Smtp 通过 SMTP 发送纯文本邮件 未指定 tls 时 465 端口使用 ssl, 其它端口在服务器支持时升级 starttls
+type Smtp struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+	tls      string
+}
+
+func NewSmtp(host string, port int, username, password, from string, to []string, tls string) Smtp {
+	if port == 0 {
+		port = 25
+	}
+	return Smtp{host: host, port: port, username: username, password: password, from: from, to: to, tls: tls}
+}
+
+func (s Smtp) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: s.host}
+	if s.tls == TlsSsl || (s.tls == "" && s.port == 465) {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if _, isTls := conn.(*tls.Conn); !isTls && s.tls != TlsNone {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err = client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		} else if s.tls == TlsStartTls {
+			return fmt.Errorf("邮件服务器 %s 不支持 STARTTLS", addr)
+		}
+	}
+	if s.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err = client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(s.build(msg)); err != nil {
+		w.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// build 组装邮件 标题按 RFC 2047 编码, 正文 base64 编码
+func (s Smtp) build(msg Message) []byte {
+	body := msg.Content + "\r\n\r\n事件: " + msg.Event + "\r\n时间: " + msg.Time.Format(utils.TimeFormat) + "\r\n"
+	if msg.Data != nil {
+		if data, err := json.MarshalIndent(msg.Data, "", "  "); err == nil {
+			body += "\r\n" + string(data) + "\r\n"
+		}
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\nContent-Transfer-Encoding: base64\r\n\r\n")
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpServer 本地 SMTP 服务 只实现发送一封邮件所需的命令
+type smtpServer struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     string
+	done     chan struct{}
+}
+
+func newSmtpServer(t *testing.T, rejectRcpt bool) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener, done: make(chan struct{})}
+	t.Cleanup(func() { _ = listener.Close() })
+	go s.serve(rejectRcpt)
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve(rejectRcpt bool) {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			s.from = line[strings.Index(line, "<")+1 : strings.LastIndex(line, ">")]
+			reply("250 OK")
+		case "RCPT":
+			if rejectRcpt {
+				reply("550 mailbox unavailable")
+				continue
+			}
+			s.to = append(s.to, line[strings.Index(line, "<")+1:strings.LastIndex(line, ">")])
+			reply("250 OK")
+		case "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				l, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSmtpSend(t *testing.T) {
+	server := newSmtpServer(t, false)
+	to := []string{"ops@example.com", "dev@example.com"}
+	msg := Message{Event: "job.failed", Level: "error", Title: "任务执行失败: backup", Content: "连接超时",
+		Data: map[string]string{"name": "backup"}, Time: time.Now()}
+	err := NewSmtp("127.0.0.1", server.port(), "", "", "panel@example.com", to, TlsNone).Send(context.Background(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-server.done
+	if server.from != "panel@example.com" || strings.Join(server.to, ",") != strings.Join(to, ",") {
+		t.Fatalf("envelope from %s to %v", server.from, server.to)
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(server.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Title {
+		t.Errorf("subject = %q, err = %v", subject, err)
+	}
+	if parsed.Header.Get("To") != strings.Join(to, ", ") {
+		t.Errorf("to header = %q", parsed.Header.Get("To"))
+	}
+	raw := make([]byte, 4096)
+	n, _ := parsed.Body.Read(raw)
+	body, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(raw[:n]), "\r\n", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{msg.Content, "事件: job.failed", `"name": "backup"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body missing %q: %s", want, body)
+		}
+	}
+}
+
+func TestSmtpSendErrors(t *testing.T) {
+	server := newSmtpServer(t, true)
+	err := NewSmtp("127.0.0.1", server.port(), "", "", "panel@example.com", []string{"ops@example.com"}, TlsNone).
+		Send(context.Background(), Message{Title: "t"})
+	if err == nil || !strings.Contains(err.Error(), "550") {
+		t.Errorf("rejected recipient err = %v", err)
+	}
+
+	// 要求 STARTTLS 而服务器不支持
+	server = newSmtpServer(t, false)
+	err = NewSmtp("127.0.0.1", server.port(), "", "", "panel@example.com", []string{"ops@example.com"}, TlsStartTls).
+		Send(context.Background(), Message{Title: "t"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("starttls err = %v", err)
+	}
+
+	// 无人监听的端口
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	err = NewSmtp("127.0.0.1", port, "", "", "panel@example.com", []string{"ops@example.com"}, TlsNone).
+		Send(context.Background(), Message{Title: "t"})
+	if err == nil {
+		t.Errorf("send to closed port %s succeeded", strconv.Itoa(port))
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderTimestamp = "X-DataPanel-Timestamp" // 发送时间戳(秒)
+	HeaderSignature = "X-DataPanel-Signature" // sha256=HMAC-SHA256(secret, 时间戳 + "." + 请求体) 的十六进制
+)
+
+// Webhook 以 JSON POST 推送 配置了密钥时携带签名, 接收方按相同方式计算后比对
+type Webhook struct {
+	url     string
+	secret  string
+	headers map[string]string
+}
+
+func NewWebhook(url, secret string, headers map[string]string) Webhook {
+	return Webhook{url: url, secret: secret, headers: headers}
+}
+
+func (w Webhook) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	if w.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, "sha256="+Sign(w.secret, timestamp, body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("webhook 响应状态 %d: %s", resp.StatusCode, bytes.TrimSpace(text))
+	}
+	return nil
+}
+
+// Sign 计算 webhook 签名
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSend(t *testing.T) {
+	cases := []struct {
+		name   string
+		secret string
+		status int
+		ok     bool
+	}{
+		{"signed", "s3cret", http.StatusOK, true},
+		{"unsigned", "", http.StatusNoContent, true},
+		{"server error", "s3cret", http.StatusInternalServerError, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			msg := Message{Event: "alert.fire", Level: "error", Title: "磁盘告警", Content: "使用率 95%", Time: time.Now()}
+			err := NewWebhook(server.URL, tc.secret, map[string]string{"X-Extra": "1"}).Send(context.Background(), msg)
+			if (err == nil) != tc.ok {
+				t.Fatalf("err = %v, want ok %v", err, tc.ok)
+			}
+			if got.Method != http.MethodPost || got.Header.Get("Content-Type") != "application/json" || got.Header.Get("X-Extra") != "1" {
+				t.Errorf("unexpected request %s %v", got.Method, got.Header)
+			}
+			var decoded Message
+			if err = json.Unmarshal(body, &decoded); err != nil || decoded.Title != msg.Title {
+				t.Errorf("body = %s, err = %v", body, err)
+			}
+			timestamp, signature := got.Header.Get(HeaderTimestamp), got.Header.Get(HeaderSignature)
+			if tc.secret == "" {
+				if timestamp != "" || signature != "" {
+					t.Errorf("unsigned request carries signature headers")
+				}
+				return
+			}
+			if want := "sha256=" + Sign(tc.secret, timestamp, body); signature != want {
+				t.Errorf("signature = %s, want %s", signature, want)
+			}
+			if signature == "sha256="+Sign("other", timestamp, body) {
+				t.Errorf("signature does not depend on secret")
+			}
+		})
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := NewWebhook(server.URL, "", nil).Send(ctx, Message{})
+	if err == nil || !strings.Contains(err.Error(), "deadline") {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,51 @@
+package sender
+
+import (
+	"dataPanel/serviceend/global"
+	"dataPanel/serviceend/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LogSender 将消息写入日志 用于开发调试
+type LogSender struct{}
+
+func (l LogSender) Send(msg Message) error {
+	global.GvaLog.Info("模拟发送消息", zap.String("channel", msg.Channel), zap.String("target", msg.Target),
+		zap.String("subject", msg.Subject), zap.String("content", msg.Content))
+	return nil
+}
+
+// FileSender 将消息追加写入本地文件 便于离线测试时查看验证码
+type FileSender struct {
+	path string
+}
+
+var fileSenderLock sync.Mutex
+
+func NewFileSender(path string) FileSender {
+	if path == "" {
+		path = "log/verification.log"
+	}
+	return FileSender{path: path}
+}
+
+func (f FileSender) Send(msg Message) error {
+	if err := utils.CreateDir(filepath.Dir(f.path)); err != nil {
+		return err
+	}
+	fileSenderLock.Lock()
+	defer fileSenderLock.Unlock()
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\t%s\t%s\n", time.Now().Format(utils.TimeFormat), msg.Channel, msg.Target, msg.Subject, msg.Content)
+	return err
+}
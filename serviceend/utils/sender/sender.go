@@ -0,0 +1,43 @@
+package sender
+
+import (
+	"dataPanel/serviceend/global"
+	"errors"
+)
+
+const (
+	ChannelSms   = "sms"   // 短信
+	ChannelEmail = "email" // 邮件
+
+	SenderLog  = "log"  // 仅写日志
+	SenderFile = "file" // 写入本地文件
+)
+
+// Message 待发送的消息
+type Message struct {
+	Channel string // sms|email
+	Target  string // 手机号/邮箱
+	Subject string // 标题 邮件使用
+	Content string // 内容
+}
+
+// Sender 短信/邮件发送 接入第三方服务时实现该接口并在 NewSender 中注册
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSender 按 verification.sender 创建发送器 未配置时仅写日志
+func NewSender() (Sender, error) {
+	cfg := global.GvaConfig.Verification
+	if cfg == nil {
+		return LogSender{}, nil
+	}
+	switch cfg.Sender {
+	case SenderLog, "":
+		return LogSender{}, nil
+	case SenderFile:
+		return NewFileSender(cfg.FilePath), nil
+	default:
+		return nil, errors.New("不支持的发送方式: " + cfg.Sender)
+	}
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "unicode/utf8"
+
+// Truncate 截取不超过 maxLen 字节的前缀 不截断多字节字符
+func Truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	for maxLen > 0 && !utf8.RuneStart(s[maxLen]) {
+		maxLen--
+	}
+	return s[:maxLen]
+}
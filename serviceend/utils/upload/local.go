@@ -0,0 +1,122 @@
+package upload
+
+import (
+	"dataPanel/serviceend/utils"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local 本地磁盘存储 对象保存在 root 目录下
+type Local struct {
+	root string
+}
+
+// NewLocal 创建本地磁盘存储 目录不存在时自动创建
+func NewLocal(root string) (*Local, error) {
+	if root == "" {
+		root = "uploads"
+	}
+	if err := utils.CreateDir(root); err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Local{root: abs}, nil
+}
+
+func (l *Local) Type() string {
+	return StorageLocal
+}
+
+func (l *Local) Put(key string, r io.Reader) (int64, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err = utils.CreateDir(filepath.Dir(name)); err != nil {
+		return 0, err
+	}
+	// 先写临时文件再重命名 避免中断时留下残缺文件
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return n, err
+	}
+	return n, nil
+}
+
+func (l *Local) Open(key string) (io.ReadSeekCloser, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	return f, err
+}
+
+func (l *Local) Stat(key string) (ObjectInfo, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && fi.IsDir()) {
+		return ObjectInfo{}, ErrNotExist
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+func (l *Local) Delete(key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) DeletePrefix(prefix string) error {
+	name, err := l.path(prefix)
+	if err != nil {
+		return err
+	}
+	if name == l.root {
+		return errors.New("不允许清空存储根目录")
+	}
+	return os.RemoveAll(name)
+}
+
+// path 将 key 转换为 root 下的绝对路径 拒绝越出 root 的路径
+func (l *Local) path(key string) (string, error) {
+	clean := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))
+	name := filepath.Join(l.root, filepath.FromSlash(clean))
+	if name != l.root && !strings.HasPrefix(name, l.root+string(filepath.Separator)) {
+		return "", errors.New("非法的文件路径: " + key)
+	}
+	return name, nil
+}
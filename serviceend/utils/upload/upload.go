@@ -0,0 +1,53 @@
+package upload
+
+import (
+	"dataPanel/serviceend/global"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	StorageLocal = "local" // 本地磁盘
+)
+
+// ErrNotExist 存储中不存在该对象
+var ErrNotExist = errors.New("文件不存在")
+
+// ObjectInfo 存储对象信息
+type ObjectInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage 文件存储后端 key 为以 / 分隔的相对路径
+// 新增存储方式时实现该接口并在 NewStorage 中注册
+type Storage interface {
+	// Type 存储方式 写入文件记录用于定位存储后端
+	Type() string
+	// Put 写入对象 返回写入字节数 写入失败时不保留残缺对象
+	Put(key string, r io.Reader) (int64, error)
+	// Open 打开对象 返回值支持 Seek 用于断点续传下载
+	Open(key string) (io.ReadSeekCloser, error)
+	// Stat 对象信息 不存在时返回 ErrNotExist
+	Stat(key string) (ObjectInfo, error)
+	// Delete 删除对象 不存在时忽略
+	Delete(key string) error
+	// DeletePrefix 删除前缀下的全部对象 用于清理分片
+	DeletePrefix(prefix string) error
+}
+
+// NewStorage 按 file.storage-type 创建存储后端 未配置时使用本地磁盘
+func NewStorage() (Storage, error) {
+	cfg := global.GvaConfig.File
+	if cfg == nil {
+		return NewLocal("uploads")
+	}
+	switch cfg.StorageType {
+	case StorageLocal, "":
+		return NewLocal(cfg.Path)
+	default:
+		return nil, errors.New("不支持的存储方式: " + cfg.StorageType)
+	}
+}
@@ -0,0 +1,85 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// AlertWails 告警规则与告警记录 暴露给wails 告警触发通过 alert:fire 事件推送
+type AlertWails struct {
+	ctx context.Context
+}
+
+var alertService = service.ServiceGroupApp.AlertService
+
+func NewAlertWails() *AlertWails {
+	return &AlertWails{}
+}
+
+func (a *AlertWails) SetCtx(ctx context.Context) *AlertWails {
+	a.ctx = ctx
+	return a
+}
+
+// List 告警规则列表
+func (a *AlertWails) List(req reqModel.AlertRuleListReq) response.Response {
+	return response.Wrap(alertService.List(req))
+}
+
+// Get 告警规则详情
+func (a *AlertWails) Get(id uint) response.Response {
+	return response.Wrap(alertService.Get(id))
+}
+
+// Create 新增告警规则
+func (a *AlertWails) Create(req reqModel.AlertRuleReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	req.ID = 0
+	return response.Wrap(alertService.Save(0, req))
+}
+
+// Update 修改告警规则
+func (a *AlertWails) Update(req reqModel.AlertRuleReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	if req.ID == 0 {
+		return response.Wrap(nil, ApiReturn.ErrParam)
+	}
+	return response.Wrap(alertService.Save(0, req))
+}
+
+// Delete 删除告警规则
+func (a *AlertWails) Delete(ids []uint) response.Response {
+	return response.Wrap(nil, alertService.Delete(ids))
+}
+
+// Silence 静默告警规则
+func (a *AlertWails) Silence(req reqModel.AlertSilenceReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(alertService.Silence(req))
+}
+
+// Events 告警记录
+func (a *AlertWails) Events(req reqModel.AlertEventListReq) response.Response {
+	return response.Wrap(alertService.Events(req))
+}
+
+// Ack 确认告警
+func (a *AlertWails) Ack(ids []uint) response.Response {
+	return response.Wrap(nil, alertService.Ack(0, ids))
+}
+
+// Unacked 未确认告警数量
+func (a *AlertWails) Unacked() response.Response {
+	return response.Wrap(alertService.Unacked())
+}
@@ -0,0 +1,65 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/service"
+	"dataPanel/serviceend/utils"
+	"errors"
+	"sync/atomic"
+)
+
+// AuthWails 桌面窗口的登录身份 前端登录或刷新令牌后调用 SetToken, 其它 wails 方法以此记录操作用户
+type AuthWails struct {
+	ctx context.Context
+}
+
+var wailsClaims atomic.Pointer[utils.CustomClaims]
+
+func NewAuthWails() *AuthWails {
+	return &AuthWails{}
+}
+
+func (a *AuthWails) SetCtx(ctx context.Context) *AuthWails {
+	a.ctx = ctx
+	return a
+}
+
+// SetToken 设置当前登录用户的访问令牌
+func (a *AuthWails) SetToken(token string) response.Response {
+	claims, err := utils.NewJWT().ParseToken(token)
+	if err != nil {
+		if errors.Is(err, utils.TokenExpired) {
+			return response.Wrap(nil, ApiReturn.LoginExpired)
+		}
+		return response.Wrap(nil, ApiReturn.UnauthorizedAccess)
+	}
+	if claims.TokenType != utils.TokenTypeAccess {
+		return response.Wrap(nil, ApiReturn.UnauthorizedAccess)
+	}
+	if !activeClaims(claims) {
+		return response.Wrap(nil, ApiReturn.LoginExpired)
+	}
+	wailsClaims.Store(claims)
+	return response.Wrap(nil, nil)
+}
+
+// ClearToken 退出登录后清除
+func (a *AuthWails) ClearToken() response.Response {
+	wailsClaims.Store(nil)
+	return response.Wrap(nil, nil)
+}
+
+// currentUserId 桌面窗口当前登录的用户 未登录或会话已失效时返回 0
+func currentUserId() uint {
+	if claims := wailsClaims.Load(); claims != nil && activeClaims(claims) {
+		return claims.UserId
+	}
+	return 0
+}
+
+// activeClaims 会话未退出、未被强制下线
+func activeClaims(claims *utils.CustomClaims) bool {
+	return !service.ServiceGroupApp.JwtService.IsBlacklist(claims.ID) && service.ServiceGroupApp.SessionService.IsActive(claims.SessionId)
+}
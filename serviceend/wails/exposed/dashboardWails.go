@@ -0,0 +1,88 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// DashboardWails 仪表盘 暴露给wails 返回结构与 http 接口一致
+type DashboardWails struct {
+	ctx context.Context
+}
+
+var (
+	dashboardService     = service.ServiceGroupApp.DashboardService
+	widgetRefreshService = service.ServiceGroupApp.WidgetRefreshService
+)
+
+func NewDashboardWails() *DashboardWails {
+	return &DashboardWails{}
+}
+
+func (d *DashboardWails) SetCtx(ctx context.Context) *DashboardWails {
+	d.ctx = ctx
+	return d
+}
+
+// List 仪表盘列表
+func (d *DashboardWails) List(req reqModel.PageInfo) response.Response {
+	return response.Wrap(dashboardService.List(req))
+}
+
+// Get 仪表盘详情
+func (d *DashboardWails) Get(id uint) response.Response {
+	return response.Wrap(dashboardService.Get(id))
+}
+
+// Create 新增仪表盘
+func (d *DashboardWails) Create(req reqModel.DashboardReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	req.ID = 0
+	return response.Wrap(dashboardService.Save(0, req))
+}
+
+// Update 保存仪表盘 生成新版本
+func (d *DashboardWails) Update(req reqModel.DashboardReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	if req.ID == 0 {
+		return response.Wrap(nil, ApiReturn.ErrParam)
+	}
+	return response.Wrap(dashboardService.Save(0, req))
+}
+
+// Delete 删除仪表盘
+func (d *DashboardWails) Delete(ids []uint) response.Response {
+	return response.Wrap(nil, dashboardService.Delete(ids))
+}
+
+// Versions 历史版本
+func (d *DashboardWails) Versions(id uint) response.Response {
+	return response.Wrap(dashboardService.Versions(id))
+}
+
+// Restore 恢复历史版本
+func (d *DashboardWails) Restore(req reqModel.DashboardRestoreReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(dashboardService.Restore(0, req))
+}
+
+// Data 各组件最近结果 自动刷新的组件返回缓存, 之后的变化通过 widget:update 事件推送
+func (d *DashboardWails) Data(id uint) response.Response {
+	return response.Wrap(widgetRefreshService.Latest(0, id))
+}
+
+// WidgetData 组件数据
+func (d *DashboardWails) WidgetData(widgetId uint) response.Response {
+	return response.Wrap(dashboardService.WidgetData(0, widgetId))
+}
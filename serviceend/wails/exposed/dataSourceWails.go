@@ -0,0 +1,87 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// DataSourceWails 数据源管理 暴露给wails 返回结构与 http 接口一致
+type DataSourceWails struct {
+	ctx context.Context
+}
+
+var dataSourceService = service.ServiceGroupApp.DataSourceService
+
+func NewDataSourceWails() *DataSourceWails {
+	return &DataSourceWails{}
+}
+
+func (d *DataSourceWails) SetCtx(ctx context.Context) *DataSourceWails {
+	d.ctx = ctx
+	return d
+}
+
+// Types 支持的数据源类型
+func (d *DataSourceWails) Types() response.Response {
+	return response.Wrap(dataSourceService.Types(), nil)
+}
+
+// List 数据源列表
+func (d *DataSourceWails) List(req reqModel.DataSourceListReq) response.Response {
+	return response.Wrap(dataSourceService.List(req))
+}
+
+// Get 数据源详情
+func (d *DataSourceWails) Get(id uint) response.Response {
+	return response.Wrap(dataSourceService.Get(id))
+}
+
+// Create 新增数据源
+func (d *DataSourceWails) Create(req reqModel.DataSourceReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	req.ID = 0
+	return response.Wrap(dataSourceService.Save(currentUserId(), req))
+}
+
+// Update 修改数据源
+func (d *DataSourceWails) Update(req reqModel.DataSourceReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	if req.ID == 0 {
+		return response.Wrap(nil, ApiReturn.ErrParam)
+	}
+	return response.Wrap(dataSourceService.Save(currentUserId(), req))
+}
+
+// Delete 删除数据源
+func (d *DataSourceWails) Delete(ids []uint) response.Response {
+	return response.Wrap(nil, dataSourceService.Delete(ids))
+}
+
+// Test 测试连接参数
+func (d *DataSourceWails) Test(req reqModel.DataSourceReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(dataSourceService.Test(d.context(), req))
+}
+
+// TestById 测试已保存的数据源
+func (d *DataSourceWails) TestById(id uint) response.Response {
+	return response.Wrap(dataSourceService.TestById(d.context(), id))
+}
+
+func (d *DataSourceWails) context() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
@@ -0,0 +1,76 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/model/resModel"
+	"dataPanel/serviceend/service"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ExportWails 导出查询结果 暴露给wails 通过系统保存对话框选择保存位置
+type ExportWails struct {
+	ctx context.Context
+}
+
+var exportService = service.ServiceGroupApp.ExportService
+
+func NewExportWails() *ExportWails {
+	return &ExportWails{}
+}
+
+func (e *ExportWails) SetCtx(ctx context.Context) *ExportWails {
+	e.ctx = ctx
+	return e
+}
+
+// Export 选择保存位置并导出 取消保存时 Path 为空
+func (e *ExportWails) Export(req reqModel.ExportReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	export, err := exportService.Prepare(0, req)
+	if err != nil {
+		return response.Wrap(nil, err)
+	}
+	path, err := runtime.SaveFileDialog(e.ctx, runtime.SaveDialogOptions{
+		DefaultFilename: export.FileName,
+		Filters: []runtime.FileFilter{
+			{DisplayName: strings.ToUpper(req.Format) + " (*." + req.Format + ")", Pattern: "*." + req.Format},
+		},
+	})
+	if err != nil {
+		return response.Wrap(nil, ApiReturn.ExportFailed.WithData(err.Error()))
+	}
+	if path == "" {
+		return response.Wrap(resModel.ExportResult{}, nil)
+	}
+	if filepath.Ext(path) == "" {
+		path += "." + req.Format
+	}
+	return response.Wrap(e.save(export, path))
+}
+
+// save 写入文件 失败时删除未写完的文件
+func (e *ExportWails) save(export *service.Export, path string) (res resModel.ExportResult, err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return res, ApiReturn.ExportFailed.WithData(err.Error())
+	}
+	rows, err := export.Write(file)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = ApiReturn.ExportFailed.WithData(closeErr.Error())
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return res, err
+	}
+	return resModel.ExportResult{Path: path, Rows: rows}, nil
+}
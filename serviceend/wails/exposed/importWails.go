@@ -0,0 +1,63 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ImportWails 导入 csv/xlsx 到本地数据库 暴露给wails 可直接选择本地文件
+type ImportWails struct {
+	ctx context.Context
+}
+
+var importService = service.ServiceGroupApp.ImportService
+
+func NewImportWails() *ImportWails {
+	return &ImportWails{}
+}
+
+func (i *ImportWails) SetCtx(ctx context.Context) *ImportWails {
+	i.ctx = ctx
+	return i
+}
+
+// PickFile 打开系统文件对话框选择导入文件 取消时返回空路径
+func (i *ImportWails) PickFile() response.Response {
+	path, err := runtime.OpenFileDialog(i.ctx, runtime.OpenDialogOptions{
+		Title: "选择导入文件",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV/Excel (*.csv;*.xlsx)", Pattern: "*.csv;*.txt;*.xlsx;*.xlsm"},
+		},
+	})
+	if err != nil {
+		return response.Wrap(nil, ApiReturn.ImportFileInvalid.WithData(err.Error()))
+	}
+	return response.Wrap(path, nil)
+}
+
+// Tables 可导入的表
+func (i *ImportWails) Tables() response.Response {
+	return response.Wrap(importService.Tables())
+}
+
+// Preview 预览文件并推断列类型
+func (i *ImportWails) Preview(req reqModel.ImportPreviewReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(importService.Preview(req))
+}
+
+// Import 导入
+func (i *ImportWails) Import(req reqModel.ImportReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(importService.Import(0, req))
+}
@@ -0,0 +1,49 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+)
+
+// JobWails 定时任务 暴露给wails 返回结构与 http 接口一致
+type JobWails struct {
+	ctx context.Context
+}
+
+var jobService = service.ServiceGroupApp.JobService
+
+func NewJobWails() *JobWails {
+	return &JobWails{}
+}
+
+func (j *JobWails) SetCtx(ctx context.Context) *JobWails {
+	j.ctx = ctx
+	return j
+}
+
+// List 已注册的任务
+func (j *JobWails) List() response.Response {
+	return response.Wrap(jobService.List())
+}
+
+// Trigger 手动执行
+func (j *JobWails) Trigger(name string) response.Response {
+	return response.Wrap(nil, jobService.Trigger(name))
+}
+
+// Pause 暂停计划执行
+func (j *JobWails) Pause(name string) response.Response {
+	return response.Wrap(nil, jobService.Pause(name))
+}
+
+// Resume 恢复计划执行
+func (j *JobWails) Resume(name string) response.Response {
+	return response.Wrap(nil, jobService.Resume(name))
+}
+
+// Logs 执行记录
+func (j *JobWails) Logs(req reqModel.JobRunListReq) response.Response {
+	return response.Wrap(jobService.Logs(req))
+}
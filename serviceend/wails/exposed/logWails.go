@@ -0,0 +1,69 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// LogWails 运行日志 暴露给wails 返回结构与 http 接口一致
+type LogWails struct {
+	ctx context.Context
+}
+
+var logService = service.ServiceGroupApp.LogService
+
+func NewLogWails() *LogWails {
+	return &LogWails{}
+}
+
+func (l *LogWails) SetCtx(ctx context.Context) *LogWails {
+	l.ctx = ctx
+	return l
+}
+
+// Level 当前日志级别
+func (l *LogWails) Level() response.Response {
+	return response.Wrap(logService.Level(), nil)
+}
+
+// SetLevel 修改日志级别 可指定到期自动恢复
+func (l *LogWails) SetLevel(req reqModel.LogLevelReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(logService.SetLevel(req))
+}
+
+// Files 日志文件列表
+func (l *LogWails) Files(req reqModel.LogFileListReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(logService.Files(req))
+}
+
+// Search 搜索日志
+func (l *LogWails) Search(req reqModel.LogSearchReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(logService.Search(req))
+}
+
+// StartTail 开始实时跟踪 新日志以 log:tail 事件推送
+func (l *LogWails) StartTail(req reqModel.LogFilterReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(nil, logService.StartTail(req))
+}
+
+// StopTail 停止实时跟踪
+func (l *LogWails) StopTail() response.Response {
+	logService.StopTail()
+	return response.Wrap(nil, nil)
+}
@@ -0,0 +1,39 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+)
+
+// NotifyWails 通知渠道与投递记录 暴露给wails 返回结构与 http 接口一致
+type NotifyWails struct {
+	ctx context.Context
+}
+
+var notifyService = service.ServiceGroupApp.NotifyService
+
+func NewNotifyWails() *NotifyWails {
+	return &NotifyWails{}
+}
+
+func (n *NotifyWails) SetCtx(ctx context.Context) *NotifyWails {
+	n.ctx = ctx
+	return n
+}
+
+// Channels 已配置的通知渠道
+func (n *NotifyWails) Channels() response.Response {
+	return response.Wrap(notifyService.Channels())
+}
+
+// Test 发送测试通知
+func (n *NotifyWails) Test(name string) response.Response {
+	return response.Wrap(nil, notifyService.Test(name))
+}
+
+// Logs 投递记录
+func (n *NotifyWails) Logs(req reqModel.NotifyLogListReq) response.Response {
+	return response.Wrap(notifyService.Logs(req))
+}
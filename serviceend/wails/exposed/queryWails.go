@@ -0,0 +1,44 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// QueryWails 即席查询 暴露给wails 结果通过 query:chunk/query:done 事件推送
+type QueryWails struct {
+	ctx context.Context
+}
+
+var queryService = service.ServiceGroupApp.QueryService
+
+func NewQueryWails() *QueryWails {
+	return &QueryWails{}
+}
+
+func (q *QueryWails) SetCtx(ctx context.Context) *QueryWails {
+	q.ctx = ctx
+	return q
+}
+
+// Run 后台执行查询 返回查询ID
+func (q *QueryWails) Run(req reqModel.QueryRunReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(queryService.RunAsync(0, req))
+}
+
+// Cancel 取消查询
+func (q *QueryWails) Cancel(queryId string) response.Response {
+	return response.Wrap(nil, queryService.Cancel(0, queryId))
+}
+
+// Logs 查询执行记录
+func (q *QueryWails) Logs(req reqModel.QueryLogListReq) response.Response {
+	return response.Wrap(queryService.Logs(req))
+}
@@ -0,0 +1,70 @@
+package exposed
+
+import (
+	"context"
+	"dataPanel/serviceend/common/ApiReturn"
+	"dataPanel/serviceend/common/response"
+	"dataPanel/serviceend/model/reqModel"
+	"dataPanel/serviceend/service"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// SavedQueryWails 保存的查询 暴露给wails 执行结果通过 query:chunk/query:done 事件推送
+type SavedQueryWails struct {
+	ctx context.Context
+}
+
+var savedQueryService = service.ServiceGroupApp.SavedQueryService
+
+func NewSavedQueryWails() *SavedQueryWails {
+	return &SavedQueryWails{}
+}
+
+func (s *SavedQueryWails) SetCtx(ctx context.Context) *SavedQueryWails {
+	s.ctx = ctx
+	return s
+}
+
+// List 保存的查询列表
+func (s *SavedQueryWails) List(req reqModel.SavedQueryListReq) response.Response {
+	return response.Wrap(savedQueryService.List(req))
+}
+
+// Get 保存的查询详情
+func (s *SavedQueryWails) Get(id uint) response.Response {
+	return response.Wrap(savedQueryService.Get(id))
+}
+
+// Create 新增保存的查询
+func (s *SavedQueryWails) Create(req reqModel.SavedQueryReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	req.ID = 0
+	return response.Wrap(savedQueryService.Save(0, req))
+}
+
+// Update 修改保存的查询
+func (s *SavedQueryWails) Update(req reqModel.SavedQueryReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	if req.ID == 0 {
+		return response.Wrap(nil, ApiReturn.ErrParam)
+	}
+	return response.Wrap(savedQueryService.Save(0, req))
+}
+
+// Delete 删除保存的查询
+func (s *SavedQueryWails) Delete(ids []uint) response.Response {
+	return response.Wrap(nil, savedQueryService.Delete(ids))
+}
+
+// Execute 后台执行保存的查询 返回查询ID
+func (s *SavedQueryWails) Execute(id uint, req reqModel.SavedQueryExecReq) response.Response {
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return response.Wrap(nil, err)
+	}
+	return response.Wrap(savedQueryService.ExecuteAsync(0, id, req))
+}
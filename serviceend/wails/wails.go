@@ -26,6 +26,17 @@ var assets embed.FS
 func Run() {
 	app := code.NewApp()
 	helloWails := exposed.NewHelloWails()
+	dataSourceWails := exposed.NewDataSourceWails()
+	queryWails := exposed.NewQueryWails()
+	savedQueryWails := exposed.NewSavedQueryWails()
+	dashboardWails := exposed.NewDashboardWails()
+	jobWails := exposed.NewJobWails()
+	alertWails := exposed.NewAlertWails()
+	notifyWails := exposed.NewNotifyWails()
+	exportWails := exposed.NewExportWails()
+	importWails := exposed.NewImportWails()
+	logWails := exposed.NewLogWails()
+	authWails := exposed.NewAuthWails()
 	// 设置菜单项 无边框状态下，快捷键可用
 	AppMenu := menu.NewMenu()
 	FileMenu := AppMenu.AddSubmenu("应用设置")
@@ -55,6 +66,17 @@ func Run() {
 		OnStartup: func(ctx context.Context) {
 			app.Startup(ctx)
 			helloWails.SetCtx(ctx)
+			dataSourceWails.SetCtx(ctx)
+			queryWails.SetCtx(ctx)
+			savedQueryWails.SetCtx(ctx)
+			dashboardWails.SetCtx(ctx)
+			jobWails.SetCtx(ctx)
+			alertWails.SetCtx(ctx)
+			notifyWails.SetCtx(ctx)
+			exportWails.SetCtx(ctx)
+			importWails.SetCtx(ctx)
+			logWails.SetCtx(ctx)
+			authWails.SetCtx(ctx)
 		},
 		OnDomReady:    app.DomReady,
 		OnBeforeClose: app.BeforeClose,
@@ -70,6 +92,17 @@ func Run() {
 		Bind: []interface{}{
 			app,
 			helloWails,
+			dataSourceWails,
+			queryWails,
+			savedQueryWails,
+			dashboardWails,
+			jobWails,
+			alertWails,
+			notifyWails,
+			exportWails,
+			importWails,
+			logWails,
+			authWails,
 		},
 		WindowStartState: options.Normal,
 		Windows: &windows.Options{